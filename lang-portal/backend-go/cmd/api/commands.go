@@ -0,0 +1,53 @@
+package main
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"log/slog"
+	"strings"
+
+	"github.com/souheilbenslama/free-genai-bootcamp-2025/lang-portal/backend-go/internal/repository/sqlite"
+)
+
+const commandUsage = "promote-admin EMAIL, adopt-sessions EMAIL"
+
+// runCommand runs a one-off maintenance command against the migrated
+// database instead of starting the server.
+func runCommand(ctx context.Context, db *sql.DB, command []string) error {
+	switch {
+	case command[0] == "promote-admin" && len(command) == 2:
+		// Registration is open, so only an account that already exists and
+		// was vouched for by the operator becomes an admin
+		email := strings.ToLower(strings.TrimSpace(command[1]))
+		user, err := sqlite.NewUserRepository(db).PromoteAdmin(ctx, email)
+		if errors.Is(err, sql.ErrNoRows) {
+			return fmt.Errorf("no account is registered with %s", email)
+		}
+		if err != nil {
+			return fmt.Errorf("failed to promote %s: %w", email, err)
+		}
+		slog.Info("account promoted to admin", slog.Int("user_id", user.ID), slog.String("email", user.Email))
+		return nil
+
+	case command[0] == "adopt-sessions" && len(command) == 2:
+		// Sessions recorded before accounts existed have no owner and show up
+		// on no dashboard until an account adopts them
+		email := strings.ToLower(strings.TrimSpace(command[1]))
+		user, err := sqlite.NewUserRepository(db).GetUserByEmail(ctx, email)
+		if errors.Is(err, sql.ErrNoRows) {
+			return fmt.Errorf("no account is registered with %s", email)
+		}
+		if err != nil {
+			return fmt.Errorf("failed to find %s: %w", email, err)
+		}
+		adopted, err := sqlite.NewStudyRepository(db).AdoptLegacySessions(ctx, user.ID)
+		if err != nil {
+			return err
+		}
+		slog.Info("study sessions adopted", slog.Int("user_id", user.ID), slog.Int("sessions", adopted))
+		return nil
+	}
+	return fmt.Errorf("unknown command %q, commands are: %s", strings.Join(command, " "), commandUsage)
+}
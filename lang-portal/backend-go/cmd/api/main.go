@@ -1,89 +1,219 @@
 package main
 
 import (
-	"database/sql"
+	"context"
+	"crypto/rand"
+	"errors"
+	"flag"
 	"fmt"
-	"log"
+	"io/fs"
+	"log/slog"
 	"os"
-	"path/filepath"
-	"runtime"
+	"os/signal"
+	"strings"
+	"syscall"
+	"time"
+	// Time zones of learners resolve without zoneinfo on the host
+	_ "time/tzdata"
 
 	"github.com/gin-gonic/gin"
-	_ "github.com/mattn/go-sqlite3"
+	"go.opentelemetry.io/contrib/instrumentation/github.com/gin-gonic/gin/otelgin"
+
+	"github.com/souheilbenslama/free-genai-bootcamp-2025/lang-portal/backend-go/database"
 	"github.com/souheilbenslama/free-genai-bootcamp-2025/lang-portal/backend-go/internal/api/handlers"
+	"github.com/souheilbenslama/free-genai-bootcamp-2025/lang-portal/backend-go/internal/api/middleware"
 	"github.com/souheilbenslama/free-genai-bootcamp-2025/lang-portal/backend-go/internal/api/routes"
+	"github.com/souheilbenslama/free-genai-bootcamp-2025/lang-portal/backend-go/internal/auth"
+	"github.com/souheilbenslama/free-genai-bootcamp-2025/lang-portal/backend-go/internal/config"
+	"github.com/souheilbenslama/free-genai-bootcamp-2025/lang-portal/backend-go/internal/logging"
+	"github.com/souheilbenslama/free-genai-bootcamp-2025/lang-portal/backend-go/internal/metrics"
+	"github.com/souheilbenslama/free-genai-bootcamp-2025/lang-portal/backend-go/internal/migrate"
+	"github.com/souheilbenslama/free-genai-bootcamp-2025/lang-portal/backend-go/internal/ratelimit"
 	"github.com/souheilbenslama/free-genai-bootcamp-2025/lang-portal/backend-go/internal/repository/sqlite"
 	"github.com/souheilbenslama/free-genai-bootcamp-2025/lang-portal/backend-go/internal/seeder"
+	"github.com/souheilbenslama/free-genai-bootcamp-2025/lang-portal/backend-go/internal/server"
+	"github.com/souheilbenslama/free-genai-bootcamp-2025/lang-portal/backend-go/internal/spa"
+	"github.com/souheilbenslama/free-genai-bootcamp-2025/lang-portal/backend-go/internal/streak"
+	"github.com/souheilbenslama/free-genai-bootcamp-2025/lang-portal/backend-go/internal/study"
+	"github.com/souheilbenslama/free-genai-bootcamp-2025/lang-portal/backend-go/internal/tracing"
+	"github.com/souheilbenslama/free-genai-bootcamp-2025/lang-portal/backend-go/web"
 )
 
-// getProjectRoot returns the absolute path to the project root directory
-func getProjectRoot() (string, error) {
-	_, filename, _, ok := runtime.Caller(0)
-	if !ok {
-		return "", fmt.Errorf("failed to get current file path")
+func main() {
+	// Exiting only here lets the deferred cleanup of run flush traces and
+	// close the database first
+	if err := run(os.Args[1:]); err != nil {
+		slog.Error("exiting", slog.Any("error", err))
+		os.Exit(1)
 	}
-	// Go up two directories from cmd/api/main.go to reach project root
-	projectRoot := filepath.Join(filepath.Dir(filename), "..", "..")
-	return filepath.Abs(projectRoot)
 }
 
-func main() {
-	// Get the project root directory
-	projectRoot, err := getProjectRoot()
+func run(args []string) error {
+	// Leading words name a one-off command to run instead of the server
+	var command []string
+	for len(args) > 0 && !strings.HasPrefix(args[0], "-") {
+		command, args = append(command, args[0]), args[1:]
+	}
+
+	cfg, err := config.Load(args, os.Getenv, os.Stderr)
+	if errors.Is(err, flag.ErrHelp) {
+		return nil
+	}
 	if err != nil {
-		log.Fatal("Failed to get project root:", err)
+		return fmt.Errorf("invalid configuration: %w", err)
+	}
+
+	logger := logging.New(cfg.LogLevel, os.Stdout)
+	slog.SetDefault(logger)
+	slog.Info("effective configuration", slog.Any("config", cfg))
+
+	shutdownTracing, err := tracing.Setup(context.Background(), cfg)
+	if err != nil {
+		return fmt.Errorf("failed to set up tracing: %w", err)
+	}
+	defer func() {
+		ctx, cancel := context.WithTimeout(context.Background(), cfg.ShutdownTimeout)
+		defer cancel()
+		if err := shutdownTracing(ctx); err != nil {
+			slog.Error("failed to flush traces", slog.Any("error", err))
+		}
+	}()
+
+	if cfg.LogLevel != "debug" {
+		gin.SetMode(gin.ReleaseMode)
 	}
 
 	// Initialize SQLite database
-	dbPath := filepath.Join(projectRoot, "words.db")
-	db, err := sql.Open("sqlite3", dbPath)
+	db, err := sqlite.Open(cfg.DBPath)
 	if err != nil {
-		log.Fatal("Failed to connect to database:", err)
+		return fmt.Errorf("failed to connect to database: %w", err)
 	}
+	// The server closes the database once drained; this covers the errors
+	// before it runs
 	defer db.Close()
 
+	if err := metrics.RegisterDB(db, "main"); err != nil {
+		return fmt.Errorf("failed to register database metrics: %w", err)
+	}
+
 	// Apply migrations
-	migrationPath := filepath.Join(projectRoot, "database", "migrations", "001_initial_schema.sql")
-	log.Printf("Looking for migration file at: %s", migrationPath)
-	
-	migrationSQL, err := os.ReadFile(migrationPath)
+	var migrations fs.FS = database.Migrations()
+	if cfg.MigrationsDir != "" {
+		migrations = os.DirFS(cfg.MigrationsDir)
+	}
+
+	applied, err := migrate.Apply(context.Background(), db, migrations)
 	if err != nil {
-		log.Fatal("Failed to read migration file:", err)
+		return fmt.Errorf("failed to apply migrations: %w", err)
 	}
+	slog.Info("migrations applied", slog.Any("versions", applied))
 
-	if _, err := db.Exec(string(migrationSQL)); err != nil {
-		log.Fatal("Failed to apply migrations:", err)
+	if len(command) > 0 {
+		return runCommand(context.Background(), db, command)
 	}
 
 	// Load seed data from JSON files
-	seedDir := filepath.Join(projectRoot, "database", "seed")
-	log.Printf("Loading seed data from directory: %s", seedDir)
-	
-	if err := seeder.LoadSeedData(db, seedDir); err != nil {
-		log.Fatal("Failed to load seed data:", err)
-	}
+	if cfg.Seed {
+		var seed fs.FS = database.Seed()
+		if cfg.SeedDir != "" {
+			seed = os.DirFS(cfg.SeedDir)
+		}
 
-	log.Println("Database initialized with seed data")
+		loaded, err := seeder.LoadSeedFS(db, seed)
+		if err != nil {
+			return fmt.Errorf("failed to load seed data: %w", err)
+		}
+		if loaded {
+			slog.Info("database initialized with seed data")
+		} else {
+			slog.Info("seed data already loaded, skipping")
+		}
+	}
 
 	// Initialize repositories
 	wordRepo := sqlite.NewWordRepository(db)
 	groupRepo := sqlite.NewGroupRepository(db)
 	studyRepo := sqlite.NewStudyRepository(db)
+	userRepo := sqlite.NewUserRepository(db)
+	apiKeyRepo := sqlite.NewAPIKeyRepository(db)
+
+	secret := []byte(cfg.AuthSecret)
+	if len(secret) == 0 {
+		secret = make([]byte, 32)
+		if _, err := rand.Read(secret); err != nil {
+			return fmt.Errorf("failed to generate auth secret: %w", err)
+		}
+		slog.Warn("auth_secret is not set, using a random secret: tokens will not survive a restart")
+	}
+	tokens := auth.NewTokens(secret, cfg.AccessTokenTTL, cfg.RefreshTokenTTL)
 
 	// Initialize handlers
 	wordHandler := handlers.NewWordHandler(wordRepo)
 	groupHandler := handlers.NewGroupHandler(groupRepo)
-	studyHandler := handlers.NewStudyHandler(studyRepo)
+	studyHandler := handlers.NewStudyHandler(studyRepo, streak.NewService(studyRepo, nil))
+	questionHandler := handlers.NewQuestionHandler(studyRepo, groupRepo)
+	answerHandler := handlers.NewAnswerHandler(studyRepo, cfg.GradingTolerance)
+	healthHandler := handlers.NewHealthHandler(db, migrations, cfg.Seed)
+	authHandler := handlers.NewAuthHandler(userRepo, tokens)
+	adminHandler := handlers.NewAdminHandler(userRepo, apiKeyRepo)
+
+	var frontend fs.FS = web.Dist()
+	if cfg.WebDir != "" {
+		frontend = os.DirFS(cfg.WebDir)
+	}
 
 	// Initialize Gin router
-	r := gin.Default()
+	r := gin.New()
+	if err := r.SetTrustedProxies(cfg.TrustedProxies); err != nil {
+		return fmt.Errorf("invalid trusted proxies: %w", err)
+	}
+	r.Use(
+		otelgin.Middleware(tracing.ServiceName, otelgin.WithFilter(tracing.Traced)),
+		middleware.RequestID(),
+		middleware.Logger(logger),
+		middleware.Recovery(logger),
+		middleware.CORS(middleware.CORSPolicy{
+			AllowedOrigins:   cfg.CORSOrigins,
+			AllowedMethods:   cfg.CORSMethods,
+			AllowedHeaders:   cfg.CORSHeaders,
+			ExposedHeaders:   middleware.ExposedHeaders,
+			AllowCredentials: cfg.CORSCredentials,
+			MaxAge:           cfg.CORSMaxAge,
+		}),
+	)
 
 	// Setup routes
-	routes.SetupRoutes(r, wordHandler, groupHandler, studyHandler)
+	routes.SetupRoutes(r, routes.Handlers{
+		Word:        wordHandler,
+		Group:       groupHandler,
+		Study:       studyHandler,
+		Question:    questionHandler,
+		Answer:      answerHandler,
+		Health:      healthHandler,
+		Auth:        authHandler,
+		Admin:       adminHandler,
+		RequireUser: middleware.Authenticate(tokens, apiKeyRepo),
+		Limits: routes.Limits{
+			MaxBodyBytes: cfg.MaxBodyBytes,
+			Default:      ratelimit.New("default", cfg.RateLimitDefault),
+			Auth:         ratelimit.New("auth", cfg.RateLimitAuth),
+			Reviews:      ratelimit.New("reviews", cfg.RateLimitReviews),
+		},
+		Frontend: spa.Handler(frontend),
+	})
+
+	// Serve until SIGINT or SIGTERM, then drain and close the database
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
 
-	// Start server
-	log.Printf("Server starting on :8080... (Project root: %s)", projectRoot)
-	if err := r.Run(":8080"); err != nil {
-		log.Fatal("Failed to start server:", err)
+	srv := server.New(cfg, r, db)
+	if cfg.SessionIdleTimeout > 0 {
+		// Check often enough that sessions end close to the timeout
+		interval := min(cfg.SessionIdleTimeout/2, time.Minute)
+		srv.AddWorker("abandon-idle-sessions", study.AbandonIdle(studyRepo, cfg.SessionIdleTimeout, interval))
+	}
+	if err := srv.Run(ctx); err != nil {
+		return fmt.Errorf("server error: %w", err)
 	}
+	return nil
 }
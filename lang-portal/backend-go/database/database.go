@@ -0,0 +1,26 @@
+// Package database embeds the SQL migrations and seed data so a built binary
+// does not depend on the source tree it was compiled from.
+package database
+
+import (
+	"embed"
+	"io/fs"
+)
+
+//go:embed migrations/*.sql
+var migrations embed.FS
+
+//go:embed seed/*.json
+var seed embed.FS
+
+// Migrations returns the embedded migration files.
+func Migrations() fs.FS {
+	sub, _ := fs.Sub(migrations, "migrations")
+	return sub
+}
+
+// Seed returns the embedded seed files.
+func Seed() fs.FS {
+	sub, _ := fs.Sub(seed, "seed")
+	return sub
+}
@@ -0,0 +1,11 @@
+-- Record which seed data has been loaded so restarts do not duplicate it
+CREATE TABLE IF NOT EXISTS seed_runs (
+    checksum TEXT PRIMARY KEY,
+    applied_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+-- Databases seeded before seed runs were recorded count as seeded, so the
+-- first start after the upgrade does not load the seed data a second time
+INSERT INTO seed_runs (checksum)
+SELECT 'before-seed-runs'
+WHERE EXISTS (SELECT 1 FROM words) OR EXISTS (SELECT 1 FROM groups);
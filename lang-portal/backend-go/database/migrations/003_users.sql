@@ -0,0 +1,27 @@
+-- Learner accounts; words and groups stay shared between all users
+CREATE TABLE IF NOT EXISTS users (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    email TEXT NOT NULL UNIQUE,
+    name TEXT NOT NULL,
+    password_hash TEXT NOT NULL,
+    created_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+-- Refresh tokens are stored as SHA-256 hashes and revoked when used
+CREATE TABLE IF NOT EXISTS refresh_tokens (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    user_id INTEGER NOT NULL,
+    token_hash TEXT NOT NULL UNIQUE,
+    expires_at DATETIME NOT NULL,
+    revoked_at DATETIME,
+    created_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    FOREIGN KEY (user_id) REFERENCES users(id)
+);
+
+-- Study sessions belong to a user; sessions recorded before accounts existed
+-- keep a NULL owner and are not shown to anyone until an account adopts them
+-- with the adopt-sessions command
+ALTER TABLE study_sessions ADD COLUMN user_id INTEGER REFERENCES users(id);
+
+CREATE INDEX IF NOT EXISTS idx_study_sessions_user ON study_sessions (user_id, id);
+CREATE INDEX IF NOT EXISTS idx_refresh_tokens_user ON refresh_tokens (user_id);
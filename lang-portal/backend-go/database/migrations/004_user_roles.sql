@@ -0,0 +1,4 @@
+-- Roles decide who may manage shared content and accounts; everyone starts
+-- as a learner
+ALTER TABLE users ADD COLUMN role TEXT NOT NULL DEFAULT 'learner'
+    CHECK (role IN ('admin', 'teacher', 'learner'));
@@ -0,0 +1,16 @@
+-- API keys let other apps act for a user without a login. Keys are stored as
+-- SHA-256 hashes; prefix keeps the first characters so admins can tell them
+-- apart. scopes is a space separated list.
+CREATE TABLE IF NOT EXISTS api_keys (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    name TEXT NOT NULL,
+    prefix TEXT NOT NULL,
+    key_hash TEXT NOT NULL UNIQUE,
+    scopes TEXT NOT NULL,
+    user_id INTEGER NOT NULL,
+    expires_at DATETIME,
+    last_used_at DATETIME,
+    revoked_at DATETIME,
+    created_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    FOREIGN KEY (user_id) REFERENCES users(id)
+);
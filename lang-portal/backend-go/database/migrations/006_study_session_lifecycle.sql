@@ -0,0 +1,15 @@
+-- Sessions stay active until the learner finishes them (completed) or they
+-- sit idle for too long (abandoned); ended_at is set either way
+ALTER TABLE study_sessions ADD COLUMN state TEXT NOT NULL DEFAULT 'active'
+    CHECK (state IN ('active', 'completed', 'abandoned'));
+ALTER TABLE study_sessions ADD COLUMN ended_at DATETIME;
+
+-- Idleness is measured from the start of a session or its latest review
+ALTER TABLE study_sessions ADD COLUMN last_activity_at DATETIME;
+
+UPDATE study_sessions SET last_activity_at = COALESCE(
+    (SELECT MAX(created_at) FROM word_review_items WHERE study_session_id = study_sessions.id),
+    created_at
+);
+
+CREATE INDEX IF NOT EXISTS idx_study_sessions_active ON study_sessions (state, last_activity_at);
@@ -0,0 +1,15 @@
+-- Words issued to a study session by GET /api/study_sessions/{id}/next;
+-- reviews are only accepted for words their session issued for the drill
+-- they answer. A word is issued once per drill in a session.
+CREATE TABLE IF NOT EXISTS study_session_items (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    study_session_id INTEGER NOT NULL,
+    word_id INTEGER NOT NULL,
+    drill TEXT NOT NULL,
+    strategy TEXT NOT NULL,
+    issued_at DATETIME NOT NULL,
+    FOREIGN KEY (study_session_id) REFERENCES study_sessions(id),
+    FOREIGN KEY (word_id) REFERENCES words(id)
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_study_session_items_word ON study_session_items (study_session_id, word_id, drill);
@@ -0,0 +1,6 @@
+-- Options offered by a multiple choice question about an issued word, as
+-- space separated word IDs; NULL for words issued without one
+ALTER TABLE study_session_items ADD COLUMN options TEXT;
+
+-- The option picked when answering a multiple choice question
+ALTER TABLE word_review_items ADD COLUMN chosen_word_id INTEGER REFERENCES words(id);
@@ -0,0 +1,16 @@
+-- Answers of the article drill, kept apart from the meaning reviews in
+-- word_review_items. article is the article of the noun when it was
+-- answered, chosen_article the one the learner picked.
+CREATE TABLE IF NOT EXISTS article_reviews (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    study_session_id INTEGER NOT NULL,
+    word_id INTEGER NOT NULL,
+    article TEXT NOT NULL,
+    chosen_article TEXT NOT NULL,
+    correct BOOLEAN NOT NULL,
+    created_at DATETIME NOT NULL,
+    FOREIGN KEY (study_session_id) REFERENCES study_sessions(id),
+    FOREIGN KEY (word_id) REFERENCES words(id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_article_reviews_session ON article_reviews (study_session_id);
@@ -0,0 +1,18 @@
+-- Answers of the plural drill. plural is the plural of the noun when it was
+-- answered and pattern the way it is formed; answer_pattern is the way the
+-- typed answer formed it, which tells what kind of mistake was made.
+CREATE TABLE IF NOT EXISTS plural_reviews (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    study_session_id INTEGER NOT NULL,
+    word_id INTEGER NOT NULL,
+    answer TEXT NOT NULL,
+    plural TEXT NOT NULL,
+    pattern TEXT NOT NULL,
+    answer_pattern TEXT NOT NULL,
+    correct BOOLEAN NOT NULL,
+    created_at DATETIME NOT NULL,
+    FOREIGN KEY (study_session_id) REFERENCES study_sessions(id),
+    FOREIGN KEY (word_id) REFERENCES words(id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_plural_reviews_session ON plural_reviews (study_session_id);
@@ -0,0 +1,6 @@
+-- The verdict on a review: correct, almost (a typed answer with small
+-- mistakes) or wrong. Reviews before typed answers were graded are correct
+-- or wrong.
+ALTER TABLE word_review_items ADD COLUMN verdict TEXT;
+
+UPDATE word_review_items SET verdict = CASE WHEN correct THEN 'correct' ELSE 'wrong' END;
@@ -0,0 +1,33 @@
+-- Reviews become an append-only log of attempts: the primary key on
+-- (word_id, study_session_id) allowed one review per word and session. Each
+-- attempt is numbered per word and session and may record the submitted and
+-- expected answers, the response time and the direction asked. Existing
+-- reviews are kept as first attempts.
+CREATE TABLE word_review_attempts (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    word_id INTEGER NOT NULL,
+    study_session_id INTEGER NOT NULL,
+    attempt INTEGER NOT NULL,
+    correct BOOLEAN NOT NULL,
+    verdict TEXT,
+    chosen_word_id INTEGER,
+    answer TEXT,
+    expected TEXT,
+    response_ms INTEGER,
+    direction TEXT CHECK (direction IN ('de_en', 'en_de')),
+    created_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    FOREIGN KEY (word_id) REFERENCES words(id),
+    FOREIGN KEY (study_session_id) REFERENCES study_sessions(id),
+    FOREIGN KEY (chosen_word_id) REFERENCES words(id)
+);
+
+INSERT INTO word_review_attempts (word_id, study_session_id, attempt, correct, verdict, chosen_word_id, created_at)
+SELECT word_id, study_session_id, 1, correct, verdict, chosen_word_id, created_at
+FROM word_review_items
+ORDER BY created_at;
+
+DROP TABLE word_review_items;
+ALTER TABLE word_review_attempts RENAME TO word_review_items;
+
+CREATE UNIQUE INDEX idx_word_review_items_attempt ON word_review_items (study_session_id, word_id, attempt);
+CREATE INDEX idx_word_review_items_word ON word_review_items (word_id);
@@ -0,0 +1,2 @@
+-- The IANA time zone of a learner decides which day their study counts for
+ALTER TABLE users ADD COLUMN timezone TEXT NOT NULL DEFAULT 'UTC';
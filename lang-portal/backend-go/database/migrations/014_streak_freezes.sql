@@ -0,0 +1,10 @@
+-- A streak freeze keeps the study streak of a learner alive over a day
+-- without study. day is the date in the time zone of the learner.
+CREATE TABLE streak_freezes (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    user_id INTEGER NOT NULL,
+    day TEXT NOT NULL,
+    created_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    FOREIGN KEY (user_id) REFERENCES users(id),
+    UNIQUE (user_id, day)
+);
@@ -0,0 +1,195 @@
+package handlers
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/souheilbenslama/free-genai-bootcamp-2025/lang-portal/backend-go/internal/auth"
+	"github.com/souheilbenslama/free-genai-bootcamp-2025/lang-portal/backend-go/internal/models"
+	"github.com/souheilbenslama/free-genai-bootcamp-2025/lang-portal/backend-go/internal/repository"
+)
+
+// AdminHandler manages user accounts and API keys. Its routes require the
+// users:manage permission.
+type AdminHandler struct {
+	users repository.UserRepository
+	keys  repository.APIKeyRepository
+}
+
+func NewAdminHandler(users repository.UserRepository, keys repository.APIKeyRepository) *AdminHandler {
+	return &AdminHandler{users: users, keys: keys}
+}
+
+func (h *AdminHandler) ListUsers(c *gin.Context) {
+	params, ok := parsePagination(c)
+	if !ok {
+		return
+	}
+
+	users, page, err := h.users.ListUsers(c.Request.Context(), params)
+	if err != nil {
+		internalError(c, err)
+		return
+	}
+
+	respondPage(c, users, page)
+}
+
+type SetRoleRequest struct {
+	Role string `json:"role" binding:"required"`
+}
+
+// SetUserRole changes the role of a user. The new role applies to the user's
+// next login or token refresh.
+func (h *AdminHandler) SetUserRole(c *gin.Context) {
+	userID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid user ID"})
+		return
+	}
+
+	var req SetRoleRequest
+	if !bindJSON(c, &req) {
+		return
+	}
+
+	role, err := auth.ParseRole(req.Role)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	user, err := h.users.SetRole(c.Request.Context(), userID, string(role))
+	if err == sql.ErrNoRows {
+		c.JSON(http.StatusNotFound, gin.H{"error": "user not found"})
+		return
+	}
+	if errors.Is(err, repository.ErrLastAdmin) {
+		c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+		return
+	}
+	if err != nil {
+		internalError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, user)
+}
+
+type CreateAPIKeyRequest struct {
+	Name   string   `json:"name" binding:"required"`
+	Scopes []string `json:"scopes" binding:"required,min=1"`
+	// UserID is the user the key acts for; it defaults to the caller.
+	UserID    int        `json:"user_id"`
+	ExpiresAt *time.Time `json:"expires_at"`
+}
+
+// CreatedAPIKey is the only response that carries the key itself.
+type CreatedAPIKey struct {
+	models.APIKey
+	Key string `json:"key"`
+}
+
+// CreateAPIKey mints a key for an integration. The key can only use scopes
+// whose permissions the owner's role holds.
+func (h *AdminHandler) CreateAPIKey(c *gin.Context) {
+	callerID, ok := currentUserID(c)
+	if !ok {
+		return
+	}
+
+	var req CreateAPIKeyRequest
+	if !bindJSON(c, &req) {
+		return
+	}
+
+	scopes, err := auth.ParseScopes(req.Scopes)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if req.ExpiresAt != nil && !req.ExpiresAt.After(time.Now()) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "expires_at must be in the future"})
+		return
+	}
+	if req.UserID == 0 {
+		req.UserID = callerID
+	}
+
+	owner, err := h.users.GetUserByID(c.Request.Context(), req.UserID)
+	if err == sql.ErrNoRows {
+		c.JSON(http.StatusBadRequest, gin.H{"error": fmt.Sprintf("user %d does not exist", req.UserID)})
+		return
+	}
+	if err != nil {
+		internalError(c, err)
+		return
+	}
+
+	key := &models.APIKey{Name: req.Name, UserID: owner.ID, ExpiresAt: req.ExpiresAt}
+	for _, scope := range scopes {
+		for _, perm := range scope.Permissions() {
+			if !auth.Role(owner.Role).Can(perm) {
+				c.JSON(http.StatusBadRequest, gin.H{"error": fmt.Sprintf("role %s of user %d cannot use scope %s", owner.Role, owner.ID, scope)})
+				return
+			}
+		}
+		key.Scopes = append(key.Scopes, string(scope))
+	}
+
+	secret, prefix, hash, err := auth.NewAPIKey()
+	if err != nil {
+		internalError(c, err)
+		return
+	}
+	key.Prefix = prefix
+
+	if err := h.keys.CreateAPIKey(c.Request.Context(), key, hash); err != nil {
+		internalError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, CreatedAPIKey{APIKey: *key, Key: secret})
+}
+
+func (h *AdminHandler) ListAPIKeys(c *gin.Context) {
+	params, ok := parsePagination(c)
+	if !ok {
+		return
+	}
+
+	keys, page, err := h.keys.ListAPIKeys(c.Request.Context(), params)
+	if err != nil {
+		internalError(c, err)
+		return
+	}
+
+	respondPage(c, keys, page)
+}
+
+// RevokeAPIKey stops a key from authenticating. Requests already in flight
+// finish; the next one is answered with 401.
+func (h *AdminHandler) RevokeAPIKey(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid API key ID"})
+		return
+	}
+
+	key, err := h.keys.RevokeAPIKey(c.Request.Context(), id)
+	if err == sql.ErrNoRows {
+		c.JSON(http.StatusNotFound, gin.H{"error": "API key not found"})
+		return
+	}
+	if err != nil {
+		internalError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, key)
+}
@@ -0,0 +1,81 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/souheilbenslama/free-genai-bootcamp-2025/lang-portal/backend-go/internal/grading"
+	"github.com/souheilbenslama/free-genai-bootcamp-2025/lang-portal/backend-go/internal/metrics"
+	"github.com/souheilbenslama/free-genai-bootcamp-2025/lang-portal/backend-go/internal/models"
+	"github.com/souheilbenslama/free-genai-bootcamp-2025/lang-portal/backend-go/internal/quiz"
+	"github.com/souheilbenslama/free-genai-bootcamp-2025/lang-portal/backend-go/internal/repository"
+)
+
+type AnswerHandler struct {
+	sessions repository.StudySessionRepository
+	grader   grading.Grader
+}
+
+// NewAnswerHandler grades answers that are off by up to tolerance letters as
+// almost correct.
+func NewAnswerHandler(sessions repository.StudySessionRepository, tolerance int) *AnswerHandler {
+	return &AnswerHandler{sessions: sessions, grader: grading.Grader{Tolerance: tolerance}}
+}
+
+// The length cap keeps grading, which is quadratic in it, cheap.
+type AnswerRequest struct {
+	WordID     int    `json:"word_id" binding:"required"`
+	Answer     string `json:"answer" binding:"required,max=200"`
+	ResponseMS *int   `json:"response_ms" binding:"omitempty,min=0"`
+}
+
+// RecordAnswer grades the German the caller typed for a word the session
+// issued, with its article for nouns, records the verdict as an attempt at
+// the word and answers with it.
+func (h *AnswerHandler) RecordAnswer(c *gin.Context) {
+	userID, ok := currentUserID(c)
+	if !ok {
+		return
+	}
+
+	sessionID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid session ID"})
+		return
+	}
+
+	var req AnswerRequest
+	if !bindJSON(c, &req) {
+		return
+	}
+
+	ctx := c.Request.Context()
+	word, err := h.sessions.IssuedWord(ctx, userID, sessionID, req.WordID, models.DrillMeaning)
+	if !sessionChanged(c, err) {
+		return
+	}
+
+	direction := models.DirectionEnglishToGerman
+	result := h.grader.Grade(quiz.Expected(*word, direction), req.Answer)
+
+	// Almost right answers are not mastered yet; the verdict keeps them apart
+	// from wrong ones
+	review := &models.WordReviewItem{
+		WordID:         word.ID,
+		StudySessionID: sessionID,
+		Correct:        result.Verdict == grading.VerdictCorrect,
+		Verdict:        result.Verdict,
+		Answer:         &result.Answer,
+		Expected:       &result.Expected,
+		ResponseMS:     req.ResponseMS,
+		Direction:      &direction,
+	}
+	err = h.sessions.RecordWordReview(ctx, userID, review)
+	if !sessionChanged(c, err) {
+		return
+	}
+	metrics.RecordReview(models.DrillMeaning, review.Correct)
+
+	c.JSON(http.StatusCreated, result)
+}
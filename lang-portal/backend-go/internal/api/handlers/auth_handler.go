@@ -0,0 +1,276 @@
+package handlers
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/mail"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/souheilbenslama/free-genai-bootcamp-2025/lang-portal/backend-go/internal/auth"
+	"github.com/souheilbenslama/free-genai-bootcamp-2025/lang-portal/backend-go/internal/models"
+	"github.com/souheilbenslama/free-genai-bootcamp-2025/lang-portal/backend-go/internal/repository"
+)
+
+type AuthHandler struct {
+	users  repository.UserRepository
+	tokens *auth.Tokens
+}
+
+// NewAuthHandler creates the account handler. Registered accounts are
+// learners; the first admin is promoted with the promote-admin command.
+func NewAuthHandler(users repository.UserRepository, tokens *auth.Tokens) *AuthHandler {
+	return &AuthHandler{users: users, tokens: tokens}
+}
+
+type RegisterRequest struct {
+	Email    string `json:"email" binding:"required"`
+	Name     string `json:"name" binding:"required"`
+	Password string `json:"password" binding:"required"`
+	// Timezone is an IANA time zone name; accounts default to UTC.
+	Timezone string `json:"timezone"`
+}
+
+type LoginRequest struct {
+	Email    string `json:"email" binding:"required"`
+	Password string `json:"password" binding:"required"`
+}
+
+type RefreshRequest struct {
+	RefreshToken string `json:"refresh_token" binding:"required"`
+}
+
+// TokenResponse is returned by login and refresh.
+type TokenResponse struct {
+	AccessToken  string       `json:"access_token"`
+	TokenType    string       `json:"token_type"`
+	ExpiresIn    int          `json:"expires_in"`
+	RefreshToken string       `json:"refresh_token"`
+	User         *models.User `json:"user"`
+}
+
+func (h *AuthHandler) Register(c *gin.Context) {
+	var req RegisterRequest
+	if !bindJSON(c, &req) {
+		return
+	}
+
+	email, err := normalizeEmail(req.Email)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	name := strings.TrimSpace(req.Name)
+	if name == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "name is required"})
+		return
+	}
+	if err := auth.ValidatePassword(req.Password); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if req.Timezone != "" {
+		if err := validateTimezone(req.Timezone); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+	}
+
+	hash, err := auth.HashPassword(req.Password)
+	if err != nil {
+		internalError(c, err)
+		return
+	}
+
+	user := &models.User{Email: email, Name: name, Role: string(auth.RoleLearner), Timezone: req.Timezone, PasswordHash: hash}
+	err = h.users.CreateUser(c.Request.Context(), user)
+	if errors.Is(err, repository.ErrEmailTaken) {
+		c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+		return
+	}
+	if err != nil {
+		internalError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, user)
+}
+
+func (h *AuthHandler) Login(c *gin.Context) {
+	var req LoginRequest
+	if !bindJSON(c, &req) {
+		return
+	}
+
+	// Unknown emails and wrong passwords get the same answer
+	var passwordHash string
+	user, err := h.users.GetUserByEmail(c.Request.Context(), strings.ToLower(strings.TrimSpace(req.Email)))
+	switch {
+	case err == nil:
+		passwordHash = user.PasswordHash
+	case err != sql.ErrNoRows:
+		internalError(c, err)
+		return
+	}
+
+	if err := auth.CheckPassword(passwordHash, req.Password); err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "invalid email or password"})
+		return
+	}
+
+	h.issueTokens(c, user)
+}
+
+// Refresh trades a refresh token for a new access token and a new refresh
+// token. The old refresh token is revoked.
+func (h *AuthHandler) Refresh(c *gin.Context) {
+	var req RefreshRequest
+	if !bindJSON(c, &req) {
+		return
+	}
+
+	userID, err := h.users.RevokeRefreshToken(c.Request.Context(), auth.HashRefresh(req.RefreshToken))
+	if errors.Is(err, repository.ErrInvalidRefreshToken) {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": err.Error()})
+		return
+	}
+	if err != nil {
+		internalError(c, err)
+		return
+	}
+
+	user, err := h.users.GetUserByID(c.Request.Context(), userID)
+	if err != nil {
+		internalError(c, err)
+		return
+	}
+
+	h.issueTokens(c, user)
+}
+
+// Logout revokes a refresh token. Access tokens stay valid until they expire.
+func (h *AuthHandler) Logout(c *gin.Context) {
+	var req RefreshRequest
+	if !bindJSON(c, &req) {
+		return
+	}
+
+	_, err := h.users.RevokeRefreshToken(c.Request.Context(), auth.HashRefresh(req.RefreshToken))
+	if err != nil && !errors.Is(err, repository.ErrInvalidRefreshToken) {
+		internalError(c, err)
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+func (h *AuthHandler) Me(c *gin.Context) {
+	userID, ok := currentUserID(c)
+	if !ok {
+		return
+	}
+
+	user, err := h.users.GetUserByID(c.Request.Context(), userID)
+	if err == sql.ErrNoRows {
+		c.JSON(http.StatusNotFound, gin.H{"error": "user not found"})
+		return
+	}
+	if err != nil {
+		internalError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, user)
+}
+
+type TimezoneRequest struct {
+	Timezone string `json:"timezone" binding:"required"`
+}
+
+// SetTimezone changes the time zone the caller's study days are counted in.
+func (h *AuthHandler) SetTimezone(c *gin.Context) {
+	userID, ok := currentUserID(c)
+	if !ok {
+		return
+	}
+
+	var req TimezoneRequest
+	if !bindJSON(c, &req) {
+		return
+	}
+	if err := validateTimezone(req.Timezone); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	user, err := h.users.SetTimezone(c.Request.Context(), userID, req.Timezone)
+	if err == sql.ErrNoRows {
+		c.JSON(http.StatusNotFound, gin.H{"error": "user not found"})
+		return
+	}
+	if err != nil {
+		internalError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, user)
+}
+
+func (h *AuthHandler) issueTokens(c *gin.Context, user *models.User) {
+	access, err := h.tokens.IssueAccess(auth.Principal{UserID: user.ID, Role: auth.Role(user.Role)})
+	if err != nil {
+		internalError(c, err)
+		return
+	}
+
+	refresh, hash, expiresAt, err := h.tokens.NewRefresh()
+	if err != nil {
+		internalError(c, err)
+		return
+	}
+	if err := h.users.CreateRefreshToken(c.Request.Context(), user.ID, hash, expiresAt); err != nil {
+		internalError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, TokenResponse{
+		AccessToken:  access,
+		TokenType:    "Bearer",
+		ExpiresIn:    int(h.tokens.AccessTTL().Seconds()),
+		RefreshToken: refresh,
+		User:         user,
+	})
+}
+
+// currentUserID returns the user authenticated by the Authenticate
+// middleware. Reaching a handler without one is a routing bug.
+func currentUserID(c *gin.Context) (int, bool) {
+	userID, ok := auth.UserID(c.Request.Context())
+	if !ok {
+		internalError(c, errors.New("route is missing the authentication middleware"))
+	}
+	return userID, ok
+}
+
+func normalizeEmail(email string) (string, error) {
+	email = strings.ToLower(strings.TrimSpace(email))
+	addr, err := mail.ParseAddress(email)
+	if err != nil || addr.Address != email {
+		return "", fmt.Errorf("invalid email address %q", email)
+	}
+	return email, nil
+}
+
+// validateTimezone accepts IANA time zone names. Local would mean the time
+// zone of the server, so it is not one.
+func validateTimezone(name string) error {
+	if name != "Local" {
+		if _, err := time.LoadLocation(name); err == nil {
+			return nil
+		}
+	}
+	return fmt.Errorf("unknown time zone %q", name)
+}
@@ -0,0 +1,28 @@
+package handlers
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// bindJSON decodes and validates the JSON body into obj. It answers with 413
+// when the body runs over the size cap set by middleware.MaxBodySize and with
+// 400 when it is invalid, and reports whether the handler may go on.
+func bindJSON(c *gin.Context, obj any) bool {
+	err := c.ShouldBindJSON(obj)
+	if err == nil {
+		return true
+	}
+
+	var tooLarge *http.MaxBytesError
+	if errors.As(err, &tooLarge) {
+		c.JSON(http.StatusRequestEntityTooLarge, gin.H{"error": fmt.Sprintf("request body exceeds %d bytes", tooLarge.Limit)})
+		return false
+	}
+
+	c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	return false
+}
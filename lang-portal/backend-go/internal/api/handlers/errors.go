@@ -0,0 +1,21 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/souheilbenslama/free-genai-bootcamp-2025/lang-portal/backend-go/internal/logging"
+)
+
+// internalError records err as the cause of the failure for the request
+// logger and answers with a generic message, so internal details such as SQL
+// errors never reach the client. The request ID lets operators find the cause.
+func internalError(c *gin.Context, err error) {
+	c.Error(err)
+
+	body := gin.H{"error": "internal server error"}
+	if id := logging.RequestID(c.Request.Context()); id != "" {
+		body["request_id"] = id
+	}
+	c.JSON(http.StatusInternalServerError, body)
+}
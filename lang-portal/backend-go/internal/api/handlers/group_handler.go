@@ -6,18 +6,18 @@ import (
 	"strconv"
 
 	"github.com/gin-gonic/gin"
+	"github.com/souheilbenslama/free-genai-bootcamp-2025/lang-portal/backend-go/internal/auth"
 	"github.com/souheilbenslama/free-genai-bootcamp-2025/lang-portal/backend-go/internal/models"
-	"github.com/souheilbenslama/free-genai-bootcamp-2025/lang-portal/backend-go/internal/repository/sqlite"
+	"github.com/souheilbenslama/free-genai-bootcamp-2025/lang-portal/backend-go/internal/repository"
 )
 
 type GroupHandler struct {
-	repo *sqlite.GroupRepository
+	repo repository.GroupRepository
 }
 
 func (h *GroupHandler) CreateGroup(c *gin.Context) {
 	var group models.Group
-	if err := c.ShouldBindJSON(&group); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	if !bindJSON(c, &group) {
 		return
 	}
 
@@ -29,7 +29,7 @@ func (h *GroupHandler) CreateGroup(c *gin.Context) {
 
 	err := h.repo.CreateGroup(c.Request.Context(), &group)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		internalError(c, err)
 		return
 	}
 
@@ -45,15 +45,14 @@ func (h *GroupHandler) UpdateGroup(c *gin.Context) {
 	}
 
 	var group models.Group
-	if err := c.ShouldBindJSON(&group); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	if !bindJSON(c, &group) {
 		return
 	}
 
 	group.ID = groupID
 	err = h.repo.UpdateGroup(c.Request.Context(), &group)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		internalError(c, err)
 		return
 	}
 
@@ -70,7 +69,7 @@ func (h *GroupHandler) DeleteGroup(c *gin.Context) {
 
 	err = h.repo.DeleteGroup(c.Request.Context(), groupID)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		internalError(c, err)
 		return
 	}
 
@@ -88,18 +87,17 @@ func (h *GroupHandler) AddWordToGroup(c *gin.Context) {
 		WordID int `json:"word_id" binding:"required"`
 	}
 
-	if err := c.ShouldBindJSON(&req); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	if !bindJSON(c, &req) {
 		return
 	}
 
 	// Check if group exists
-	group, err := h.repo.GetByID(groupID)
+	group, err := h.repo.GetByID(c.Request.Context(), groupID)
 	if err == sql.ErrNoRows {
 		c.JSON(http.StatusNotFound, gin.H{"error": "group not found"})
 		return
 	} else if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		internalError(c, err)
 		return
 	}
 	if group == nil {
@@ -113,7 +111,7 @@ func (h *GroupHandler) AddWordToGroup(c *gin.Context) {
 			c.JSON(http.StatusNotFound, gin.H{"error": "word not found"})
 			return
 		}
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		internalError(c, err)
 		return
 	}
 
@@ -135,36 +133,80 @@ func (h *GroupHandler) RemoveWordFromGroup(c *gin.Context) {
 
 	err = h.repo.RemoveWordFromGroup(c.Request.Context(), groupID, wordID)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		internalError(c, err)
 		return
 	}
 
 	c.JSON(http.StatusOK, gin.H{"message": "word removed from group successfully"})
 }
 
-func NewGroupHandler(repo *sqlite.GroupRepository) *GroupHandler {
+func NewGroupHandler(repo repository.GroupRepository) *GroupHandler {
 	return &GroupHandler{repo: repo}
 }
 
 func (h *GroupHandler) GetGroups(c *gin.Context) {
-	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
-	pageSize, _ := strconv.Atoi(c.DefaultQuery("page_size", "100"))
+	params, ok := parsePagination(c)
+	if !ok {
+		return
+	}
 
-	groups, total, err := h.repo.GetAll(page, pageSize)
+	ctx := c.Request.Context()
+	groups, page, err := h.repo.ListGroups(ctx, params)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		internalError(c, err)
 		return
 	}
 
-	c.JSON(http.StatusOK, gin.H{
-		"items": groups,
-		"pagination": gin.H{
-			"current_page":    page,
-			"total_pages":     (total + pageSize - 1) / pageSize,
-			"total_items":     total,
-			"items_per_page":  pageSize,
-		},
-	})
+	// Callers who may read their progress see it with every group
+	if principal, ok := auth.PrincipalFrom(ctx); ok && principal.Can(auth.PermReadProgress) {
+		ids := make([]int, len(groups))
+		for i, group := range groups {
+			ids[i] = group.ID
+		}
+		progress, err := h.repo.GetProgress(ctx, principal.UserID, ids)
+		if err != nil {
+			internalError(c, err)
+			return
+		}
+		for i := range groups {
+			groups[i].Progress = progress[groups[i].ID]
+		}
+	}
+
+	respondPage(c, groups, page)
+}
+
+// GetGroupProgress reports how far the caller got with the words of a group.
+func (h *GroupHandler) GetGroupProgress(c *gin.Context) {
+	userID, ok := currentUserID(c)
+	if !ok {
+		return
+	}
+
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid group ID"})
+		return
+	}
+
+	ctx := c.Request.Context()
+	group, err := h.repo.GetByID(ctx, id)
+	if err != nil {
+		internalError(c, err)
+		return
+	}
+	if group == nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "group not found"})
+		return
+	}
+
+	progress, err := h.repo.GetProgress(ctx, userID, []int{id})
+	if err != nil {
+		internalError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, progress[id])
 }
 
 func (h *GroupHandler) GetGroup(c *gin.Context) {
@@ -174,9 +216,9 @@ func (h *GroupHandler) GetGroup(c *gin.Context) {
 		return
 	}
 
-	group, err := h.repo.GetByID(id)
+	group, err := h.repo.GetByID(c.Request.Context(), id)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		internalError(c, err)
 		return
 	}
 
@@ -186,9 +228,9 @@ func (h *GroupHandler) GetGroup(c *gin.Context) {
 	}
 
 	// Get words in this group
-	words, err := h.repo.GetGroupWords(id)
+	words, err := h.repo.GetGroupWords(c.Request.Context(), id)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		internalError(c, err)
 		return
 	}
 
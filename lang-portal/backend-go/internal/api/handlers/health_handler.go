@@ -0,0 +1,111 @@
+package handlers
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"io/fs"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/souheilbenslama/free-genai-bootcamp-2025/lang-portal/backend-go/internal/migrate"
+	"github.com/souheilbenslama/free-genai-bootcamp-2025/lang-portal/backend-go/internal/seeder"
+	"github.com/souheilbenslama/free-genai-bootcamp-2025/lang-portal/backend-go/internal/version"
+)
+
+const readinessTimeout = 2 * time.Second
+
+type HealthHandler struct {
+	db           *sql.DB
+	migrations   fs.FS
+	seedRequired bool
+}
+
+// NewHealthHandler builds the probe handler. Readiness requires every
+// migration in migrations to be applied and, when seedRequired is set, the
+// seed data to be loaded.
+func NewHealthHandler(db *sql.DB, migrations fs.FS, seedRequired bool) *HealthHandler {
+	return &HealthHandler{db: db, migrations: migrations, seedRequired: seedRequired}
+}
+
+type componentStatus struct {
+	Status string `json:"status"`
+	Error  string `json:"error,omitempty"`
+}
+
+// Healthz reports that the process is alive. It never touches the database.
+func (h *HealthHandler) Healthz(c *gin.Context) {
+	c.JSON(http.StatusOK, gin.H{"status": "ok"})
+}
+
+// Readyz reports whether the service can take traffic, with one entry per
+// component so a failing probe says what is wrong.
+func (h *HealthHandler) Readyz(c *gin.Context) {
+	ctx, cancel := context.WithTimeout(c.Request.Context(), readinessTimeout)
+	defer cancel()
+
+	checks := map[string]componentStatus{
+		"database":   check(h.db.PingContext(ctx)),
+		"migrations": check(h.checkMigrations(ctx)),
+	}
+	if h.seedRequired {
+		checks["seed"] = check(h.checkSeed())
+	}
+
+	status, code := "ready", http.StatusOK
+	for _, component := range checks {
+		if component.Status != "ok" {
+			status, code = "unavailable", http.StatusServiceUnavailable
+		}
+	}
+
+	c.JSON(code, gin.H{"status": status, "checks": checks})
+}
+
+// Version reports the build of the running binary and its schema version.
+func (h *HealthHandler) Version(c *gin.Context) {
+	info := version.Get()
+
+	schemaVersion, _, err := migrate.Status(c.Request.Context(), h.db, h.migrations)
+	if err != nil || schemaVersion == "" {
+		schemaVersion = "unknown"
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"commit":         info.Commit,
+		"build_time":     info.BuildTime,
+		"go_version":     info.GoVersion,
+		"schema_version": schemaVersion,
+	})
+}
+
+func (h *HealthHandler) checkMigrations(ctx context.Context) error {
+	_, pending, err := migrate.Status(ctx, h.db, h.migrations)
+	if err != nil {
+		return err
+	}
+	if len(pending) > 0 {
+		return fmt.Errorf("%d pending migration(s): %s", len(pending), strings.Join(pending, ", "))
+	}
+	return nil
+}
+
+func (h *HealthHandler) checkSeed() error {
+	applied, err := seeder.Applied(h.db)
+	if err != nil {
+		return err
+	}
+	if !applied {
+		return fmt.Errorf("seed data has not been loaded")
+	}
+	return nil
+}
+
+func check(err error) componentStatus {
+	if err != nil {
+		return componentStatus{Status: "fail", Error: err.Error()}
+	}
+	return componentStatus{Status: "ok"}
+}
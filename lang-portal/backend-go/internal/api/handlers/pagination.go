@@ -0,0 +1,31 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/souheilbenslama/free-genai-bootcamp-2025/lang-portal/backend-go/internal/pagination"
+)
+
+// parsePagination reads the limit and cursor query parameters shared by every
+// list endpoint. It writes a 400 response and returns false on invalid input.
+func parsePagination(c *gin.Context) (pagination.Params, bool) {
+	params, err := pagination.Parse(c.Request.URL.Query())
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return pagination.Params{}, false
+	}
+	return params, true
+}
+
+// respondPage writes a list response using the shared pagination contract.
+func respondPage(c *gin.Context, items interface{}, page pagination.Page) {
+	// Added rather than set, so links of middleware such as Deprecated stay
+	c.Writer.Header().Add("Link", pagination.LinkHeader(c.Request.URL, page))
+	c.Header("X-Total-Count", strconv.Itoa(page.TotalItems))
+	c.JSON(http.StatusOK, gin.H{
+		"items":      items,
+		"pagination": page,
+	})
+}
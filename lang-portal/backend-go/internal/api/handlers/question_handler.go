@@ -0,0 +1,97 @@
+package handlers
+
+import (
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/souheilbenslama/free-genai-bootcamp-2025/lang-portal/backend-go/internal/quiz"
+	"github.com/souheilbenslama/free-genai-bootcamp-2025/lang-portal/backend-go/internal/repository"
+)
+
+type QuestionHandler struct {
+	sessions repository.StudySessionRepository
+	groups   repository.GroupRepository
+}
+
+func NewQuestionHandler(sessions repository.StudySessionRepository, groups repository.GroupRepository) *QuestionHandler {
+	return &QuestionHandler{sessions: sessions, groups: groups}
+}
+
+// GetQuestion issues the next word of a session of the caller, chosen like
+// StudyHandler.NextItem, and asks a question of the type query parameter
+// about it: its meaning among options, or the article or plural of a noun.
+// It answers 204 once every word of the group that suits the question was
+// issued.
+func (h *QuestionHandler) GetQuestion(c *gin.Context) {
+	userID, ok := currentUserID(c)
+	if !ok {
+		return
+	}
+
+	sessionID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid session ID"})
+		return
+	}
+
+	questionType := c.DefaultQuery("type", quiz.TypeMultipleChoice)
+	if !slices.Contains(quiz.Types, questionType) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "type must be one of " + strings.Join(quiz.Types, ", ")})
+		return
+	}
+
+	strategy, ok := parseStrategy(c)
+	if !ok {
+		return
+	}
+
+	ctx := c.Request.Context()
+	item, err := h.sessions.NextItem(ctx, userID, sessionID, strategy, quiz.Drills[questionType])
+	if !sessionChanged(c, err) {
+		return
+	}
+	if item == nil {
+		c.Status(http.StatusNoContent)
+		return
+	}
+
+	question := quiz.Question{
+		Type:           questionType,
+		ItemID:         item.ID,
+		StudySessionID: item.StudySessionID,
+		WordID:         item.Word.ID,
+		Prompt:         item.Word.German,
+		Remaining:      item.Remaining,
+	}
+
+	switch questionType {
+	case quiz.TypeMultipleChoice:
+		// Distractors come from the group being studied
+		pool, err := h.groups.GetGroupWords(ctx, item.GroupID)
+		if err != nil {
+			internalError(c, err)
+			return
+		}
+		question.Options = quiz.MultipleChoice(item.Word, pool, nil)
+
+		ids := make([]int, len(question.Options))
+		for i, option := range question.Options {
+			ids[i] = option.WordID
+		}
+		if err := h.sessions.SetItemOptions(ctx, userID, item.ID, ids); err != nil {
+			internalError(c, err)
+			return
+		}
+	case quiz.TypeArticle:
+		question.Articles = quiz.Articles
+	case quiz.TypePlural:
+		if article := quiz.ParseParts(item.Word).Article; article != "" {
+			question.Prompt = article + " " + item.Word.German
+		}
+	}
+
+	c.JSON(http.StatusOK, question)
+}
@@ -1,25 +1,40 @@
 package handlers
 
 import (
+	"database/sql"
+	"errors"
 	"net/http"
 	"strconv"
+	"strings"
+	"time"
 
 	"github.com/gin-gonic/gin"
-	"github.com/souheilbenslama/free-genai-bootcamp-2025/lang-portal/backend-go/internal/repository/sqlite"
+	"github.com/souheilbenslama/free-genai-bootcamp-2025/lang-portal/backend-go/internal/grading"
+	"github.com/souheilbenslama/free-genai-bootcamp-2025/lang-portal/backend-go/internal/metrics"
+	"github.com/souheilbenslama/free-genai-bootcamp-2025/lang-portal/backend-go/internal/models"
+	"github.com/souheilbenslama/free-genai-bootcamp-2025/lang-portal/backend-go/internal/repository"
+	"github.com/souheilbenslama/free-genai-bootcamp-2025/lang-portal/backend-go/internal/stats"
+	"github.com/souheilbenslama/free-genai-bootcamp-2025/lang-portal/backend-go/internal/streak"
 )
 
 type StudyHandler struct {
-	repo *sqlite.StudyRepository
+	repo    repository.StudySessionRepository
+	streaks *streak.Service
 }
 
-func NewStudyHandler(repo *sqlite.StudyRepository) *StudyHandler {
-	return &StudyHandler{repo: repo}
+func NewStudyHandler(repo repository.StudySessionRepository, streaks *streak.Service) *StudyHandler {
+	return &StudyHandler{repo: repo, streaks: streaks}
 }
 
 func (h *StudyHandler) GetLastStudySession(c *gin.Context) {
-	session, err := h.repo.GetLastStudySession()
+	userID, ok := currentUserID(c)
+	if !ok {
+		return
+	}
+
+	session, err := h.repo.GetLastStudySession(c.Request.Context(), userID)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		internalError(c, err)
 		return
 	}
 
@@ -32,9 +47,14 @@ func (h *StudyHandler) GetLastStudySession(c *gin.Context) {
 }
 
 func (h *StudyHandler) GetStudyProgress(c *gin.Context) {
-	progress, err := h.repo.GetStudyProgress()
+	userID, ok := currentUserID(c)
+	if !ok {
+		return
+	}
+
+	progress, err := h.repo.GetStudyProgress(c.Request.Context(), userID)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		internalError(c, err)
 		return
 	}
 
@@ -42,58 +62,493 @@ func (h *StudyHandler) GetStudyProgress(c *gin.Context) {
 }
 
 func (h *StudyHandler) GetQuickStats(c *gin.Context) {
-	stats, err := h.repo.GetQuickStats()
+	userID, ok := currentUserID(c)
+	if !ok {
+		return
+	}
+
+	ctx := c.Request.Context()
+	stats, err := h.repo.GetQuickStats(ctx, userID)
+	if err != nil {
+		internalError(c, err)
+		return
+	}
+	current, err := h.streaks.Get(ctx, userID)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		internalError(c, err)
 		return
 	}
+	stats.StudyStreakDays = current.Current
 
 	c.JSON(http.StatusOK, stats)
 }
 
+func (h *StudyHandler) ListStudySessions(c *gin.Context) {
+	userID, ok := currentUserID(c)
+	if !ok {
+		return
+	}
+
+	params, ok := parsePagination(c)
+	if !ok {
+		return
+	}
+
+	sessions, page, err := h.repo.ListStudySessions(c.Request.Context(), userID, params)
+	if err != nil {
+		internalError(c, err)
+		return
+	}
+
+	respondPage(c, sessions, page)
+}
+
 type StartStudySessionRequest struct {
 	GroupID int `json:"group_id" binding:"required"`
 }
 
 func (h *StudyHandler) StartStudySession(c *gin.Context) {
+	userID, ok := currentUserID(c)
+	if !ok {
+		return
+	}
+
 	var req StartStudySessionRequest
-	if err := c.ShouldBindJSON(&req); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	if !bindJSON(c, &req) {
 		return
 	}
 
-	session, err := h.repo.CreateStudySession(req.GroupID)
+	session, err := h.repo.CreateStudySession(c.Request.Context(), userID, req.GroupID)
+	if errors.Is(err, repository.ErrGroupNotFound) {
+		c.JSON(http.StatusNotFound, gin.H{"error": "group not found"})
+		return
+	}
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		internalError(c, err)
 		return
 	}
+	metrics.SessionsStarted.Inc()
 
 	c.JSON(http.StatusCreated, session)
 }
 
+// FinishStudySession completes an active session of the caller.
+func (h *StudyHandler) FinishStudySession(c *gin.Context) {
+	userID, ok := currentUserID(c)
+	if !ok {
+		return
+	}
+
+	sessionID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid session ID"})
+		return
+	}
+
+	session, err := h.repo.FinishStudySession(c.Request.Context(), userID, sessionID)
+	if !sessionChanged(c, err) {
+		return
+	}
+	metrics.SessionsEnded.WithLabelValues(session.State).Inc()
+
+	c.JSON(http.StatusOK, session)
+}
+
+// NextItem issues the next word of a session of the caller, chosen by the
+// strategy query parameter: shuffle (the default), weakest or due. It answers
+// 204 once every word of the group was issued.
+func (h *StudyHandler) NextItem(c *gin.Context) {
+	userID, ok := currentUserID(c)
+	if !ok {
+		return
+	}
+
+	sessionID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid session ID"})
+		return
+	}
+
+	strategy, ok := parseStrategy(c)
+	if !ok {
+		return
+	}
+
+	item, err := h.repo.NextItem(c.Request.Context(), userID, sessionID, strategy, models.DrillMeaning)
+	if !sessionChanged(c, err) {
+		return
+	}
+	if item == nil {
+		c.Status(http.StatusNoContent)
+		return
+	}
+
+	c.JSON(http.StatusOK, item)
+}
+
+// parseStrategy reads the strategy query parameter, answering 400 when it is
+// unknown.
+func parseStrategy(c *gin.Context) (string, bool) {
+	strategy := c.DefaultQuery("strategy", models.StrategyShuffle)
+	switch strategy {
+	case models.StrategyShuffle, models.StrategyWeakest, models.StrategyDue:
+		return strategy, true
+	}
+	c.JSON(http.StatusBadRequest, gin.H{"error": "strategy must be shuffle, weakest or due"})
+	return "", false
+}
+
+// Correct is a pointer so that a missing answer fails validation while an
+// explicit false does not. Answers to multiple choice questions may send the
+// picked option instead, which decides whether the answer was correct. The
+// answer text, response time and direction are optional.
 type WordReviewRequest struct {
-	WordID  int  `json:"word_id" binding:"required"`
-	Correct bool `json:"correct" binding:"required"`
+	WordID       int     `json:"word_id" binding:"required"`
+	Correct      *bool   `json:"correct" binding:"required_without=ChosenWordID"`
+	ChosenWordID *int    `json:"chosen_word_id"`
+	Answer       *string `json:"answer" binding:"omitempty,max=200"`
+	ResponseMS   *int    `json:"response_ms" binding:"omitempty,min=0"`
+	Direction    *string `json:"direction" binding:"omitempty,oneof=de_en en_de"`
 }
 
 func (h *StudyHandler) RecordWordReview(c *gin.Context) {
-	sessionID, err := strconv.Atoi(c.Param("session_id"))
+	userID, ok := currentUserID(c)
+	if !ok {
+		return
+	}
+
+	sessionID, err := strconv.Atoi(c.Param("id"))
 	if err != nil {
 		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid session ID"})
 		return
 	}
 
 	var req WordReviewRequest
-	if err := c.ShouldBindJSON(&req); err != nil {
+	if !bindJSON(c, &req) {
+		return
+	}
+
+	review := &models.WordReviewItem{
+		WordID:         req.WordID,
+		StudySessionID: sessionID,
+		ChosenWordID:   req.ChosenWordID,
+		Answer:         req.Answer,
+		ResponseMS:     req.ResponseMS,
+		Direction:      req.Direction,
+	}
+	if req.ChosenWordID != nil {
+		// Multiple choice questions show the German word
+		if review.Direction == nil {
+			direction := models.DirectionGermanToEnglish
+			review.Direction = &direction
+		}
+		review.Correct = *req.ChosenWordID == req.WordID
+		if req.Correct != nil && *req.Correct != review.Correct {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "correct contradicts chosen_word_id"})
+			return
+		}
+	} else {
+		review.Correct = *req.Correct
+	}
+
+	review.Verdict = grading.Verdict(review.Correct)
+
+	err = h.repo.RecordWordReview(c.Request.Context(), userID, review)
+	if !sessionChanged(c, err) {
+		return
+	}
+	metrics.RecordReview(models.DrillMeaning, review.Correct)
+
+	c.Status(http.StatusNoContent)
+}
+
+type ArticleReviewRequest struct {
+	WordID  int    `json:"word_id" binding:"required"`
+	Article string `json:"article" binding:"required,oneof=der die das"`
+}
+
+// RecordArticleReview grades the article the caller chose for a noun issued
+// by an article question and answers with the graded review.
+func (h *StudyHandler) RecordArticleReview(c *gin.Context) {
+	userID, ok := currentUserID(c)
+	if !ok {
+		return
+	}
+
+	sessionID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid session ID"})
+		return
+	}
+
+	var req ArticleReviewRequest
+	if !bindJSON(c, &req) {
+		return
+	}
+
+	review := &models.ArticleReview{WordID: req.WordID, StudySessionID: sessionID, ChosenArticle: req.Article}
+	err = h.repo.RecordArticleReview(c.Request.Context(), userID, review)
+	if !sessionChanged(c, err) {
+		return
+	}
+	metrics.RecordReview(models.DrillArticle, review.Correct)
+
+	c.JSON(http.StatusCreated, review)
+}
+
+// GetArticleBreakdown reports the article drill accuracy of the caller per
+// article and per noun.
+func (h *StudyHandler) GetArticleBreakdown(c *gin.Context) {
+	userID, ok := currentUserID(c)
+	if !ok {
+		return
+	}
+
+	breakdown, err := h.repo.GetArticleBreakdown(c.Request.Context(), userID)
+	if err != nil {
+		internalError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, breakdown)
+}
+
+type PluralReviewRequest struct {
+	WordID int    `json:"word_id" binding:"required"`
+	Answer string `json:"answer" binding:"required"`
+}
+
+// RecordPluralReview grades the plural the caller typed for a noun issued by
+// a plural question and answers with the graded review.
+func (h *StudyHandler) RecordPluralReview(c *gin.Context) {
+	userID, ok := currentUserID(c)
+	if !ok {
+		return
+	}
+
+	sessionID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid session ID"})
+		return
+	}
+
+	var req PluralReviewRequest
+	if !bindJSON(c, &req) {
+		return
+	}
+
+	review := &models.PluralReview{WordID: req.WordID, StudySessionID: sessionID, Answer: strings.TrimSpace(req.Answer)}
+	err = h.repo.RecordPluralReview(c.Request.Context(), userID, review)
+	if !sessionChanged(c, err) {
+		return
+	}
+	metrics.RecordReview(models.DrillPlural, review.Correct)
+
+	c.JSON(http.StatusCreated, review)
+}
+
+// GetPluralBreakdown reports the plural drill accuracy of the caller per
+// plural pattern.
+func (h *StudyHandler) GetPluralBreakdown(c *gin.Context) {
+	userID, ok := currentUserID(c)
+	if !ok {
+		return
+	}
+
+	breakdown, err := h.repo.GetPluralBreakdown(c.Request.Context(), userID)
+	if err != nil {
+		internalError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, breakdown)
+}
+
+// TimeSeriesRequest is the query of a time series. Dates are days in the time
+// zone of the caller; to defaults to today and from to 30 days or 12 weeks
+// before it.
+type TimeSeriesRequest struct {
+	Metric  string `form:"metric" binding:"omitempty,oneof=reviews accuracy new_words minutes"`
+	Bucket  string `form:"bucket" binding:"omitempty,oneof=day week"`
+	From    string `form:"from" binding:"omitempty,datetime=2006-01-02"`
+	To      string `form:"to" binding:"omitempty,datetime=2006-01-02"`
+	GroupID int    `form:"group_id" binding:"omitempty,min=1"`
+	Drill   string `form:"drill" binding:"omitempty,oneof=meaning article plural"`
+	Compare bool   `form:"compare"`
+}
+
+// GetTimeSeries reports a metric of the caller per day or week, optionally
+// next to the period right before.
+func (h *StudyHandler) GetTimeSeries(c *gin.Context) {
+	userID, ok := currentUserID(c)
+	if !ok {
+		return
+	}
+
+	req := TimeSeriesRequest{Metric: stats.MetricReviews, Bucket: stats.BucketDay}
+	if err := c.ShouldBindQuery(&req); err != nil {
 		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
 		return
 	}
 
-	err = h.repo.RecordWordReview(sessionID, req.WordID, req.Correct)
+	// The binding already checked the layout of the dates, and today is
+	// only needed without them
+	ctx := c.Request.Context()
+	to, _ := time.Parse(stats.DateLayout, req.To)
+	if req.To == "" {
+		timezone, err := h.repo.Timezone(ctx, userID)
+		if err != nil {
+			internalError(c, err)
+			return
+		}
+		loc, err := time.LoadLocation(timezone)
+		if err != nil {
+			internalError(c, err)
+			return
+		}
+		to = stats.Day(time.Now().In(loc))
+	}
+	from := to.AddDate(0, 0, -29)
+	if req.Bucket == stats.BucketWeek {
+		from = to.AddDate(0, 0, -11*7)
+	}
+	if req.From != "" {
+		from, _ = time.Parse(stats.DateLayout, req.From)
+	}
+
+	rng, err := stats.NewRange(req.Bucket, from, to)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
 		return
 	}
 
-	c.Status(http.StatusNoContent)
+	filter := models.StatsFilter{GroupID: req.GroupID, Drill: req.Drill}
+	series, err := h.repo.GetTimeSeries(ctx, userID, req.Metric, rng, filter)
+	if err != nil {
+		internalError(c, err)
+		return
+	}
+
+	timeSeries := &models.TimeSeries{Metric: req.Metric, Bucket: req.Bucket, Series: *series}
+	if req.GroupID != 0 {
+		timeSeries.GroupID = &req.GroupID
+	}
+	if req.Drill != "" {
+		timeSeries.Drill = &req.Drill
+	}
+
+	if req.Compare {
+		timeSeries.Previous, err = h.repo.GetTimeSeries(ctx, userID, req.Metric, rng.Previous(), filter)
+		if err != nil {
+			internalError(c, err)
+			return
+		}
+		if current, previous := series.Total, timeSeries.Previous.Total; current != nil && previous != nil && *previous != 0 {
+			change := (*current - *previous) / *previous * 100
+			timeSeries.Change = &change
+		}
+	}
+
+	c.JSON(http.StatusOK, timeSeries)
+}
+
+type CalendarRequest struct {
+	Year int `form:"year" binding:"omitempty,min=1970,max=9999"`
+}
+
+// GetCalendar reports the study of the caller on every day of a year, by
+// default the current one, in the caller's time zone.
+func (h *StudyHandler) GetCalendar(c *gin.Context) {
+	userID, ok := currentUserID(c)
+	if !ok {
+		return
+	}
+
+	var req CalendarRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	calendar, err := h.repo.GetCalendar(c.Request.Context(), userID, req.Year)
+	if err != nil {
+		internalError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, calendar)
+}
+
+// GetStreak reports the study streak of the caller.
+func (h *StudyHandler) GetStreak(c *gin.Context) {
+	userID, ok := currentUserID(c)
+	if !ok {
+		return
+	}
+
+	current, err := h.streaks.Get(c.Request.Context(), userID)
+	if err != nil {
+		internalError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, current)
+}
+
+// FreezeStreakRequest names the day to freeze, today or yesterday in the
+// caller's time zone as reported by the streak.
+type FreezeStreakRequest struct {
+	Day string `json:"day" binding:"required,datetime=2006-01-02"`
+}
+
+// FreezeStreak spends a streak freeze of the caller on a day without study.
+func (h *StudyHandler) FreezeStreak(c *gin.Context) {
+	userID, ok := currentUserID(c)
+	if !ok {
+		return
+	}
+
+	var req FreezeStreakRequest
+	if !bindJSON(c, &req) {
+		return
+	}
+
+	current, err := h.streaks.Freeze(c.Request.Context(), userID, req.Day)
+	switch {
+	case errors.Is(err, streak.ErrNotFreezable):
+		c.JSON(http.StatusBadRequest, gin.H{"error": "only today or yesterday can be frozen, when not studied and right after a day studied or frozen"})
+		return
+	case errors.Is(err, streak.ErrNoFreezes):
+		c.JSON(http.StatusConflict, gin.H{"error": "no streak freezes left"})
+		return
+	case err != nil:
+		internalError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, current)
+}
+
+// sessionChanged answers the errors of changing a study session and reports
+// whether there were none.
+func sessionChanged(c *gin.Context, err error) bool {
+	switch {
+	case err == nil:
+		return true
+	case errors.Is(err, sql.ErrNoRows):
+		c.JSON(http.StatusNotFound, gin.H{"error": "study session not found"})
+	case errors.Is(err, repository.ErrSessionEnded):
+		c.JSON(http.StatusConflict, gin.H{"error": "study session has already ended"})
+	case errors.Is(err, repository.ErrWordNotIssued):
+		c.JSON(http.StatusBadRequest, gin.H{"error": "word was not issued in this study session"})
+	case errors.Is(err, repository.ErrOptionNotOffered):
+		c.JSON(http.StatusBadRequest, gin.H{"error": "chosen word was not offered as an option"})
+	case errors.Is(err, repository.ErrNotANoun):
+		c.JSON(http.StatusBadRequest, gin.H{"error": "word has no article"})
+	case errors.Is(err, repository.ErrNoPlural):
+		c.JSON(http.StatusBadRequest, gin.H{"error": "word has no plural"})
+	default:
+		internalError(c, err)
+	}
+	return false
 }
@@ -4,10 +4,24 @@ import (
 	"database/sql"
 	"log"
 	"os"
+	"time"
 
 	_ "github.com/mattn/go-sqlite3"
+	"github.com/souheilbenslama/free-genai-bootcamp-2025/lang-portal/backend-go/internal/auth"
 )
 
+// Tokens issues access tokens accepted by routes set up in tests.
+var Tokens = auth.NewTokens([]byte("test-secret-test-secret-test-secret"), time.Hour, 24*time.Hour)
+
+// Bearer returns an Authorization header value for a user with the role.
+func Bearer(userID int, role auth.Role) string {
+	token, err := Tokens.IssueAccess(auth.Principal{UserID: userID, Role: role})
+	if err != nil {
+		log.Fatal(err)
+	}
+	return "Bearer " + token
+}
+
 func SetupTestDB() *sql.DB {
 	// Create a temporary database file
 	tmpfile, err := os.CreateTemp("", "test-*.db")
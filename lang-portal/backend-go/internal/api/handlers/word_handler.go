@@ -6,6 +6,7 @@ import (
 	"strconv"
 
 	"github.com/gin-gonic/gin"
+	"github.com/souheilbenslama/free-genai-bootcamp-2025/lang-portal/backend-go/internal/metrics"
 	"github.com/souheilbenslama/free-genai-bootcamp-2025/lang-portal/backend-go/internal/models"
 	"github.com/souheilbenslama/free-genai-bootcamp-2025/lang-portal/backend-go/internal/repository"
 )
@@ -23,15 +24,14 @@ func (h *WordHandler) UpdateWord(c *gin.Context) {
 	}
 
 	var word models.Word
-	if err := c.ShouldBindJSON(&word); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	if !bindJSON(c, &word) {
 		return
 	}
 
 	word.ID = wordID
 	err = h.wordRepo.UpdateWord(c.Request.Context(), &word)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		internalError(c, err)
 		return
 	}
 
@@ -48,7 +48,7 @@ func (h *WordHandler) DeleteWord(c *gin.Context) {
 
 	err = h.wordRepo.DeleteWord(c.Request.Context(), wordID)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		internalError(c, err)
 		return
 	}
 
@@ -72,7 +72,7 @@ func (h *WordHandler) GetWord(c *gin.Context) {
 			c.JSON(http.StatusNotFound, gin.H{"error": "word not found"})
 			return
 		}
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		internalError(c, err)
 		return
 	}
 
@@ -80,28 +80,23 @@ func (h *WordHandler) GetWord(c *gin.Context) {
 }
 
 func (h *WordHandler) ListWords(c *gin.Context) {
-	offset, _ := strconv.Atoi(c.DefaultQuery("offset", "0"))
-	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "100"))
+	params, ok := parsePagination(c)
+	if !ok {
+		return
+	}
 
-	words, err := h.wordRepo.ListWords(c.Request.Context(), offset, limit)
+	words, page, err := h.wordRepo.ListWords(c.Request.Context(), params)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		internalError(c, err)
 		return
 	}
 
-	c.JSON(http.StatusOK, gin.H{
-		"items": words,
-		"pagination": gin.H{
-			"offset": offset,
-			"limit":  limit,
-		},
-	})
+	respondPage(c, words, page)
 }
 
 func (h *WordHandler) CreateWord(c *gin.Context) {
 	var word models.Word
-	if err := c.ShouldBindJSON(&word); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	if !bindJSON(c, &word) {
 		return
 	}
 
@@ -112,9 +107,10 @@ func (h *WordHandler) CreateWord(c *gin.Context) {
 	}
 
 	if err := h.wordRepo.CreateWord(c.Request.Context(), &word); err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		internalError(c, err)
 		return
 	}
+	metrics.WordsCreated.Inc()
 
 	c.JSON(http.StatusOK, word)
 }
@@ -12,6 +12,7 @@ import (
 	"github.com/souheilbenslama/free-genai-bootcamp-2025/lang-portal/backend-go/internal/api/handlers"
 	"github.com/souheilbenslama/free-genai-bootcamp-2025/lang-portal/backend-go/internal/api/handlers/test"
 	"github.com/souheilbenslama/free-genai-bootcamp-2025/lang-portal/backend-go/internal/models"
+	"github.com/souheilbenslama/free-genai-bootcamp-2025/lang-portal/backend-go/internal/pagination"
 	"github.com/souheilbenslama/free-genai-bootcamp-2025/lang-portal/backend-go/internal/repository/sqlite"
 )
 
@@ -191,18 +192,23 @@ var _ = Describe("WordHandler", func() {
 			})
 
 			It("returns paginated results", func() {
-				req := httptest.NewRequest("GET", "/api/words?limit=2&offset=0", nil)
+				req := httptest.NewRequest("GET", "/api/words?limit=2", nil)
 				w := httptest.NewRecorder()
 				router.ServeHTTP(w, req)
 
 				Expect(w.Code).To(Equal(http.StatusOK))
 
 				var response struct {
-					Items []models.Word `json:"items"`
+					Items      []models.Word   `json:"items"`
+					Pagination pagination.Page `json:"pagination"`
 				}
 				err := json.Unmarshal(w.Body.Bytes(), &response)
 				Expect(err).NotTo(HaveOccurred())
 				Expect(response.Items).To(HaveLen(2))
+				Expect(response.Pagination.TotalItems).To(Equal(3))
+				Expect(response.Pagination.HasMore).To(BeTrue())
+				Expect(response.Pagination.NextCursor).NotTo(BeEmpty())
+				Expect(w.Header().Get("Link")).To(ContainSubstring(`rel="next"`))
 
 				// Verify each word has required fields
 				for _, word := range response.Items {
@@ -211,6 +217,52 @@ var _ = Describe("WordHandler", func() {
 					Expect(word.English).NotTo(BeEmpty())
 				}
 			})
+
+			It("follows the next cursor to the last page", func() {
+				req := httptest.NewRequest("GET", "/api/words?limit=2", nil)
+				w := httptest.NewRecorder()
+				router.ServeHTTP(w, req)
+				Expect(w.Code).To(Equal(http.StatusOK))
+
+				var first struct {
+					Items      []models.Word   `json:"items"`
+					Pagination pagination.Page `json:"pagination"`
+				}
+				Expect(json.Unmarshal(w.Body.Bytes(), &first)).To(Succeed())
+
+				req = httptest.NewRequest("GET", "/api/words?limit=2&cursor="+first.Pagination.NextCursor, nil)
+				w = httptest.NewRecorder()
+				router.ServeHTTP(w, req)
+				Expect(w.Code).To(Equal(http.StatusOK))
+
+				var second struct {
+					Items      []models.Word   `json:"items"`
+					Pagination pagination.Page `json:"pagination"`
+				}
+				Expect(json.Unmarshal(w.Body.Bytes(), &second)).To(Succeed())
+				Expect(second.Items).To(HaveLen(1))
+				Expect(second.Items[0].ID).To(BeNumerically(">", first.Items[1].ID))
+				Expect(second.Pagination.HasMore).To(BeFalse())
+				Expect(second.Pagination.NextCursor).To(BeEmpty())
+			})
+
+			It("rejects out of range limits", func() {
+				for _, limit := range []string{"-1", "0", "100000", "abc"} {
+					req := httptest.NewRequest("GET", "/api/words?limit="+limit, nil)
+					w := httptest.NewRecorder()
+					router.ServeHTTP(w, req)
+
+					Expect(w.Code).To(Equal(http.StatusBadRequest), "limit=%s", limit)
+				}
+			})
+
+			It("rejects malformed cursors", func() {
+				req := httptest.NewRequest("GET", "/api/words?cursor=not-a-cursor", nil)
+				w := httptest.NewRecorder()
+				router.ServeHTTP(w, req)
+
+				Expect(w.Code).To(Equal(http.StatusBadRequest))
+			})
 		})
 	})
 })
@@ -0,0 +1,106 @@
+package middleware
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/souheilbenslama/free-genai-bootcamp-2025/lang-portal/backend-go/internal/auth"
+	"github.com/souheilbenslama/free-genai-bootcamp-2025/lang-portal/backend-go/internal/repository"
+)
+
+// Authenticate requires a valid "Authorization: Bearer <credential>" header,
+// where the credential is an access token or an API key, and stores the
+// caller's principal in the request context. Other requests are answered
+// with 401.
+func Authenticate(tokens *auth.Tokens, keys repository.APIKeyRepository) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		scheme, token, ok := strings.Cut(c.GetHeader("Authorization"), " ")
+		token = strings.TrimSpace(token)
+		if !ok || !strings.EqualFold(scheme, "Bearer") || token == "" {
+			unauthorized(c, "missing bearer token")
+			return
+		}
+
+		var principal auth.Principal
+		var err error
+		if auth.IsAPIKey(token) {
+			principal, err = authenticateKey(c, keys, token)
+		} else {
+			principal, err = tokens.ParseAccess(token)
+		}
+		if errors.Is(err, auth.ErrInvalidToken) || errors.Is(err, repository.ErrInvalidAPIKey) {
+			unauthorized(c, err.Error())
+			return
+		}
+		if err != nil {
+			c.Error(err)
+			c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{"error": "internal server error"})
+			return
+		}
+
+		c.Request = c.Request.WithContext(auth.WithPrincipal(c.Request.Context(), principal))
+		c.Next()
+	}
+}
+
+// authenticateKey resolves an API key to its owner, limited to its scopes.
+func authenticateKey(c *gin.Context, keys repository.APIKeyRepository, token string) (auth.Principal, error) {
+	key, role, err := keys.UseAPIKey(c.Request.Context(), auth.HashAPIKey(token))
+	if err != nil {
+		return auth.Principal{}, err
+	}
+
+	principal := auth.Principal{UserID: key.UserID, Role: auth.Role(role), APIKeyID: key.ID}
+	for _, scope := range key.Scopes {
+		principal.Scopes = append(principal.Scopes, auth.Scope(scope))
+	}
+	return principal, nil
+}
+
+// Require lets the request through only when the authenticated principal
+// holds perm, and answers with a 403 problem response otherwise. It must run
+// after Authenticate.
+func Require(perm auth.Permission) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		principal, ok := auth.PrincipalFrom(c.Request.Context())
+		if !ok {
+			unauthorized(c, "missing bearer token")
+			return
+		}
+		if !principal.Can(perm) {
+			forbidden(c, principal, perm)
+			return
+		}
+		c.Next()
+	}
+}
+
+// ProblemContentType is the media type of RFC 7807 problem responses.
+const ProblemContentType = "application/problem+json"
+
+// forbidden answers with an RFC 7807 problem document naming the missing
+// permission. The error member keeps it readable by clients that only know
+// the API's usual error shape.
+func forbidden(c *gin.Context, principal auth.Principal, perm auth.Permission) {
+	detail := fmt.Sprintf("role %s does not have the %s permission", principal.Role, perm)
+	if principal.Role.Can(perm) {
+		detail = fmt.Sprintf("the scopes of API key %d do not grant the %s permission", principal.APIKeyID, perm)
+	}
+	c.Header("Content-Type", ProblemContentType)
+	c.AbortWithStatusJSON(http.StatusForbidden, gin.H{
+		"type":                "about:blank",
+		"title":               http.StatusText(http.StatusForbidden),
+		"status":              http.StatusForbidden,
+		"detail":              detail,
+		"required_permission": perm,
+		"error":               detail,
+	})
+}
+
+func unauthorized(c *gin.Context, message string) {
+	c.Header("WWW-Authenticate", `Bearer realm="lang-portal"`)
+	c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": message})
+}
@@ -0,0 +1,117 @@
+package middleware
+
+import (
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// ExposedHeaders are the response headers browsers let the frontend read.
+var ExposedHeaders = []string{
+	"Link", "X-Total-Count", "X-Request-ID", "Retry-After",
+	"RateLimit-Policy", "RateLimit-Limit", "RateLimit-Remaining", "RateLimit-Reset",
+}
+
+// CORSPolicy decides which browser origins may call the API.
+type CORSPolicy struct {
+	// AllowedOrigins lists origins like http://localhost:5173; "*" allows
+	// any origin.
+	AllowedOrigins   []string
+	AllowedMethods   []string
+	AllowedHeaders   []string
+	ExposedHeaders   []string
+	AllowCredentials bool
+	// MaxAge is how long browsers may cache a preflight answer.
+	MaxAge time.Duration
+}
+
+func (p CORSPolicy) allowsOrigin(origin string) bool {
+	return slices.Contains(p.AllowedOrigins, "*") || slices.Contains(p.AllowedOrigins, origin)
+}
+
+func (p CORSPolicy) allowsMethod(method string) bool {
+	return method == http.MethodOptions || slices.Contains(p.AllowedMethods, strings.ToUpper(method))
+}
+
+func (p CORSPolicy) allowsHeaders(requested string) bool {
+	for _, header := range strings.Split(requested, ",") {
+		header = strings.TrimSpace(header)
+		if header == "" {
+			continue
+		}
+		if !slices.ContainsFunc(p.AllowedHeaders, func(allowed string) bool { return strings.EqualFold(allowed, header) }) {
+			return false
+		}
+	}
+	return true
+}
+
+// CORS applies policy to cross-origin requests. It answers preflight
+// requests itself, with 204 when the origin, method and headers are allowed
+// and 403 otherwise, so it must run before routing. Requests without an
+// Origin header are left alone.
+func CORS(policy CORSPolicy) gin.HandlerFunc {
+	methods := strings.Join(policy.AllowedMethods, ", ")
+	headers := strings.Join(policy.AllowedHeaders, ", ")
+	exposed := strings.Join(policy.ExposedHeaders, ", ")
+	maxAge := strconv.Itoa(int(policy.MaxAge.Seconds()))
+
+	return func(c *gin.Context) {
+		origin := c.GetHeader("Origin")
+		header := c.Writer.Header()
+		header.Add("Vary", "Origin")
+		if origin == "" {
+			c.Next()
+			return
+		}
+
+		preflight := c.Request.Method == http.MethodOptions && c.GetHeader("Access-Control-Request-Method") != ""
+		if !policy.allowsOrigin(origin) {
+			if preflight {
+				c.AbortWithStatusJSON(http.StatusForbidden, gin.H{"error": "origin " + origin + " is not allowed"})
+				return
+			}
+			c.Next()
+			return
+		}
+
+		// Echo the origin rather than "*", which browsers refuse together
+		// with credentials
+		header.Set("Access-Control-Allow-Origin", origin)
+		if policy.AllowCredentials {
+			header.Set("Access-Control-Allow-Credentials", "true")
+		}
+
+		if !preflight {
+			if exposed != "" {
+				header.Set("Access-Control-Expose-Headers", exposed)
+			}
+			c.Next()
+			return
+		}
+
+		header.Add("Vary", "Access-Control-Request-Method")
+		header.Add("Vary", "Access-Control-Request-Headers")
+		if !policy.allowsMethod(c.GetHeader("Access-Control-Request-Method")) {
+			c.AbortWithStatusJSON(http.StatusForbidden, gin.H{"error": "method is not allowed"})
+			return
+		}
+		if !policy.allowsHeaders(c.GetHeader("Access-Control-Request-Headers")) {
+			c.AbortWithStatusJSON(http.StatusForbidden, gin.H{"error": "request headers are not allowed"})
+			return
+		}
+
+		header.Set("Access-Control-Allow-Methods", methods)
+		if headers != "" {
+			header.Set("Access-Control-Allow-Headers", headers)
+		}
+		if policy.MaxAge > 0 {
+			header.Set("Access-Control-Max-Age", maxAge)
+		}
+		c.AbortWithStatus(http.StatusNoContent)
+	}
+}
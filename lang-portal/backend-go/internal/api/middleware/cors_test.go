@@ -0,0 +1,107 @@
+package middleware_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"github.com/souheilbenslama/free-genai-bootcamp-2025/lang-portal/backend-go/internal/api/middleware"
+)
+
+var _ = Describe("CORS", func() {
+	const frontend = "http://localhost:5173"
+
+	var (
+		router *gin.Engine
+		policy middleware.CORSPolicy
+	)
+
+	BeforeEach(func() {
+		gin.SetMode(gin.TestMode)
+		policy = middleware.CORSPolicy{
+			AllowedOrigins: []string{frontend},
+			AllowedMethods: []string{"GET", "POST", "PUT", "DELETE"},
+			AllowedHeaders: []string{"Authorization", "Content-Type"},
+			ExposedHeaders: middleware.ExposedHeaders,
+			MaxAge:         10 * time.Minute,
+		}
+	})
+
+	serve := func(req *http.Request) *httptest.ResponseRecorder {
+		router = gin.New()
+		router.Use(middleware.CORS(policy))
+		router.GET("/api/words", func(c *gin.Context) { c.JSON(http.StatusOK, gin.H{"items": []string{}}) })
+		router.POST("/api/words", func(c *gin.Context) { c.JSON(http.StatusOK, gin.H{}) })
+
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		return w
+	}
+
+	preflight := func(origin, method, headers string) *http.Request {
+		req := httptest.NewRequest(http.MethodOptions, "/api/words", nil)
+		req.Header.Set("Origin", origin)
+		req.Header.Set("Access-Control-Request-Method", method)
+		if headers != "" {
+			req.Header.Set("Access-Control-Request-Headers", headers)
+		}
+		return req
+	}
+
+	It("answers preflights from allowed origins", func() {
+		w := serve(preflight(frontend, http.MethodPost, "authorization, content-type"))
+
+		Expect(w.Code).To(Equal(http.StatusNoContent))
+		Expect(w.Header().Get("Access-Control-Allow-Origin")).To(Equal(frontend))
+		Expect(w.Header().Get("Access-Control-Allow-Methods")).To(Equal("GET, POST, PUT, DELETE"))
+		Expect(w.Header().Get("Access-Control-Allow-Headers")).To(Equal("Authorization, Content-Type"))
+		Expect(w.Header().Get("Access-Control-Max-Age")).To(Equal("600"))
+		Expect(w.Header().Get("Access-Control-Allow-Credentials")).To(BeEmpty())
+		Expect(w.Header().Values("Vary")).To(ContainElements("Origin", "Access-Control-Request-Method"))
+	})
+
+	It("refuses preflights for other origins, methods or headers", func() {
+		Expect(serve(preflight("https://evil.example", http.MethodGet, "")).Code).To(Equal(http.StatusForbidden))
+		Expect(serve(preflight(frontend, http.MethodPatch, "")).Code).To(Equal(http.StatusForbidden))
+		Expect(serve(preflight(frontend, http.MethodGet, "X-Custom")).Code).To(Equal(http.StatusForbidden))
+	})
+
+	It("marks actual requests from allowed origins readable", func() {
+		req := httptest.NewRequest(http.MethodGet, "/api/words", nil)
+		req.Header.Set("Origin", frontend)
+		w := serve(req)
+
+		Expect(w.Code).To(Equal(http.StatusOK))
+		Expect(w.Header().Get("Access-Control-Allow-Origin")).To(Equal(frontend))
+		Expect(w.Header().Get("Access-Control-Expose-Headers")).To(ContainSubstring("X-Total-Count"))
+	})
+
+	It("leaves requests from other origins without CORS headers", func() {
+		req := httptest.NewRequest(http.MethodGet, "/api/words", nil)
+		req.Header.Set("Origin", "https://evil.example")
+		w := serve(req)
+
+		Expect(w.Code).To(Equal(http.StatusOK))
+		Expect(w.Header().Get("Access-Control-Allow-Origin")).To(BeEmpty())
+		Expect(w.Header().Get("Vary")).To(Equal("Origin"))
+	})
+
+	It("echoes any origin for the wildcard", func() {
+		policy.AllowedOrigins = []string{"*"}
+		w := serve(preflight("https://partner.example", http.MethodGet, ""))
+
+		Expect(w.Code).To(Equal(http.StatusNoContent))
+		Expect(w.Header().Get("Access-Control-Allow-Origin")).To(Equal("https://partner.example"))
+	})
+
+	It("allows credentials when asked", func() {
+		policy.AllowCredentials = true
+		w := serve(preflight(frontend, http.MethodGet, ""))
+
+		Expect(w.Code).To(Equal(http.StatusNoContent))
+		Expect(w.Header().Get("Access-Control-Allow-Credentials")).To(Equal("true"))
+	})
+})
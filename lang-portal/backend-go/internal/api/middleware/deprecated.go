@@ -0,0 +1,18 @@
+package middleware
+
+import (
+	"fmt"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Deprecated marks the responses of a route kept for older clients with the
+// Deprecation header and links to the route that replaces it.
+func Deprecated(successor string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		c.Header("Deprecation", "true")
+		// Added rather than set, next to the links of the route itself
+		c.Writer.Header().Add("Link", fmt.Sprintf(`<%s>; rel="successor-version"`, successor))
+		c.Next()
+	}
+}
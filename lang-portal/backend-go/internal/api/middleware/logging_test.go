@@ -0,0 +1,110 @@
+package middleware_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+
+	"github.com/gin-gonic/gin"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"github.com/souheilbenslama/free-genai-bootcamp-2025/lang-portal/backend-go/internal/api/middleware"
+	"github.com/souheilbenslama/free-genai-bootcamp-2025/lang-portal/backend-go/internal/logging"
+)
+
+var _ = Describe("Request logging", func() {
+	var (
+		router *gin.Engine
+		logs   *bytes.Buffer
+	)
+
+	BeforeEach(func() {
+		gin.SetMode(gin.TestMode)
+		logs = &bytes.Buffer{}
+		logger := logging.New("debug", logs)
+
+		router = gin.New()
+		router.Use(middleware.RequestID(), middleware.Logger(logger), middleware.Recovery(logger))
+
+		router.GET("/api/words/:id", func(c *gin.Context) {
+			logger.InfoContext(c.Request.Context(), "inside handler")
+			c.JSON(http.StatusOK, gin.H{"id": c.Param("id")})
+		})
+		router.GET("/api/broken", func(c *gin.Context) {
+			c.Error(errors.New("no such table: words"))
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "internal server error"})
+		})
+		router.GET("/api/panic", func(c *gin.Context) {
+			panic("boom")
+		})
+	})
+
+	logLines := func() []map[string]interface{} {
+		var lines []map[string]interface{}
+		decoder := json.NewDecoder(logs)
+		for decoder.More() {
+			var line map[string]interface{}
+			Expect(decoder.Decode(&line)).To(Succeed())
+			lines = append(lines, line)
+		}
+		return lines
+	}
+
+	It("echoes a client supplied request ID", func() {
+		req := httptest.NewRequest(http.MethodGet, "/api/words/1", nil)
+		req.Header.Set(middleware.RequestIDHeader, "client-id-123")
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+
+		Expect(w.Header().Get(middleware.RequestIDHeader)).To(Equal("client-id-123"))
+	})
+
+	It("generates a request ID when the client sends none or a malformed one", func() {
+		for _, header := range []string{"", "has spaces in it"} {
+			req := httptest.NewRequest(http.MethodGet, "/api/words/1", nil)
+			req.Header.Set(middleware.RequestIDHeader, header)
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+
+			Expect(w.Header().Get(middleware.RequestIDHeader)).To(MatchRegexp("^[0-9a-f]{32}$"))
+		}
+	})
+
+	It("tags every log line of the request with its ID", func() {
+		req := httptest.NewRequest(http.MethodGet, "/api/words/7", nil)
+		req.Header.Set(middleware.RequestIDHeader, "abc")
+		router.ServeHTTP(httptest.NewRecorder(), req)
+
+		lines := logLines()
+		Expect(lines).To(HaveLen(2))
+		Expect(lines[0]["msg"]).To(Equal("inside handler"))
+		Expect(lines[0]["request_id"]).To(Equal("abc"))
+
+		Expect(lines[1]["msg"]).To(Equal("request"))
+		Expect(lines[1]["request_id"]).To(Equal("abc"))
+		Expect(lines[1]["route"]).To(Equal("/api/words/:id"))
+		Expect(lines[1]["path"]).To(Equal("/api/words/7"))
+		Expect(lines[1]["status"]).To(BeNumerically("==", http.StatusOK))
+		Expect(lines[1]).To(HaveKey("latency_ms"))
+	})
+
+	It("logs the error cause of failed requests", func() {
+		router.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/api/broken", nil))
+
+		lines := logLines()
+		Expect(lines).To(HaveLen(1))
+		Expect(lines[0]["level"]).To(Equal("ERROR"))
+		Expect(lines[0]["error"]).To(Equal("no such table: words"))
+	})
+
+	It("recovers from panics", func() {
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/api/panic", nil))
+
+		Expect(w.Code).To(Equal(http.StatusInternalServerError))
+		Expect(w.Body.String()).NotTo(ContainSubstring("boom"))
+		Expect(logs.String()).To(ContainSubstring(`"panic":"boom"`))
+	})
+})
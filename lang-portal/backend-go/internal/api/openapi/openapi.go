@@ -0,0 +1,42 @@
+package openapi
+
+import (
+	_ "embed"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Spec is the OpenAPI 3 document describing every route in routes.SetupRoutes.
+//
+//go:embed openapi.yaml
+var Spec []byte
+
+const docsPage = `<!DOCTYPE html>
+<html lang="en">
+<head>
+  <meta charset="utf-8">
+  <title>Language Learning Portal API</title>
+  <link rel="stylesheet" href="https://unpkg.com/swagger-ui-dist@5/swagger-ui.css">
+</head>
+<body>
+  <div id="swagger-ui"></div>
+  <script src="https://unpkg.com/swagger-ui-dist@5/swagger-ui-bundle.js" crossorigin></script>
+  <script>
+    window.onload = function () {
+      window.ui = SwaggerUIBundle({ url: "/api/openapi.yaml", dom_id: "#swagger-ui" });
+    };
+  </script>
+</body>
+</html>
+`
+
+// ServeSpec returns the raw OpenAPI document.
+func ServeSpec(c *gin.Context) {
+	c.Data(http.StatusOK, "application/yaml", Spec)
+}
+
+// ServeDocs renders the OpenAPI document with Swagger UI.
+func ServeDocs(c *gin.Context) {
+	c.Data(http.StatusOK, "text/html; charset=utf-8", []byte(docsPage))
+}
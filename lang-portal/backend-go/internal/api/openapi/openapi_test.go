@@ -0,0 +1,393 @@
+package openapi_test
+
+import (
+	"bytes"
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/getkin/kin-openapi/openapi3"
+	"github.com/getkin/kin-openapi/openapi3filter"
+	"github.com/getkin/kin-openapi/routers"
+	"github.com/getkin/kin-openapi/routers/legacy"
+	"github.com/gin-gonic/gin"
+	_ "github.com/mattn/go-sqlite3"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"github.com/souheilbenslama/free-genai-bootcamp-2025/lang-portal/backend-go/database"
+	"github.com/souheilbenslama/free-genai-bootcamp-2025/lang-portal/backend-go/internal/api/handlers"
+	"github.com/souheilbenslama/free-genai-bootcamp-2025/lang-portal/backend-go/internal/api/middleware"
+	"github.com/souheilbenslama/free-genai-bootcamp-2025/lang-portal/backend-go/internal/api/openapi"
+	"github.com/souheilbenslama/free-genai-bootcamp-2025/lang-portal/backend-go/internal/api/routes"
+	"github.com/souheilbenslama/free-genai-bootcamp-2025/lang-portal/backend-go/internal/auth"
+	"github.com/souheilbenslama/free-genai-bootcamp-2025/lang-portal/backend-go/internal/migrate"
+	"github.com/souheilbenslama/free-genai-bootcamp-2025/lang-portal/backend-go/internal/models"
+	"github.com/souheilbenslama/free-genai-bootcamp-2025/lang-portal/backend-go/internal/repository/sqlite"
+	"github.com/souheilbenslama/free-genai-bootcamp-2025/lang-portal/backend-go/internal/seeder"
+	"github.com/souheilbenslama/free-genai-bootcamp-2025/lang-portal/backend-go/internal/streak"
+)
+
+const (
+	baseURL = "http://localhost:8080"
+
+	// The contract user, an admin, and a refresh token stored for them in BeforeAll
+	userEmail    = "learner@example.com"
+	userPassword = "correct horse battery"
+	refreshToken = "contract-refresh-token"
+)
+
+var ginParam = regexp.MustCompile(`:(\w+)`)
+
+var _ = Describe("OpenAPI contract", Ordered, func() {
+	var (
+		doc        *openapi3.T
+		specRouter routers.Router
+		router     *gin.Engine
+		bearer     string
+		tokens     *auth.Tokens
+	)
+
+	BeforeAll(func() {
+		var err error
+		doc, err = openapi3.NewLoader().LoadFromData(openapi.Spec)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(doc.Validate(context.Background())).To(Succeed())
+
+		specRouter, err = legacy.NewRouter(doc)
+		Expect(err).NotTo(HaveOccurred())
+
+		db := setupSeededDB()
+		DeferCleanup(db.Close)
+
+		users := sqlite.NewUserRepository(db)
+		hash, err := auth.HashPassword(userPassword)
+		Expect(err).NotTo(HaveOccurred())
+		user := &models.User{Email: userEmail, Name: "Learner", PasswordHash: hash, Role: string(auth.RoleAdmin)}
+		Expect(users.CreateUser(context.Background(), user)).To(Succeed())
+		Expect(users.CreateRefreshToken(context.Background(), user.ID, auth.HashRefresh(refreshToken), time.Now().Add(time.Hour))).To(Succeed())
+
+		tokens = auth.NewTokens([]byte("contract-secret-contract-secret-x"), time.Hour, time.Hour)
+		access, err := tokens.IssueAccess(auth.Principal{UserID: user.ID, Role: auth.RoleAdmin})
+		Expect(err).NotTo(HaveOccurred())
+		bearer = "Bearer " + access
+
+		gin.SetMode(gin.TestMode)
+		router = gin.New()
+		routes.SetupRoutes(router, routes.Handlers{
+			Word:        handlers.NewWordHandler(sqlite.NewWordRepository(db)),
+			Group:       handlers.NewGroupHandler(sqlite.NewGroupRepository(db)),
+			Study:       handlers.NewStudyHandler(sqlite.NewStudyRepository(db), streak.NewService(sqlite.NewStudyRepository(db), nil)),
+			Question:    handlers.NewQuestionHandler(sqlite.NewStudyRepository(db), sqlite.NewGroupRepository(db)),
+			Answer:      handlers.NewAnswerHandler(sqlite.NewStudyRepository(db), 1),
+			Health:      handlers.NewHealthHandler(db, database.Migrations(), true),
+			Auth:        handlers.NewAuthHandler(users, tokens),
+			Admin:       handlers.NewAdminHandler(users, sqlite.NewAPIKeyRepository(db)),
+			RequireUser: middleware.Authenticate(tokens, sqlite.NewAPIKeyRepository(db)),
+		})
+	})
+
+	// validate checks the recorded response against the operation of req
+	validate := func(req *http.Request, w *httptest.ResponseRecorder, body string) {
+		route, pathParams, err := specRouter.FindRoute(req)
+		Expect(err).NotTo(HaveOccurred())
+
+		// Rebuild the request body, the handler has already consumed it
+		if body != "" {
+			req.Body = io.NopCloser(strings.NewReader(body))
+		}
+		requestInput := &openapi3filter.RequestValidationInput{
+			Request:    req,
+			PathParams: pathParams,
+			Route:      route,
+		}
+
+		options := &openapi3filter.Options{}
+		if !strings.Contains(w.Header().Get("Content-Type"), "json") {
+			options.ExcludeResponseBody = true
+		}
+
+		err = openapi3filter.ValidateResponse(context.Background(), &openapi3filter.ResponseValidationInput{
+			RequestValidationInput: requestInput,
+			Status:                 w.Code,
+			Header:                 w.Header(),
+			Body:                   io.NopCloser(bytes.NewReader(w.Body.Bytes())),
+			Options:                options,
+		})
+		Expect(err).NotTo(HaveOccurred())
+	}
+
+	It("documents every registered route and nothing else", func() {
+		registered := map[string]bool{}
+		for _, route := range router.Routes() {
+			path := ginParam.ReplaceAllString(route.Path, "{$1}")
+			registered[route.Method+" "+path] = true
+		}
+
+		documented := map[string]bool{}
+		for path, item := range doc.Paths.Map() {
+			for method := range item.Operations() {
+				documented[method+" "+path] = true
+			}
+		}
+
+		Expect(documented).To(Equal(registered))
+	})
+
+	DescribeTable("responses match the schema",
+		func(method, path, body string, expectedCode int) {
+			var reader io.Reader
+			if body != "" {
+				reader = strings.NewReader(body)
+			}
+			req := httptest.NewRequest(method, baseURL+path, reader)
+			if body != "" {
+				req.Header.Set("Content-Type", "application/json")
+			}
+			req.Header.Set("Authorization", bearer)
+
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+			Expect(w.Code).To(Equal(expectedCode), w.Body.String())
+
+			validate(req, w, body)
+		},
+		Entry("register", http.MethodPost, "/api/auth/register", `{"email":"new@example.com","name":"New","password":"long enough"}`, http.StatusCreated),
+		Entry("register taken email", http.MethodPost, "/api/auth/register", `{"email":"learner@example.com","name":"Again","password":"long enough"}`, http.StatusConflict),
+		Entry("register in a time zone", http.MethodPost, "/api/auth/register", `{"email":"berlin@example.com","name":"Berlin","password":"long enough","timezone":"Europe/Berlin"}`, http.StatusCreated),
+		Entry("register in an unknown time zone", http.MethodPost, "/api/auth/register", `{"email":"mars@example.com","name":"Mars","password":"long enough","timezone":"Mars/Olympus"}`, http.StatusBadRequest),
+		Entry("register short password", http.MethodPost, "/api/auth/register", `{"email":"short@example.com","name":"Short","password":"short"}`, http.StatusBadRequest),
+		Entry("login", http.MethodPost, "/api/auth/login", `{"email":"learner@example.com","password":"correct horse battery"}`, http.StatusOK),
+		Entry("login wrong password", http.MethodPost, "/api/auth/login", `{"email":"learner@example.com","password":"wrong"}`, http.StatusUnauthorized),
+		Entry("refresh", http.MethodPost, "/api/auth/refresh", `{"refresh_token":"contract-refresh-token"}`, http.StatusOK),
+		Entry("refresh used token", http.MethodPost, "/api/auth/refresh", `{"refresh_token":"contract-refresh-token"}`, http.StatusUnauthorized),
+		Entry("logout", http.MethodPost, "/api/auth/logout", `{"refresh_token":"contract-refresh-token"}`, http.StatusNoContent),
+		Entry("current user", http.MethodGet, "/api/auth/me", "", http.StatusOK),
+		Entry("set time zone", http.MethodPut, "/api/auth/me/timezone", `{"timezone":"America/New_York"}`, http.StatusOK),
+		Entry("set unknown time zone", http.MethodPut, "/api/auth/me/timezone", `{"timezone":"Local"}`, http.StatusBadRequest),
+		Entry("list words", http.MethodGet, "/api/words", "", http.StatusOK),
+		Entry("list words page", http.MethodGet, "/api/words?limit=2", "", http.StatusOK),
+		Entry("list words with bad limit", http.MethodGet, "/api/words?limit=0", "", http.StatusBadRequest),
+		Entry("get word", http.MethodGet, "/api/words/1", "", http.StatusOK),
+		Entry("get missing word", http.MethodGet, "/api/words/9999", "", http.StatusNotFound),
+		Entry("create word", http.MethodPost, "/api/words", `{"german":"Baum","english":"tree","parts":"{\"article\":\"der\"}"}`, http.StatusOK),
+		Entry("create invalid word", http.MethodPost, "/api/words", `{"german":"Baum"}`, http.StatusBadRequest),
+		Entry("update word", http.MethodPut, "/api/words/1", `{"german":"Haus","english":"house","parts":"{}"}`, http.StatusOK),
+		Entry("list groups", http.MethodGet, "/api/groups", "", http.StatusOK),
+		Entry("get group", http.MethodGet, "/api/groups/1", "", http.StatusOK),
+		Entry("get missing group", http.MethodGet, "/api/groups/9999", "", http.StatusNotFound),
+		Entry("create group", http.MethodPost, "/api/groups", `{"name":"Trees","description":"Forest words"}`, http.StatusCreated),
+		Entry("update group", http.MethodPut, "/api/groups/1", `{"name":"Basics","description":"Renamed"}`, http.StatusOK),
+		Entry("add word to group", http.MethodPost, "/api/groups/4/words", `{"word_id":1}`, http.StatusOK),
+		Entry("add missing word to group", http.MethodPost, "/api/groups/4/words", `{"word_id":9999}`, http.StatusNotFound),
+		Entry("remove word from group", http.MethodDelete, "/api/groups/4/words/1", "", http.StatusOK),
+		Entry("start study session", http.MethodPost, "/api/study_sessions", `{"group_id":1}`, http.StatusCreated),
+		Entry("next due word", http.MethodGet, "/api/study_sessions/1/next?strategy=due", "", http.StatusOK),
+		Entry("next weakest word", http.MethodGet, "/api/study_sessions/1/next?strategy=weakest", "", http.StatusOK),
+		Entry("next word by unknown strategy", http.MethodGet, "/api/study_sessions/1/next?strategy=hardest", "", http.StatusBadRequest),
+		Entry("next word in missing session", http.MethodGet, "/api/study_sessions/9999/next", "", http.StatusNotFound),
+		Entry("record word review", http.MethodPost, "/api/study_sessions/1/reviews", `{"word_id":1,"correct":true}`, http.StatusNoContent),
+		Entry("record incorrect word review", http.MethodPost, "/api/study_sessions/1/reviews", `{"word_id":2,"correct":false}`, http.StatusNoContent),
+		Entry("record review of a word not issued", http.MethodPost, "/api/study_sessions/1/reviews", `{"word_id":3,"correct":true}`, http.StatusBadRequest),
+		Entry("record review in missing session", http.MethodPost, "/api/study_sessions/9999/reviews", `{"word_id":1,"correct":true}`, http.StatusNotFound),
+		Entry("finish study session", http.MethodPost, "/api/study_sessions/1/finish", "", http.StatusOK),
+		Entry("finish finished study session", http.MethodPost, "/api/study_sessions/1/finish", "", http.StatusConflict),
+		Entry("finish missing study session", http.MethodPost, "/api/study_sessions/9999/finish", "", http.StatusNotFound),
+		Entry("record review in finished session", http.MethodPost, "/api/study_sessions/1/reviews", `{"word_id":3,"correct":true}`, http.StatusConflict),
+		Entry("next word in finished session", http.MethodGet, "/api/study_sessions/1/next", "", http.StatusConflict),
+		Entry("start study session in a small group", http.MethodPost, "/api/study_sessions", `{"group_id":2}`, http.StatusCreated),
+		Entry("next shuffled word", http.MethodGet, "/api/study_sessions/2/next", "", http.StatusOK),
+		Entry("next shuffled word again", http.MethodGet, "/api/study_sessions/2/next", "", http.StatusOK),
+		Entry("next word when none are left", http.MethodGet, "/api/study_sessions/2/next", "", http.StatusNoContent),
+		Entry("start study session for questions", http.MethodPost, "/api/study_sessions", `{"group_id":3}`, http.StatusCreated),
+		Entry("multiple choice question", http.MethodGet, "/api/study_sessions/3/questions?type=mc&strategy=weakest", "", http.StatusOK),
+		Entry("question of unknown type", http.MethodGet, "/api/study_sessions/3/questions?type=essay", "", http.StatusBadRequest),
+		Entry("question in missing session", http.MethodGet, "/api/study_sessions/9999/questions", "", http.StatusNotFound),
+		Entry("answer multiple choice question", http.MethodPost, "/api/study_sessions/3/reviews", `{"word_id":4,"chosen_word_id":5}`, http.StatusNoContent),
+		Entry("another multiple choice question", http.MethodGet, "/api/study_sessions/3/questions?strategy=weakest", "", http.StatusOK),
+		Entry("answer with an option not offered", http.MethodPost, "/api/study_sessions/3/reviews", `{"word_id":5,"chosen_word_id":3}`, http.StatusBadRequest),
+		Entry("answer contradicting the chosen option", http.MethodPost, "/api/study_sessions/3/reviews", `{"word_id":5,"chosen_word_id":5,"correct":false}`, http.StatusBadRequest),
+		Entry("answer with neither choice nor correctness", http.MethodPost, "/api/study_sessions/3/reviews", `{"word_id":5}`, http.StatusBadRequest),
+		Entry("last multiple choice question", http.MethodGet, "/api/study_sessions/3/questions", "", http.StatusOK),
+		Entry("question when none are left", http.MethodGet, "/api/study_sessions/3/questions", "", http.StatusNoContent),
+		Entry("answer article question about a word without article", http.MethodPost, "/api/study_sessions/3/article_reviews", `{"word_id":1,"article":"das"}`, http.StatusBadRequest),
+		Entry("start study session for article questions", http.MethodPost, "/api/study_sessions", `{"group_id":2}`, http.StatusCreated),
+		Entry("article question", http.MethodGet, "/api/study_sessions/4/questions?type=article&strategy=weakest", "", http.StatusOK),
+		Entry("answer article question wrong", http.MethodPost, "/api/study_sessions/4/article_reviews", `{"word_id":2,"article":"der"}`, http.StatusCreated),
+		Entry("answer with an unknown article", http.MethodPost, "/api/study_sessions/4/article_reviews", `{"word_id":2,"article":"den"}`, http.StatusBadRequest),
+		Entry("answer article of a word not issued", http.MethodPost, "/api/study_sessions/4/article_reviews", `{"word_id":3,"article":"der"}`, http.StatusBadRequest),
+		Entry("answer article in missing session", http.MethodPost, "/api/study_sessions/9999/article_reviews", `{"word_id":2,"article":"die"}`, http.StatusNotFound),
+		Entry("another article question", http.MethodGet, "/api/study_sessions/4/questions?type=article&strategy=weakest", "", http.StatusOK),
+		Entry("answer article question", http.MethodPost, "/api/study_sessions/4/article_reviews", `{"word_id":3,"article":"der"}`, http.StatusCreated),
+		Entry("article question when none are left", http.MethodGet, "/api/study_sessions/4/questions?type=article", "", http.StatusNoContent),
+		Entry("answer plural question about a word without plural", http.MethodPost, "/api/study_sessions/3/plural_reviews", `{"word_id":1,"answer":"Häuser"}`, http.StatusBadRequest),
+		Entry("start study session for plural questions", http.MethodPost, "/api/study_sessions", `{"group_id":2}`, http.StatusCreated),
+		Entry("plural question", http.MethodGet, "/api/study_sessions/5/questions?type=plural&strategy=weakest", "", http.StatusOK),
+		Entry("answer plural question wrong", http.MethodPost, "/api/study_sessions/5/plural_reviews", `{"word_id":2,"answer":"Katze"}`, http.StatusCreated),
+		Entry("answer plural question without answer", http.MethodPost, "/api/study_sessions/5/plural_reviews", `{"word_id":2}`, http.StatusBadRequest),
+		Entry("answer plural of a word not issued", http.MethodPost, "/api/study_sessions/5/plural_reviews", `{"word_id":3,"answer":"Hunde"}`, http.StatusBadRequest),
+		Entry("another plural question", http.MethodGet, "/api/study_sessions/5/questions?type=plural&strategy=weakest", "", http.StatusOK),
+		Entry("answer plural question", http.MethodPost, "/api/study_sessions/5/plural_reviews", `{"word_id":3,"answer":"die Hunde"}`, http.StatusCreated),
+		Entry("plural question when none are left", http.MethodGet, "/api/study_sessions/5/questions?type=plural", "", http.StatusNoContent),
+		Entry("answer a word issued for another drill", http.MethodPost, "/api/study_sessions/5/answers", `{"word_id":2,"answer":"die Katze"}`, http.StatusBadRequest),
+		Entry("next word to answer", http.MethodGet, "/api/study_sessions/5/next", "", http.StatusOK),
+		Entry("next word to answer again", http.MethodGet, "/api/study_sessions/5/next", "", http.StatusOK),
+		Entry("answer article of a noun issued for other drills", http.MethodPost, "/api/study_sessions/5/article_reviews", `{"word_id":2,"article":"die"}`, http.StatusBadRequest),
+		Entry("answer plural of a noun issued for other drills", http.MethodPost, "/api/study_sessions/4/plural_reviews", `{"word_id":2,"answer":"Katzen"}`, http.StatusBadRequest),
+		Entry("answer with a typo", http.MethodPost, "/api/study_sessions/5/answers", `{"word_id":2,"answer":"die Kaze"}`, http.StatusCreated),
+		Entry("answer correctly", http.MethodPost, "/api/study_sessions/5/answers", `{"word_id":3,"answer":"der Hund"}`, http.StatusCreated),
+		Entry("answer without answer", http.MethodPost, "/api/study_sessions/5/answers", `{"word_id":3}`, http.StatusBadRequest),
+		Entry("answer for a word not issued", http.MethodPost, "/api/study_sessions/5/answers", `{"word_id":1,"answer":"das Haus"}`, http.StatusBadRequest),
+		Entry("answer in missing session", http.MethodPost, "/api/study_sessions/9999/answers", `{"word_id":1,"answer":"das Haus"}`, http.StatusNotFound),
+		Entry("record another attempt at a word", http.MethodPost, "/api/study_sessions/5/reviews", `{"word_id":2,"correct":true,"answer":"cat","response_ms":1800,"direction":"de_en"}`, http.StatusNoContent),
+		Entry("record attempt in unknown direction", http.MethodPost, "/api/study_sessions/5/reviews", `{"word_id":2,"correct":true,"direction":"fr_de"}`, http.StatusBadRequest),
+		Entry("record attempt with negative response time", http.MethodPost, "/api/study_sessions/5/reviews", `{"word_id":2,"correct":true,"response_ms":-1}`, http.StatusBadRequest),
+		Entry("start study session for a missing group", http.MethodPost, "/api/study_sessions", `{"group_id":9999}`, http.StatusNotFound),
+		Entry("list study sessions", http.MethodGet, "/api/study_sessions", "", http.StatusOK),
+		Entry("list study sessions under the old path", http.MethodGet, "/api/study-sessions", "", http.StatusOK),
+		Entry("last study session", http.MethodGet, "/api/dashboard/last_study_session", "", http.StatusOK),
+		Entry("study progress", http.MethodGet, "/api/dashboard/study_progress", "", http.StatusOK),
+		Entry("quick stats", http.MethodGet, "/api/dashboard/quick_stats", "", http.StatusOK),
+		Entry("article breakdown", http.MethodGet, "/api/dashboard/articles", "", http.StatusOK),
+		Entry("plural breakdown", http.MethodGet, "/api/dashboard/plurals", "", http.StatusOK),
+		Entry("streak", http.MethodGet, "/api/dashboard/streak", "", http.StatusOK),
+		Entry("freeze a day long ago", http.MethodPost, "/api/dashboard/streak/freeze", `{"day":"2020-01-01"}`, http.StatusBadRequest),
+		Entry("freeze a malformed day", http.MethodPost, "/api/dashboard/streak/freeze", `{"day":"yesterday"}`, http.StatusBadRequest),
+		Entry("list groups with progress", http.MethodGet, "/api/groups", "", http.StatusOK),
+		Entry("group progress", http.MethodGet, "/api/groups/2/progress", "", http.StatusOK),
+		Entry("progress of an empty group", http.MethodGet, "/api/groups/4/progress", "", http.StatusOK),
+		Entry("progress of a missing group", http.MethodGet, "/api/groups/9999/progress", "", http.StatusNotFound),
+		Entry("reviews per day", http.MethodGet, "/api/stats/timeseries", "", http.StatusOK),
+		Entry("accuracy per week compared", http.MethodGet, "/api/stats/timeseries?metric=accuracy&bucket=week&compare=true", "", http.StatusOK),
+		Entry("new words of a period", http.MethodGet, "/api/stats/timeseries?metric=new_words&from=2025-01-01&to=2025-01-31&compare=true", "", http.StatusOK),
+		Entry("minutes of a group and drill", http.MethodGet, "/api/stats/timeseries?metric=minutes&group_id=2&drill=plural", "", http.StatusOK),
+		Entry("calendar of this year", http.MethodGet, "/api/stats/calendar", "", http.StatusOK),
+		Entry("calendar of a leap year", http.MethodGet, "/api/stats/calendar?year=2024", "", http.StatusOK),
+		Entry("calendar of a year out of range", http.MethodGet, "/api/stats/calendar?year=1900", "", http.StatusBadRequest),
+		Entry("unknown metric", http.MethodGet, "/api/stats/timeseries?metric=streak", "", http.StatusBadRequest),
+		Entry("period ending before it starts", http.MethodGet, "/api/stats/timeseries?from=2025-02-01&to=2025-01-01", "", http.StatusBadRequest),
+		Entry("delete group", http.MethodDelete, "/api/groups/4", "", http.StatusOK),
+		Entry("delete word", http.MethodDelete, "/api/words/2", "", http.StatusOK),
+		Entry("list users", http.MethodGet, "/api/admin/users", "", http.StatusOK),
+		Entry("set user role", http.MethodPut, "/api/admin/users/2/role", `{"role":"teacher"}`, http.StatusOK),
+		Entry("set unknown role", http.MethodPut, "/api/admin/users/2/role", `{"role":"root"}`, http.StatusBadRequest),
+		Entry("set role of missing user", http.MethodPut, "/api/admin/users/9999/role", `{"role":"teacher"}`, http.StatusNotFound),
+		Entry("demote the last admin", http.MethodPut, "/api/admin/users/1/role", `{"role":"learner"}`, http.StatusConflict),
+		Entry("create API key", http.MethodPost, "/api/admin/api_keys", `{"name":"listening app","scopes":["reviews:write","words:read"]}`, http.StatusCreated),
+		Entry("create API key with unknown scope", http.MethodPost, "/api/admin/api_keys", `{"name":"bad","scopes":["everything"]}`, http.StatusBadRequest),
+		Entry("create API key for missing user", http.MethodPost, "/api/admin/api_keys", `{"name":"bad","scopes":["stats:read"],"user_id":9999}`, http.StatusBadRequest),
+		Entry("list API keys", http.MethodGet, "/api/admin/api_keys", "", http.StatusOK),
+		Entry("revoke API key", http.MethodDelete, "/api/admin/api_keys/1", "", http.StatusOK),
+		Entry("revoke missing API key", http.MethodDelete, "/api/admin/api_keys/9999", "", http.StatusNotFound),
+		Entry("openapi document", http.MethodGet, "/api/openapi.yaml", "", http.StatusOK),
+		Entry("api docs", http.MethodGet, "/api/docs", "", http.StatusOK),
+		Entry("liveness", http.MethodGet, "/healthz", "", http.StatusOK),
+		Entry("readiness", http.MethodGet, "/readyz", "", http.StatusOK),
+		Entry("version", http.MethodGet, "/api/version", "", http.StatusOK),
+		Entry("metrics", http.MethodGet, "/metrics", "", http.StatusOK),
+	)
+
+	It("documents the answer to requests without credentials", func() {
+		req := httptest.NewRequest(http.MethodGet, baseURL+"/api/study_sessions", nil)
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		Expect(w.Code).To(Equal(http.StatusUnauthorized))
+
+		validate(req, w, "")
+	})
+
+	It("accepts API keys within their scopes", func() {
+		send := func(method, path, authorization, body string) (*http.Request, *httptest.ResponseRecorder) {
+			var reader io.Reader
+			if body != "" {
+				reader = strings.NewReader(body)
+			}
+			req := httptest.NewRequest(method, baseURL+path, reader)
+			if body != "" {
+				req.Header.Set("Content-Type", "application/json")
+			}
+			req.Header.Set("Authorization", authorization)
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+			return req, w
+		}
+
+		_, w := send(http.MethodPost, "/api/admin/api_keys", bearer, `{"name":"sentence constructor","scopes":["reviews:write"]}`)
+		Expect(w.Code).To(Equal(http.StatusCreated), w.Body.String())
+		var created struct {
+			ID  int    `json:"id"`
+			Key string `json:"key"`
+		}
+		Expect(json.Unmarshal(w.Body.Bytes(), &created)).To(Succeed())
+		apiKey := "Bearer " + created.Key
+
+		_, w = send(http.MethodPost, "/api/study_sessions", apiKey, `{"group_id":1}`)
+		Expect(w.Code).To(Equal(http.StatusCreated), w.Body.String())
+		var session models.StudySession
+		Expect(json.Unmarshal(w.Body.Bytes(), &session)).To(Succeed())
+		Expect(session.UserID).To(Equal(1))
+
+		_, w = send(http.MethodGet, fmt.Sprintf("/api/study_sessions/%d/next", session.ID), apiKey, "")
+		Expect(w.Code).To(Equal(http.StatusOK), w.Body.String())
+		var item models.SessionItem
+		Expect(json.Unmarshal(w.Body.Bytes(), &item)).To(Succeed())
+
+		body := fmt.Sprintf(`{"word_id":%d,"correct":true}`, item.Word.ID)
+		req, w := send(http.MethodPost, fmt.Sprintf("/api/study_sessions/%d/reviews", session.ID), apiKey, body)
+		Expect(w.Code).To(Equal(http.StatusNoContent), w.Body.String())
+		validate(req, w, body)
+
+		req, w = send(http.MethodGet, "/api/dashboard/quick_stats", apiKey, "")
+		Expect(w.Code).To(Equal(http.StatusForbidden))
+		validate(req, w, "")
+
+		_, w = send(http.MethodDelete, fmt.Sprintf("/api/admin/api_keys/%d", created.ID), bearer, "")
+		Expect(w.Code).To(Equal(http.StatusOK))
+		var revoked models.APIKey
+		Expect(json.Unmarshal(w.Body.Bytes(), &revoked)).To(Succeed())
+		Expect(revoked.LastUsedAt).NotTo(BeNil())
+
+		req, w = send(http.MethodPost, "/api/study_sessions", apiKey, `{"group_id":1}`)
+		Expect(w.Code).To(Equal(http.StatusUnauthorized))
+		validate(req, w, `{"group_id":1}`)
+	})
+
+	It("documents the answer to requests without the permission", func() {
+		access, err := tokens.IssueAccess(auth.Principal{UserID: 2, Role: auth.RoleLearner})
+		Expect(err).NotTo(HaveOccurred())
+
+		body := `{"german":"Baum","english":"tree","parts":"{}"}`
+		req := httptest.NewRequest(http.MethodPost, baseURL+"/api/words", strings.NewReader(body))
+		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set("Authorization", "Bearer "+access)
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		Expect(w.Code).To(Equal(http.StatusForbidden))
+
+		validate(req, w, body)
+	})
+})
+
+func setupSeededDB() *sql.DB {
+	tmpfile, err := os.CreateTemp("", "openapi-*.db")
+	Expect(err).NotTo(HaveOccurred())
+	DeferCleanup(os.Remove, tmpfile.Name())
+
+	db, err := sql.Open("sqlite3", tmpfile.Name())
+	Expect(err).NotTo(HaveOccurred())
+
+	_, err = migrate.Apply(context.Background(), db, database.Migrations())
+	Expect(err).NotTo(HaveOccurred())
+
+	_, err = seeder.LoadSeedFS(db, database.Seed())
+	Expect(err).NotTo(HaveOccurred())
+
+	return db
+}
@@ -1,53 +1,199 @@
 package routes
 
 import (
+	"net/http"
+	"strings"
+
 	"github.com/gin-gonic/gin"
 	"github.com/souheilbenslama/free-genai-bootcamp-2025/lang-portal/backend-go/internal/api/handlers"
+	"github.com/souheilbenslama/free-genai-bootcamp-2025/lang-portal/backend-go/internal/api/middleware"
+	"github.com/souheilbenslama/free-genai-bootcamp-2025/lang-portal/backend-go/internal/api/openapi"
+	"github.com/souheilbenslama/free-genai-bootcamp-2025/lang-portal/backend-go/internal/auth"
+	"github.com/souheilbenslama/free-genai-bootcamp-2025/lang-portal/backend-go/internal/metrics"
+	"github.com/souheilbenslama/free-genai-bootcamp-2025/lang-portal/backend-go/internal/ratelimit"
 )
 
-func SetupRoutes(
-	r *gin.Engine,
-	wordHandler *handlers.WordHandler,
-	groupHandler *handlers.GroupHandler,
-	studyHandler *handlers.StudyHandler,
-) {
-	api := r.Group("/api")
+// Handlers holds everything the routes are wired to.
+type Handlers struct {
+	Word     *handlers.WordHandler
+	Group    *handlers.GroupHandler
+	Study    *handlers.StudyHandler
+	Question *handlers.QuestionHandler
+	Answer   *handlers.AnswerHandler
+	Health   *handlers.HealthHandler
+	Auth     *handlers.AuthHandler
+	Admin    *handlers.AdminHandler
+
+	// RequireUser authenticates the caller by access token or API key;
+	// routes that need a permission add middleware.Require after it.
+	RequireUser gin.HandlerFunc
+
+	Limits Limits
+
+	// Frontend serves the single page app on every path the API does not
+	// claim. Nil leaves those paths to gin's 404.
+	Frontend http.Handler
+}
+
+// Limits protect the API from misbehaving clients. The zero value disables
+// them.
+type Limits struct {
+	MaxBodyBytes int64
+	// Default applies to every API route. Auth replaces it on signing in and
+	// the other account routes of anonymous callers, and Reviews on recording
+	// reviews.
+	Default *ratelimit.Limiter
+	Auth    *ratelimit.Limiter
+	Reviews *ratelimit.Limiter
+}
+
+func SetupRoutes(r *gin.Engine, h Handlers) {
+	r.Use(metrics.Middleware())
+
+	// Anonymous callers are limited by IP. Authenticated routes limit after
+	// authentication so each API key and user gets a bucket of its own.
+	public := middleware.RateLimit(h.Limits.Default)
+	authorize := func(limiter *ratelimit.Limiter, perm auth.Permission) []gin.HandlerFunc {
+		return []gin.HandlerFunc{h.RequireUser, middleware.RateLimit(limiter), middleware.Require(perm)}
+	}
+	// identify authenticates callers of public routes that send credentials,
+	// so the answer can include what is theirs
+	identify := func(c *gin.Context) {
+		if c.GetHeader("Authorization") == "" {
+			c.Next()
+			return
+		}
+		h.RequireUser(c)
+	}
+
+	// Probes and metrics for the orchestrator
+	r.GET("/healthz", h.Health.Healthz)
+	r.GET("/readyz", h.Health.Readyz)
+	r.GET("/metrics", gin.WrapH(metrics.Handler()))
+
+	api := r.Group("/api", middleware.MaxBodySize(h.Limits.MaxBodyBytes))
 	{
-		// Word routes
+		api.GET("/version", public, h.Health.Version)
+
+		// Account routes; signing in has a policy of its own against
+		// password guessing, signed in users count against their own bucket
+		account := api.Group("/auth", middleware.RateLimit(h.Limits.Auth))
+		{
+			account.POST("/register", h.Auth.Register)
+			account.POST("/login", h.Auth.Login)
+			account.POST("/refresh", h.Auth.Refresh)
+			account.POST("/logout", h.Auth.Logout)
+		}
+		me := api.Group("/auth/me", authorize(h.Limits.Default, auth.PermReadProgress)...)
+		{
+			me.GET("", h.Auth.Me)
+			me.PUT("/timezone", middleware.Require(auth.PermStudy), h.Auth.SetTimezone)
+		}
+
+		// Word routes: anyone may read, content managers may write
 		words := api.Group("/words")
 		{
-			words.GET("", wordHandler.ListWords)
-			words.GET("/:id", wordHandler.GetWord)
-			words.POST("", wordHandler.CreateWord)
-			words.PUT("/:id", wordHandler.UpdateWord)
-			words.DELETE("/:id", wordHandler.DeleteWord)
+			read := words.Group("", public)
+			read.GET("", h.Word.ListWords)
+			read.GET("/:id", h.Word.GetWord)
+
+			manage := words.Group("", authorize(h.Limits.Default, auth.PermManageContent)...)
+			manage.POST("", h.Word.CreateWord)
+			manage.PUT("/:id", h.Word.UpdateWord)
+			manage.DELETE("/:id", h.Word.DeleteWord)
 		}
 
-		// Group routes
+		// Group routes: anyone may read, content managers may write and
+		// learners read their progress
 		groups := api.Group("/groups")
 		{
-			groups.GET("", groupHandler.GetGroups)
-			groups.GET("/:id", groupHandler.GetGroup)
-			groups.POST("", groupHandler.CreateGroup)
-			groups.PUT("/:id", groupHandler.UpdateGroup)
-			groups.DELETE("/:id", groupHandler.DeleteGroup)
-			groups.POST("/:id/words", groupHandler.AddWordToGroup)
-			groups.DELETE("/:id/words/:word_id", groupHandler.RemoveWordFromGroup)
+			read := groups.Group("", public)
+			read.GET("", identify, h.Group.GetGroups)
+			read.GET("/:id", h.Group.GetGroup)
+
+			progress := groups.Group("", authorize(h.Limits.Default, auth.PermReadProgress)...)
+			progress.GET("/:id/progress", h.Group.GetGroupProgress)
+
+			manage := groups.Group("", authorize(h.Limits.Default, auth.PermManageContent)...)
+			manage.POST("", h.Group.CreateGroup)
+			manage.PUT("/:id", h.Group.UpdateGroup)
+			manage.DELETE("/:id", h.Group.DeleteGroup)
+			manage.POST("/:id/words", h.Group.AddWordToGroup)
+			manage.DELETE("/:id/words/:word_id", h.Group.RemoveWordFromGroup)
 		}
 
 		// Dashboard routes
-		dashboard := api.Group("/dashboard")
+		dashboard := api.Group("/dashboard", authorize(h.Limits.Default, auth.PermReadProgress)...)
+		{
+			dashboard.GET("/last_study_session", h.Study.GetLastStudySession)
+			dashboard.GET("/study_progress", h.Study.GetStudyProgress)
+			dashboard.GET("/quick_stats", h.Study.GetQuickStats)
+			dashboard.GET("/articles", h.Study.GetArticleBreakdown)
+			dashboard.GET("/plurals", h.Study.GetPluralBreakdown)
+			dashboard.GET("/streak", h.Study.GetStreak)
+			dashboard.POST("/streak/freeze", middleware.Require(auth.PermStudy), h.Study.FreezeStreak)
+		}
+
+		// Statistics of the caller's study over time
+		statistics := api.Group("/stats", authorize(h.Limits.Default, auth.PermReadProgress)...)
+		{
+			statistics.GET("/timeseries", h.Study.GetTimeSeries)
+			statistics.GET("/calendar", h.Study.GetCalendar)
+		}
+
+		// Study session routes; reviews have a policy of their own because
+		// activities post them in quick succession
+		study := api.Group("/study_sessions")
 		{
-			dashboard.GET("/last_study_session", studyHandler.GetLastStudySession)
-			dashboard.GET("/study_progress", studyHandler.GetStudyProgress)
-			dashboard.GET("/quick_stats", studyHandler.GetQuickStats)
+			progress := study.Group("", authorize(h.Limits.Default, auth.PermReadProgress)...)
+			progress.GET("", h.Study.ListStudySessions)
+
+			practice := study.Group("", authorize(h.Limits.Default, auth.PermStudy)...)
+			practice.POST("", h.Study.StartStudySession)
+			practice.GET("/:id/next", h.Study.NextItem)
+			practice.GET("/:id/questions", h.Question.GetQuestion)
+			practice.POST("/:id/finish", h.Study.FinishStudySession)
+
+			reviews := study.Group("", authorize(h.Limits.Reviews, auth.PermStudy)...)
+			reviews.POST("/:id/reviews", h.Study.RecordWordReview)
+			reviews.POST("/:id/article_reviews", h.Study.RecordArticleReview)
+			reviews.POST("/:id/plural_reviews", h.Study.RecordPluralReview)
+			reviews.POST("/:id/answers", h.Answer.RecordAnswer)
 		}
 
-		// Study session routes
-		study := api.Group("/study-sessions")
+		// The first release served study sessions under /api/study-sessions.
+		// The alias keeps its routes working for existing clients.
+		legacy := api.Group("/study-sessions", middleware.Deprecated("/api/study_sessions"))
 		{
-			study.POST("", studyHandler.StartStudySession)
-			study.POST("/:session_id/reviews", studyHandler.RecordWordReview)
+			legacy.GET("", append(authorize(h.Limits.Default, auth.PermReadProgress), h.Study.ListStudySessions)...)
+			legacy.POST("", append(authorize(h.Limits.Default, auth.PermStudy), h.Study.StartStudySession)...)
+			legacy.POST("/:id/reviews", append(authorize(h.Limits.Reviews, auth.PermStudy), h.Study.RecordWordReview)...)
 		}
+
+		// Account administration
+		admin := api.Group("/admin", authorize(h.Limits.Default, auth.PermManageUsers)...)
+		{
+			admin.GET("/users", h.Admin.ListUsers)
+			admin.PUT("/users/:id/role", h.Admin.SetUserRole)
+			admin.GET("/api_keys", h.Admin.ListAPIKeys)
+			admin.POST("/api_keys", h.Admin.CreateAPIKey)
+			admin.DELETE("/api_keys/:id", h.Admin.RevokeAPIKey)
+		}
+
+		// API documentation
+		api.GET("/openapi.yaml", public, openapi.ServeSpec)
+		api.GET("/docs", public, openapi.ServeDocs)
+	}
+
+	if h.Frontend != nil {
+		frontend := gin.WrapH(h.Frontend)
+		r.NoRoute(func(c *gin.Context) {
+			// Unknown API paths stay JSON errors instead of loading the app
+			if p := c.Request.URL.Path; p == "/api" || strings.HasPrefix(p, "/api/") {
+				c.JSON(http.StatusNotFound, gin.H{"error": "route not found"})
+				return
+			}
+			frontend(c)
+		})
 	}
 }
@@ -1,18 +1,29 @@
 package routes_test
 
 import (
+	"context"
 	"encoding/json"
 	"net/http"
 	"net/http/httptest"
+	"path/filepath"
 	"strings"
+	"testing/fstest"
+	"time"
 
 	"github.com/gin-gonic/gin"
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
+	"github.com/souheilbenslama/free-genai-bootcamp-2025/lang-portal/backend-go/database"
 	"github.com/souheilbenslama/free-genai-bootcamp-2025/lang-portal/backend-go/internal/api/handlers"
 	"github.com/souheilbenslama/free-genai-bootcamp-2025/lang-portal/backend-go/internal/api/handlers/test"
+	"github.com/souheilbenslama/free-genai-bootcamp-2025/lang-portal/backend-go/internal/api/middleware"
 	"github.com/souheilbenslama/free-genai-bootcamp-2025/lang-portal/backend-go/internal/api/routes"
+	"github.com/souheilbenslama/free-genai-bootcamp-2025/lang-portal/backend-go/internal/auth"
+	"github.com/souheilbenslama/free-genai-bootcamp-2025/lang-portal/backend-go/internal/migrate"
+	"github.com/souheilbenslama/free-genai-bootcamp-2025/lang-portal/backend-go/internal/ratelimit"
 	"github.com/souheilbenslama/free-genai-bootcamp-2025/lang-portal/backend-go/internal/repository/sqlite"
+	"github.com/souheilbenslama/free-genai-bootcamp-2025/lang-portal/backend-go/internal/spa"
+	"github.com/souheilbenslama/free-genai-bootcamp-2025/lang-portal/backend-go/internal/streak"
 )
 
 var _ = Describe("Routes", func() {
@@ -21,6 +32,7 @@ var _ = Describe("Routes", func() {
 		wordHandler *handlers.WordHandler
 		groupHandler *handlers.GroupHandler
 		studyHandler *handlers.StudyHandler
+		healthHandler *handlers.HealthHandler
 	)
 
 	BeforeEach(func() {
@@ -35,9 +47,20 @@ var _ = Describe("Routes", func() {
 
 		wordHandler = handlers.NewWordHandler(wordRepo)
 		groupHandler = handlers.NewGroupHandler(groupRepo)
-		studyHandler = handlers.NewStudyHandler(studyRepo)
+		studyHandler = handlers.NewStudyHandler(studyRepo, streak.NewService(studyRepo, nil))
+		healthHandler = handlers.NewHealthHandler(db, database.Migrations(), true)
 
-		routes.SetupRoutes(router, wordHandler, groupHandler, studyHandler)
+		routes.SetupRoutes(router, routes.Handlers{
+			Word:        wordHandler,
+			Group:       groupHandler,
+			Study:       studyHandler,
+			Question:    handlers.NewQuestionHandler(studyRepo, groupRepo),
+			Answer:      handlers.NewAnswerHandler(studyRepo, 1),
+			Health:      healthHandler,
+			Auth:        handlers.NewAuthHandler(sqlite.NewUserRepository(db), test.Tokens),
+			Admin:       handlers.NewAdminHandler(sqlite.NewUserRepository(db), sqlite.NewAPIKeyRepository(db)),
+			RequireUser: middleware.Authenticate(test.Tokens, sqlite.NewAPIKeyRepository(db)),
+		})
 	})
 
 	Context("when creating a word", func() {
@@ -46,6 +69,7 @@ var _ = Describe("Routes", func() {
 			reqBody := `{"german":"hallo","english":"hello","parts":"verb"}`
 			req := httptest.NewRequest(http.MethodPost, "/api/words", strings.NewReader(reqBody))
 			req.Header.Set("Content-Type", "application/json")
+			req.Header.Set("Authorization", test.Bearer(1, auth.RoleAdmin))
 			router.ServeHTTP(w, req)
 
 			Expect(w.Code).To(Equal(http.StatusOK))
@@ -64,6 +88,7 @@ var _ = Describe("Routes", func() {
 			reqBody := `{"german":"","english":"hello","parts":"verb"}`
 			req := httptest.NewRequest(http.MethodPost, "/api/words", strings.NewReader(reqBody))
 			req.Header.Set("Content-Type", "application/json")
+			req.Header.Set("Authorization", test.Bearer(1, auth.RoleAdmin))
 			router.ServeHTTP(w, req)
 
 			Expect(w.Code).To(Equal(http.StatusBadRequest))
@@ -84,24 +109,59 @@ var _ = Describe("Routes", func() {
 		}{
 			{"List Words endpoint", http.MethodGet, "/api/words", http.StatusOK},
 			{"Get Word endpoint", http.MethodGet, "/api/words/1", http.StatusNotFound},
-			{"Create Word endpoint", http.MethodPost, "/api/words", http.StatusBadRequest},
-			{"Update Word endpoint", http.MethodPut, "/api/words/1", http.StatusBadRequest},
-			{"Delete Word endpoint", http.MethodDelete, "/api/words/1", http.StatusInternalServerError},
+			{"Create Word endpoint", http.MethodPost, "/api/words", http.StatusUnauthorized},
+			{"Update Word endpoint", http.MethodPut, "/api/words/1", http.StatusUnauthorized},
+			{"Delete Word endpoint", http.MethodDelete, "/api/words/1", http.StatusUnauthorized},
 			
 			{"List Groups endpoint", http.MethodGet, "/api/groups", http.StatusOK},
 			{"Get Group endpoint", http.MethodGet, "/api/groups/1", http.StatusNotFound},
-			{"Create Group endpoint", http.MethodPost, "/api/groups", http.StatusBadRequest},
-			{"Update Group endpoint", http.MethodPut, "/api/groups/1", http.StatusBadRequest},
-			{"Delete Group endpoint", http.MethodDelete, "/api/groups/1", http.StatusInternalServerError},
-			{"Add Word to Group endpoint", http.MethodPost, "/api/groups/1/words", http.StatusBadRequest},
-			{"Remove Word from Group endpoint", http.MethodDelete, "/api/groups/1/words/1", http.StatusInternalServerError},
+			{"Create Group endpoint", http.MethodPost, "/api/groups", http.StatusUnauthorized},
+			{"Update Group endpoint", http.MethodPut, "/api/groups/1", http.StatusUnauthorized},
+			{"Delete Group endpoint", http.MethodDelete, "/api/groups/1", http.StatusUnauthorized},
+			{"Add Word to Group endpoint", http.MethodPost, "/api/groups/1/words", http.StatusUnauthorized},
+			{"Remove Word from Group endpoint", http.MethodDelete, "/api/groups/1/words/1", http.StatusUnauthorized},
+			{"Get Group Progress endpoint", http.MethodGet, "/api/groups/1/progress", http.StatusUnauthorized},
 			
-			{"Get Last Study Session endpoint", http.MethodGet, "/api/dashboard/last_study_session", http.StatusInternalServerError},
-			{"Get Study Progress endpoint", http.MethodGet, "/api/dashboard/study_progress", http.StatusInternalServerError},
-			{"Get Quick Stats endpoint", http.MethodGet, "/api/dashboard/quick_stats", http.StatusInternalServerError},
+			{"Get Last Study Session endpoint", http.MethodGet, "/api/dashboard/last_study_session", http.StatusUnauthorized},
+			{"Get Study Progress endpoint", http.MethodGet, "/api/dashboard/study_progress", http.StatusUnauthorized},
+			{"Get Quick Stats endpoint", http.MethodGet, "/api/dashboard/quick_stats", http.StatusUnauthorized},
+			{"Get Article Breakdown endpoint", http.MethodGet, "/api/dashboard/articles", http.StatusUnauthorized},
+			{"Get Plural Breakdown endpoint", http.MethodGet, "/api/dashboard/plurals", http.StatusUnauthorized},
+			{"Get Time Series endpoint", http.MethodGet, "/api/stats/timeseries", http.StatusUnauthorized},
+			{"Get Calendar endpoint", http.MethodGet, "/api/stats/calendar", http.StatusUnauthorized},
+			{"Get Streak endpoint", http.MethodGet, "/api/dashboard/streak", http.StatusUnauthorized},
+			{"Freeze Streak endpoint", http.MethodPost, "/api/dashboard/streak/freeze", http.StatusUnauthorized},
+			{"Set Timezone endpoint", http.MethodPut, "/api/auth/me/timezone", http.StatusUnauthorized},
 			
-			{"Start Study Session endpoint", http.MethodPost, "/api/study-sessions", http.StatusBadRequest},
-			{"Record Word Review endpoint", http.MethodPost, "/api/study-sessions/1/reviews", http.StatusBadRequest},
+			{"List Study Sessions endpoint", http.MethodGet, "/api/study_sessions", http.StatusUnauthorized},
+			{"Start Study Session endpoint", http.MethodPost, "/api/study_sessions", http.StatusUnauthorized},
+			{"Record Word Review endpoint", http.MethodPost, "/api/study_sessions/1/reviews", http.StatusUnauthorized},
+			{"Record Article Review endpoint", http.MethodPost, "/api/study_sessions/1/article_reviews", http.StatusUnauthorized},
+			{"Record Plural Review endpoint", http.MethodPost, "/api/study_sessions/1/plural_reviews", http.StatusUnauthorized},
+			{"Record Answer endpoint", http.MethodPost, "/api/study_sessions/1/answers", http.StatusUnauthorized},
+			{"Next Study Session Item endpoint", http.MethodGet, "/api/study_sessions/1/next", http.StatusUnauthorized},
+			{"Finish Study Session endpoint", http.MethodPost, "/api/study_sessions/1/finish", http.StatusUnauthorized},
+			{"Study Session Question endpoint", http.MethodGet, "/api/study_sessions/1/questions", http.StatusUnauthorized},
+
+			{"Register endpoint", http.MethodPost, "/api/auth/register", http.StatusBadRequest},
+			{"Login endpoint", http.MethodPost, "/api/auth/login", http.StatusBadRequest},
+			{"Refresh endpoint", http.MethodPost, "/api/auth/refresh", http.StatusBadRequest},
+			{"Logout endpoint", http.MethodPost, "/api/auth/logout", http.StatusBadRequest},
+			{"Current user endpoint", http.MethodGet, "/api/auth/me", http.StatusUnauthorized},
+
+			{"List users endpoint", http.MethodGet, "/api/admin/users", http.StatusUnauthorized},
+			{"Set user role endpoint", http.MethodPut, "/api/admin/users/1/role", http.StatusUnauthorized},
+			{"List API keys endpoint", http.MethodGet, "/api/admin/api_keys", http.StatusUnauthorized},
+			{"Create API key endpoint", http.MethodPost, "/api/admin/api_keys", http.StatusUnauthorized},
+			{"Revoke API key endpoint", http.MethodDelete, "/api/admin/api_keys/1", http.StatusUnauthorized},
+
+			{"OpenAPI document endpoint", http.MethodGet, "/api/openapi.yaml", http.StatusOK},
+			{"API docs endpoint", http.MethodGet, "/api/docs", http.StatusOK},
+
+			{"Liveness probe", http.MethodGet, "/healthz", http.StatusOK},
+			{"Readiness probe", http.MethodGet, "/readyz", http.StatusServiceUnavailable},
+			{"Version endpoint", http.MethodGet, "/api/version", http.StatusOK},
+			{"Metrics endpoint", http.MethodGet, "/metrics", http.StatusOK},
 		}
 
 		for _, rt := range routeTests {
@@ -118,6 +178,222 @@ var _ = Describe("Routes", func() {
 		}
 	})
 
+	Context("when scraping metrics", func() {
+		It("exposes request and domain metrics", func() {
+			w := httptest.NewRecorder()
+			reqBody := `{"german":"Baum","english":"tree","parts":"der"}`
+			req := httptest.NewRequest(http.MethodPost, "/api/words", strings.NewReader(reqBody))
+			req.Header.Set("Content-Type", "application/json")
+			req.Header.Set("Authorization", test.Bearer(1, auth.RoleTeacher))
+			router.ServeHTTP(w, req)
+			Expect(w.Code).To(Equal(http.StatusOK))
+
+			w = httptest.NewRecorder()
+			req = httptest.NewRequest(http.MethodGet, "/metrics", nil)
+			router.ServeHTTP(w, req)
+
+			Expect(w.Code).To(Equal(http.StatusOK))
+			body := w.Body.String()
+			Expect(body).To(ContainSubstring(`langportal_http_requests_total{method="POST",route="/api/words",status="200"}`))
+			Expect(body).To(ContainSubstring(`langportal_db_query_duration_seconds_count{method="CreateWord",repository="word"}`))
+			Expect(body).To(ContainSubstring("langportal_words_created_total"))
+			Expect(body).To(ContainSubstring(`langportal_word_reviews_total{drill="article",result="correct"}`))
+		})
+	})
+
+	Context("when probing readiness", func() {
+		It("reports each failing component", func() {
+			w := httptest.NewRecorder()
+			req := httptest.NewRequest(http.MethodGet, "/readyz", nil)
+			router.ServeHTTP(w, req)
+
+			Expect(w.Code).To(Equal(http.StatusServiceUnavailable))
+
+			var response struct {
+				Status string `json:"status"`
+				Checks map[string]struct {
+					Status string `json:"status"`
+					Error  string `json:"error"`
+				} `json:"checks"`
+			}
+			Err := json.NewDecoder(w.Body).Decode(&response)
+			Expect(Err).NotTo(HaveOccurred())
+			Expect(response.Status).To(Equal("unavailable"))
+			Expect(response.Checks["database"].Status).To(Equal("ok"))
+			Expect(response.Checks["migrations"].Status).To(Equal("fail"))
+			Expect(response.Checks["seed"].Status).To(Equal("fail"))
+		})
+	})
+
+	Context("when authenticating", func() {
+		It("rejects requests without a bearer token", func() {
+			w := httptest.NewRecorder()
+			req := httptest.NewRequest(http.MethodGet, "/api/study_sessions", nil)
+			router.ServeHTTP(w, req)
+
+			Expect(w.Code).To(Equal(http.StatusUnauthorized))
+			Expect(w.Header().Get("WWW-Authenticate")).To(HavePrefix("Bearer"))
+		})
+
+		It("rejects tokens signed with another secret", func() {
+			other := auth.NewTokens([]byte("another-secret-another-secret-xx"), time.Hour, time.Hour)
+			token, err := other.IssueAccess(auth.Principal{UserID: 1, Role: auth.RoleAdmin})
+			Expect(err).NotTo(HaveOccurred())
+
+			w := httptest.NewRecorder()
+			req := httptest.NewRequest(http.MethodGet, "/api/study_sessions", nil)
+			req.Header.Set("Authorization", "Bearer "+token)
+			router.ServeHTTP(w, req)
+
+			Expect(w.Code).To(Equal(http.StatusUnauthorized))
+		})
+	})
+
+	Context("when authorizing", func() {
+		It("forbids learners from changing content with a problem response", func() {
+			w := httptest.NewRecorder()
+			reqBody := `{"german":"Baum","english":"tree","parts":"der"}`
+			req := httptest.NewRequest(http.MethodPost, "/api/words", strings.NewReader(reqBody))
+			req.Header.Set("Content-Type", "application/json")
+			req.Header.Set("Authorization", test.Bearer(1, auth.RoleLearner))
+			router.ServeHTTP(w, req)
+
+			Expect(w.Code).To(Equal(http.StatusForbidden))
+			Expect(w.Header().Get("Content-Type")).To(HavePrefix(middleware.ProblemContentType))
+
+			var problem map[string]interface{}
+			Err := json.NewDecoder(w.Body).Decode(&problem)
+			Expect(Err).NotTo(HaveOccurred())
+			Expect(problem["status"]).To(BeNumerically("==", http.StatusForbidden))
+			Expect(problem["required_permission"]).To(Equal("content:manage"))
+			Expect(problem["detail"]).To(ContainSubstring("learner"))
+		})
+
+		It("lets learners read content", func() {
+			w := httptest.NewRecorder()
+			req := httptest.NewRequest(http.MethodGet, "/api/words", nil)
+			req.Header.Set("Authorization", test.Bearer(1, auth.RoleLearner))
+			router.ServeHTTP(w, req)
+
+			Expect(w.Code).To(Equal(http.StatusOK))
+		})
+
+		It("keeps account administration to admins", func() {
+			for role, code := range map[auth.Role]int{
+				auth.RoleLearner: http.StatusForbidden,
+				auth.RoleTeacher: http.StatusForbidden,
+				auth.RoleAdmin:   http.StatusBadRequest,
+			} {
+				w := httptest.NewRecorder()
+				req := httptest.NewRequest(http.MethodPut, "/api/admin/users/1/role", strings.NewReader(`{"role":"root"}`))
+				req.Header.Set("Content-Type", "application/json")
+				req.Header.Set("Authorization", test.Bearer(1, role))
+				router.ServeHTTP(w, req)
+
+				Expect(w.Code).To(Equal(code), "role %s", role)
+			}
+		})
+	})
+
+	Context("when limiting clients", func() {
+		BeforeEach(func() {
+			db := test.SetupTestDB()
+			router = gin.New()
+			routes.SetupRoutes(router, routes.Handlers{
+				Word:        handlers.NewWordHandler(sqlite.NewWordRepository(db)),
+				Group:       handlers.NewGroupHandler(sqlite.NewGroupRepository(db)),
+				Study:       handlers.NewStudyHandler(sqlite.NewStudyRepository(db), streak.NewService(sqlite.NewStudyRepository(db), nil)),
+				Question:    handlers.NewQuestionHandler(sqlite.NewStudyRepository(db), sqlite.NewGroupRepository(db)),
+				Answer:      handlers.NewAnswerHandler(sqlite.NewStudyRepository(db), 1),
+				Health:      handlers.NewHealthHandler(db, database.Migrations(), true),
+				Auth:        handlers.NewAuthHandler(sqlite.NewUserRepository(db), test.Tokens),
+				Admin:       handlers.NewAdminHandler(sqlite.NewUserRepository(db), sqlite.NewAPIKeyRepository(db)),
+				RequireUser: middleware.Authenticate(test.Tokens, sqlite.NewAPIKeyRepository(db)),
+				Limits: routes.Limits{
+					MaxBodyBytes: 64,
+					Default:      ratelimit.New("default", ratelimit.Policy{Requests: 2, Period: time.Minute}),
+					Auth:         ratelimit.New("auth", ratelimit.Policy{Requests: 1, Period: time.Minute}),
+				},
+			})
+		})
+
+		get := func(authorization string) *httptest.ResponseRecorder {
+			w := httptest.NewRecorder()
+			req := httptest.NewRequest(http.MethodGet, "/api/words", nil)
+			if authorization != "" {
+				req.Header.Set("Authorization", authorization)
+			}
+			router.ServeHTTP(w, req)
+			return w
+		}
+
+		It("answers with 429 and Retry-After once the allowance is used up", func() {
+			w := get("")
+			Expect(w.Code).To(Equal(http.StatusOK))
+			Expect(w.Header().Get("RateLimit-Limit")).To(Equal("2"))
+			Expect(w.Header().Get("RateLimit-Remaining")).To(Equal("1"))
+			Expect(w.Header().Get("RateLimit-Policy")).To(Equal("2;w=60"))
+
+			Expect(get("").Code).To(Equal(http.StatusOK))
+
+			w = get("")
+			Expect(w.Code).To(Equal(http.StatusTooManyRequests))
+			Expect(w.Header().Get("Retry-After")).To(Equal("30"))
+			Expect(w.Header().Get("RateLimit-Remaining")).To(Equal("0"))
+			Expect(w.Body.String()).To(ContainSubstring("rate limit exceeded"))
+		})
+
+		It("gives authenticated callers a bucket of their own", func() {
+			for i := 0; i < 2; i++ {
+				Expect(get("").Code).To(Equal(http.StatusOK))
+			}
+			Expect(get("").Code).To(Equal(http.StatusTooManyRequests))
+
+			w := httptest.NewRecorder()
+			req := httptest.NewRequest(http.MethodPost, "/api/groups", strings.NewReader(`{"name":"Trees"}`))
+			req.Header.Set("Content-Type", "application/json")
+			req.Header.Set("Authorization", test.Bearer(1, auth.RoleTeacher))
+			router.ServeHTTP(w, req)
+			Expect(w.Code).To(Equal(http.StatusCreated))
+			Expect(w.Header().Get("RateLimit-Remaining")).To(Equal("1"))
+		})
+
+		It("counts signed in users against the default policy on their account", func() {
+			w := httptest.NewRecorder()
+			req := httptest.NewRequest(http.MethodGet, "/api/auth/me", nil)
+			req.Header.Set("Authorization", test.Bearer(1, auth.RoleLearner))
+			router.ServeHTTP(w, req)
+			Expect(w.Header().Get("RateLimit-Policy")).To(Equal("2;w=60"))
+
+			w = httptest.NewRecorder()
+			req = httptest.NewRequest(http.MethodPost, "/api/auth/login", strings.NewReader(`{}`))
+			req.Header.Set("Content-Type", "application/json")
+			router.ServeHTTP(w, req)
+			Expect(w.Header().Get("RateLimit-Policy")).To(Equal("1;w=60"))
+		})
+
+		It("rejects bodies over the size cap", func() {
+			body := `{"german":"Donaudampfschifffahrtsgesellschaft","english":"Danube steamship company","parts":"die"}`
+
+			w := httptest.NewRecorder()
+			req := httptest.NewRequest(http.MethodPost, "/api/words", strings.NewReader(body))
+			req.Header.Set("Content-Type", "application/json")
+			req.Header.Set("Authorization", test.Bearer(1, auth.RoleAdmin))
+			router.ServeHTTP(w, req)
+			Expect(w.Code).To(Equal(http.StatusRequestEntityTooLarge))
+
+			// Without a Content-Length the cap applies while the body is read
+			w = httptest.NewRecorder()
+			req = httptest.NewRequest(http.MethodPost, "/api/words", strings.NewReader(body))
+			req.ContentLength = -1
+			req.Header.Set("Content-Type", "application/json")
+			req.Header.Set("Authorization", test.Bearer(1, auth.RoleAdmin))
+			router.ServeHTTP(w, req)
+			Expect(w.Code).To(Equal(http.StatusRequestEntityTooLarge))
+			Expect(w.Body.String()).To(ContainSubstring("exceeds 64 bytes"))
+		})
+	})
+
 	Context("when accessing non-API routes", func() {
 		It("should return 404 for non-API paths", func() {
 			w := httptest.NewRecorder()
@@ -129,10 +405,75 @@ var _ = Describe("Routes", func() {
 		})
 	})
 
+	Context("when serving the frontend", func() {
+		BeforeEach(func() {
+			db := test.SetupTestDB()
+			router = gin.New()
+			routes.SetupRoutes(router, routes.Handlers{
+				Word:        handlers.NewWordHandler(sqlite.NewWordRepository(db)),
+				Group:       handlers.NewGroupHandler(sqlite.NewGroupRepository(db)),
+				Study:       handlers.NewStudyHandler(sqlite.NewStudyRepository(db), streak.NewService(sqlite.NewStudyRepository(db), nil)),
+				Question:    handlers.NewQuestionHandler(sqlite.NewStudyRepository(db), sqlite.NewGroupRepository(db)),
+				Answer:      handlers.NewAnswerHandler(sqlite.NewStudyRepository(db), 1),
+				Health:      handlers.NewHealthHandler(db, database.Migrations(), true),
+				Auth:        handlers.NewAuthHandler(sqlite.NewUserRepository(db), test.Tokens),
+				Admin:       handlers.NewAdminHandler(sqlite.NewUserRepository(db), sqlite.NewAPIKeyRepository(db)),
+				RequireUser: middleware.Authenticate(test.Tokens, sqlite.NewAPIKeyRepository(db)),
+				Frontend: spa.Handler(fstest.MapFS{
+					"index.html": {Data: []byte("<html>app</html>")},
+				}),
+			})
+		})
+
+		It("serves the app on paths the API does not claim", func() {
+			w := httptest.NewRecorder()
+			req := httptest.NewRequest(http.MethodGet, "/groups/1", nil)
+			router.ServeHTTP(w, req)
+
+			Expect(w.Code).To(Equal(http.StatusOK))
+			Expect(w.Body.String()).To(Equal("<html>app</html>"))
+		})
+
+		It("keeps unknown API paths as JSON errors", func() {
+			w := httptest.NewRecorder()
+			req := httptest.NewRequest(http.MethodGet, "/api/not-a-route", nil)
+			router.ServeHTTP(w, req)
+
+			Expect(w.Code).To(Equal(http.StatusNotFound))
+			Expect(w.Header().Get("Content-Type")).To(HavePrefix("application/json"))
+		})
+
+		It("still routes API requests", func() {
+			w := httptest.NewRecorder()
+			req := httptest.NewRequest(http.MethodGet, "/api/words", nil)
+			router.ServeHTTP(w, req)
+
+			Expect(w.Code).To(Equal(http.StatusOK))
+		})
+	})
+
+	Context("when a repository fails", func() {
+		It("does not leak the SQL error to the client", func() {
+			w := httptest.NewRecorder()
+			req := httptest.NewRequest(http.MethodGet, "/api/dashboard/quick_stats", nil)
+			req.Header.Set("Authorization", test.Bearer(1, auth.RoleLearner))
+			router.ServeHTTP(w, req)
+
+			Expect(w.Code).To(Equal(http.StatusInternalServerError))
+			Expect(w.Body.String()).NotTo(ContainSubstring("no such table"))
+
+			var response map[string]interface{}
+			Err := json.NewDecoder(w.Body).Decode(&response)
+			Expect(Err).NotTo(HaveOccurred())
+			Expect(response["error"]).To(Equal("internal server error"))
+		})
+	})
+
 	Context("when accessing dashboard", func() {
 		It("should get study progress", func() {
 			w := httptest.NewRecorder()
 			req := httptest.NewRequest(http.MethodGet, "/api/dashboard/study_progress", nil)
+			req.Header.Set("Authorization", test.Bearer(1, auth.RoleLearner))
 			router.ServeHTTP(w, req)
 
 			Expect(w.Code).To(Equal(http.StatusInternalServerError))
@@ -141,6 +482,7 @@ var _ = Describe("Routes", func() {
 		It("should get quick stats", func() {
 			w := httptest.NewRecorder()
 			req := httptest.NewRequest(http.MethodGet, "/api/dashboard/quick_stats", nil)
+			req.Header.Set("Authorization", test.Bearer(1, auth.RoleLearner))
 			router.ServeHTTP(w, req)
 
 			Expect(w.Code).To(Equal(http.StatusInternalServerError))
@@ -149,6 +491,7 @@ var _ = Describe("Routes", func() {
 		It("should get last study session", func() {
 			w := httptest.NewRecorder()
 			req := httptest.NewRequest(http.MethodGet, "/api/dashboard/last_study_session", nil)
+			req.Header.Set("Authorization", test.Bearer(1, auth.RoleLearner))
 			router.ServeHTTP(w, req)
 
 			Expect(w.Code).To(Equal(http.StatusInternalServerError))
@@ -158,18 +501,38 @@ var _ = Describe("Routes", func() {
 	Context("when managing study sessions", func() {
 		It("should create a new study session", func() {
 			w := httptest.NewRecorder()
+			req := httptest.NewRequest(http.MethodPost, "/api/groups", strings.NewReader(`{"name": "Basics"}`))
+			req.Header.Set("Authorization", test.Bearer(1, auth.RoleTeacher))
+			req.Header.Set("Content-Type", "application/json")
+			router.ServeHTTP(w, req)
+			Expect(w.Code).To(Equal(http.StatusCreated))
+
+			w = httptest.NewRecorder()
 			reqBody := `{"group_id": 1}`
-			req := httptest.NewRequest(http.MethodPost, "/api/study-sessions", strings.NewReader(reqBody))
+			req = httptest.NewRequest(http.MethodPost, "/api/study_sessions", strings.NewReader(reqBody))
+			req.Header.Set("Authorization", test.Bearer(1, auth.RoleLearner))
 			req.Header.Set("Content-Type", "application/json")
 			router.ServeHTTP(w, req)
 
 			Expect(w.Code).To(Equal(http.StatusInternalServerError))
 		})
 
+		It("should reject study sessions for a missing group", func() {
+			w := httptest.NewRecorder()
+			req := httptest.NewRequest(http.MethodPost, "/api/study_sessions", strings.NewReader(`{"group_id": 9999}`))
+			req.Header.Set("Authorization", test.Bearer(1, auth.RoleLearner))
+			req.Header.Set("Content-Type", "application/json")
+			router.ServeHTTP(w, req)
+
+			Expect(w.Code).To(Equal(http.StatusNotFound))
+			Expect(w.Body.String()).To(ContainSubstring("group not found"))
+		})
+
 		It("should reject invalid study session request", func() {
 			w := httptest.NewRecorder()
 			reqBody := `{"invalid_field": 1}`
-			req := httptest.NewRequest(http.MethodPost, "/api/study-sessions", strings.NewReader(reqBody))
+			req := httptest.NewRequest(http.MethodPost, "/api/study_sessions", strings.NewReader(reqBody))
+			req.Header.Set("Authorization", test.Bearer(1, auth.RoleLearner))
 			req.Header.Set("Content-Type", "application/json")
 			router.ServeHTTP(w, req)
 
@@ -184,17 +547,63 @@ var _ = Describe("Routes", func() {
 		It("should record a word review", func() {
 			w := httptest.NewRecorder()
 			reqBody := `{"word_id": 1, "correct": true}`
-			req := httptest.NewRequest(http.MethodPost, "/api/study-sessions/1/reviews", strings.NewReader(reqBody))
+			req := httptest.NewRequest(http.MethodPost, "/api/study_sessions/1/reviews", strings.NewReader(reqBody))
+			req.Header.Set("Authorization", test.Bearer(1, auth.RoleLearner))
 			req.Header.Set("Content-Type", "application/json")
 			router.ServeHTTP(w, req)
 
 			Expect(w.Code).To(Equal(http.StatusInternalServerError))
 		})
 
+		It("should accept an incorrect answer and require one", func() {
+			for body, code := range map[string]int{
+				`{"word_id": 1, "correct": false}`: http.StatusInternalServerError,
+				`{"word_id": 1}`:                   http.StatusBadRequest,
+			} {
+				w := httptest.NewRecorder()
+				req := httptest.NewRequest(http.MethodPost, "/api/study_sessions/1/reviews", strings.NewReader(body))
+				req.Header.Set("Authorization", test.Bearer(1, auth.RoleLearner))
+				req.Header.Set("Content-Type", "application/json")
+				router.ServeHTTP(w, req)
+
+				// The test database has no study tables, so passing validation
+				// ends in a repository error
+				Expect(w.Code).To(Equal(code), body)
+			}
+		})
+
+		It("should reject unknown strategies", func() {
+			w := httptest.NewRecorder()
+			req := httptest.NewRequest(http.MethodGet, "/api/study_sessions/1/next?strategy=hardest", nil)
+			req.Header.Set("Authorization", test.Bearer(1, auth.RoleLearner))
+			router.ServeHTTP(w, req)
+
+			Expect(w.Code).To(Equal(http.StatusBadRequest))
+		})
+
+		It("should reject unknown question types", func() {
+			w := httptest.NewRecorder()
+			req := httptest.NewRequest(http.MethodGet, "/api/study_sessions/1/questions?type=essay", nil)
+			req.Header.Set("Authorization", test.Bearer(1, auth.RoleLearner))
+			router.ServeHTTP(w, req)
+
+			Expect(w.Code).To(Equal(http.StatusBadRequest))
+		})
+
+		It("should reject finishing a session with an invalid ID", func() {
+			w := httptest.NewRecorder()
+			req := httptest.NewRequest(http.MethodPost, "/api/study_sessions/abc/finish", nil)
+			req.Header.Set("Authorization", test.Bearer(1, auth.RoleLearner))
+			router.ServeHTTP(w, req)
+
+			Expect(w.Code).To(Equal(http.StatusBadRequest))
+		})
+
 		It("should reject invalid word review", func() {
 			w := httptest.NewRecorder()
 			reqBody := `{"word_id": "invalid"}`
-			req := httptest.NewRequest(http.MethodPost, "/api/study-sessions/1/reviews", strings.NewReader(reqBody))
+			req := httptest.NewRequest(http.MethodPost, "/api/study_sessions/1/reviews", strings.NewReader(reqBody))
+			req.Header.Set("Authorization", test.Bearer(1, auth.RoleLearner))
 			req.Header.Set("Content-Type", "application/json")
 			router.ServeHTTP(w, req)
 
@@ -205,5 +614,116 @@ var _ = Describe("Routes", func() {
 			Expect(Err).NotTo(HaveOccurred())
 			Expect(response["error"]).NotTo(BeEmpty())
 		})
+
+		It("should reject overlong answers", func() {
+			w := httptest.NewRecorder()
+			body := `{"word_id": 1, "answer": "` + strings.Repeat("a", 201) + `"}`
+			req := httptest.NewRequest(http.MethodPost, "/api/study_sessions/1/answers", strings.NewReader(body))
+			req.Header.Set("Authorization", test.Bearer(1, auth.RoleLearner))
+			req.Header.Set("Content-Type", "application/json")
+			router.ServeHTTP(w, req)
+
+			Expect(w.Code).To(Equal(http.StatusBadRequest))
+		})
+
+		It("should reject time series over too many days", func() {
+			w := httptest.NewRecorder()
+			req := httptest.NewRequest(http.MethodGet, "/api/stats/timeseries?from=2024-01-01&to=2025-01-01", nil)
+			req.Header.Set("Authorization", test.Bearer(1, auth.RoleLearner))
+			router.ServeHTTP(w, req)
+
+			Expect(w.Code).To(Equal(http.StatusBadRequest))
+		})
+
+		It("should reject time series with malformed dates", func() {
+			w := httptest.NewRecorder()
+			req := httptest.NewRequest(http.MethodGet, "/api/stats/timeseries?from=01/02/2025", nil)
+			req.Header.Set("Authorization", test.Bearer(1, auth.RoleLearner))
+			router.ServeHTTP(w, req)
+
+			Expect(w.Code).To(Equal(http.StatusBadRequest))
+		})
+
+		It("should reject calendars of malformed years", func() {
+			w := httptest.NewRecorder()
+			req := httptest.NewRequest(http.MethodGet, "/api/stats/calendar?year=next", nil)
+			req.Header.Set("Authorization", test.Bearer(1, auth.RoleLearner))
+			router.ServeHTTP(w, req)
+
+			Expect(w.Code).To(Equal(http.StatusBadRequest))
+		})
+
+		It("should mark the old study session path as deprecated", func() {
+			w := httptest.NewRecorder()
+			req := httptest.NewRequest(http.MethodGet, "/api/study-sessions", nil)
+			router.ServeHTTP(w, req)
+
+			Expect(w.Code).To(Equal(http.StatusUnauthorized))
+			Expect(w.Header().Get("Deprecation")).To(Equal("true"))
+			Expect(w.Header().Get("Link")).To(Equal(`</api/study_sessions>; rel="successor-version"`))
+		})
+
+		It("should link both the pages and the successor on the old study session path", func() {
+			db, err := sqlite.Open(filepath.Join(GinkgoT().TempDir(), "words.db"))
+			Expect(err).NotTo(HaveOccurred())
+			DeferCleanup(db.Close)
+			_, err = migrate.Apply(context.Background(), db, database.Migrations())
+			Expect(err).NotTo(HaveOccurred())
+
+			router = gin.New()
+			routes.SetupRoutes(router, routes.Handlers{
+				Study:       handlers.NewStudyHandler(sqlite.NewStudyRepository(db), streak.NewService(sqlite.NewStudyRepository(db), nil)),
+				RequireUser: middleware.Authenticate(test.Tokens, sqlite.NewAPIKeyRepository(db)),
+			})
+
+			w := httptest.NewRecorder()
+			req := httptest.NewRequest(http.MethodGet, "/api/study-sessions", nil)
+			req.Header.Set("Authorization", test.Bearer(1, auth.RoleLearner))
+			router.ServeHTTP(w, req)
+
+			Expect(w.Code).To(Equal(http.StatusOK))
+			Expect(w.Header().Values("Link")).To(ConsistOf(
+				`</api/study_sessions>; rel="successor-version"`,
+				`</api/study-sessions?limit=100>; rel="first"`,
+			))
+		})
+
+		It("should reject streak freezes without a day", func() {
+			w := httptest.NewRecorder()
+			req := httptest.NewRequest(http.MethodPost, "/api/dashboard/streak/freeze", strings.NewReader(`{}`))
+			req.Header.Set("Authorization", test.Bearer(1, auth.RoleLearner))
+			req.Header.Set("Content-Type", "application/json")
+			router.ServeHTTP(w, req)
+
+			Expect(w.Code).To(Equal(http.StatusBadRequest))
+		})
+
+		It("should reject group progress with an invalid ID", func() {
+			w := httptest.NewRecorder()
+			req := httptest.NewRequest(http.MethodGet, "/api/groups/abc/progress", nil)
+			req.Header.Set("Authorization", test.Bearer(1, auth.RoleLearner))
+			router.ServeHTTP(w, req)
+
+			Expect(w.Code).To(Equal(http.StatusBadRequest))
+		})
+
+		It("should reject listing groups with an invalid token", func() {
+			w := httptest.NewRecorder()
+			req := httptest.NewRequest(http.MethodGet, "/api/groups", nil)
+			req.Header.Set("Authorization", "Bearer not-a-token")
+			router.ServeHTTP(w, req)
+
+			Expect(w.Code).To(Equal(http.StatusUnauthorized))
+		})
+
+		It("should reject unknown articles", func() {
+			w := httptest.NewRecorder()
+			req := httptest.NewRequest(http.MethodPost, "/api/study_sessions/1/article_reviews", strings.NewReader(`{"word_id": 1, "article": "den"}`))
+			req.Header.Set("Authorization", test.Bearer(1, auth.RoleLearner))
+			req.Header.Set("Content-Type", "application/json")
+			router.ServeHTTP(w, req)
+
+			Expect(w.Code).To(Equal(http.StatusBadRequest))
+		})
 	})
 })
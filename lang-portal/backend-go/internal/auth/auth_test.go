@@ -0,0 +1,135 @@
+package auth_test
+
+import (
+	"strings"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"github.com/souheilbenslama/free-genai-bootcamp-2025/lang-portal/backend-go/internal/auth"
+)
+
+var secret = []byte("auth-test-secret-auth-test-secret")
+
+var _ = Describe("Passwords", func() {
+	It("accepts the password it hashed and nothing else", func() {
+		hash, err := auth.HashPassword("correct horse battery")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(hash).NotTo(ContainSubstring("correct horse"))
+
+		Expect(auth.CheckPassword(hash, "correct horse battery")).To(Succeed())
+		Expect(auth.CheckPassword(hash, "Correct horse battery")).To(MatchError(auth.ErrWrongPassword))
+	})
+
+	It("rejects unknown users", func() {
+		Expect(auth.CheckPassword("", "anything")).To(MatchError(auth.ErrWrongPassword))
+	})
+
+	It("enforces the length limits", func() {
+		Expect(auth.ValidatePassword("short")).To(MatchError(auth.ErrPasswordTooShort))
+		Expect(auth.ValidatePassword(strings.Repeat("x", 73))).To(MatchError(auth.ErrPasswordTooLong))
+		Expect(auth.ValidatePassword("long enough")).To(Succeed())
+	})
+})
+
+var _ = Describe("Roles", func() {
+	It("grants each role its permissions", func() {
+		Expect(auth.RoleAdmin.Can(auth.PermManageUsers)).To(BeTrue())
+		Expect(auth.RoleAdmin.Can(auth.PermManageContent)).To(BeTrue())
+		Expect(auth.RoleTeacher.Can(auth.PermManageContent)).To(BeTrue())
+		Expect(auth.RoleTeacher.Can(auth.PermManageUsers)).To(BeFalse())
+		Expect(auth.RoleLearner.Can(auth.PermStudy)).To(BeTrue())
+		Expect(auth.RoleLearner.Can(auth.PermManageContent)).To(BeFalse())
+	})
+
+	It("parses only known roles", func() {
+		for _, role := range auth.Roles {
+			parsed, err := auth.ParseRole(string(role))
+			Expect(err).NotTo(HaveOccurred())
+			Expect(parsed).To(Equal(role))
+		}
+		_, err := auth.ParseRole("superuser")
+		Expect(err).To(HaveOccurred())
+	})
+})
+
+var _ = Describe("API keys", func() {
+	It("limits a key to both its scopes and its owner's role", func() {
+		key := auth.Principal{UserID: 1, Role: auth.RoleTeacher, APIKeyID: 7, Scopes: []auth.Scope{auth.ScopeReviewsWrite}}
+		Expect(key.Can(auth.PermStudy)).To(BeTrue())
+		Expect(key.Can(auth.PermReadProgress)).To(BeFalse())
+		Expect(key.Can(auth.PermManageContent)).To(BeFalse())
+
+		learnerKey := auth.Principal{UserID: 2, Role: auth.RoleLearner, APIKeyID: 8, Scopes: []auth.Scope{auth.ScopeWordsWrite}}
+		Expect(learnerKey.Can(auth.PermManageContent)).To(BeFalse())
+
+		adminKey := auth.Principal{UserID: 3, Role: auth.RoleAdmin, APIKeyID: 9, Scopes: auth.Scopes}
+		Expect(adminKey.Can(auth.PermManageUsers)).To(BeFalse())
+	})
+
+	It("parses only known scopes", func() {
+		scopes, err := auth.ParseScopes([]string{"words:read", "reviews:write", "words:read"})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(scopes).To(Equal([]auth.Scope{auth.ScopeWordsRead, auth.ScopeReviewsWrite}))
+
+		_, err = auth.ParseScopes([]string{"users:manage"})
+		Expect(err).To(HaveOccurred())
+	})
+
+	It("stores keys only as hashes", func() {
+		key, prefix, hash, err := auth.NewAPIKey()
+		Expect(err).NotTo(HaveOccurred())
+		Expect(auth.IsAPIKey(key)).To(BeTrue())
+		Expect(key).To(HavePrefix(prefix))
+		Expect(hash).To(Equal(auth.HashAPIKey(key)))
+		Expect(hash).NotTo(ContainSubstring(key))
+
+		other, _, _, err := auth.NewAPIKey()
+		Expect(err).NotTo(HaveOccurred())
+		Expect(other).NotTo(Equal(key))
+	})
+})
+
+var _ = Describe("Tokens", func() {
+	It("round trips the principal of an access token", func() {
+		tokens := auth.NewTokens(secret, time.Minute, time.Hour)
+
+		token, err := tokens.IssueAccess(auth.Principal{UserID: 42, Role: auth.RoleTeacher})
+		Expect(err).NotTo(HaveOccurred())
+
+		principal, err := tokens.ParseAccess(token)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(principal).To(Equal(auth.Principal{UserID: 42, Role: auth.RoleTeacher}))
+	})
+
+	It("rejects expired, foreign and malformed tokens", func() {
+		learner := auth.Principal{UserID: 1, Role: auth.RoleLearner}
+		expired, err := auth.NewTokens(secret, -time.Minute, time.Hour).IssueAccess(learner)
+		Expect(err).NotTo(HaveOccurred())
+		foreign, err := auth.NewTokens([]byte("some-other-secret-some-other-secret"), time.Minute, time.Hour).IssueAccess(learner)
+		Expect(err).NotTo(HaveOccurred())
+
+		tokens := auth.NewTokens(secret, time.Minute, time.Hour)
+		unknownRole, err := tokens.IssueAccess(auth.Principal{UserID: 1, Role: "root"})
+		Expect(err).NotTo(HaveOccurred())
+
+		for _, token := range []string{expired, foreign, unknownRole, "not-a-token", ""} {
+			_, err := tokens.ParseAccess(token)
+			Expect(err).To(MatchError(auth.ErrInvalidToken))
+		}
+	})
+
+	It("stores refresh tokens only as hashes", func() {
+		tokens := auth.NewTokens(secret, time.Minute, time.Hour)
+
+		token, hash, expiresAt, err := tokens.NewRefresh()
+		Expect(err).NotTo(HaveOccurred())
+		Expect(hash).To(Equal(auth.HashRefresh(token)))
+		Expect(hash).NotTo(ContainSubstring(token))
+		Expect(expiresAt).To(BeTemporally("~", time.Now().Add(time.Hour), time.Second))
+
+		other, _, _, err := tokens.NewRefresh()
+		Expect(err).NotTo(HaveOccurred())
+		Expect(other).NotTo(Equal(token))
+	})
+})
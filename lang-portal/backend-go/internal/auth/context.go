@@ -0,0 +1,50 @@
+package auth
+
+import "context"
+
+// Principal is the authenticated caller of a request. Requests made with an
+// API key act as the key's owner, limited to the key's scopes.
+type Principal struct {
+	UserID int
+	Role   Role
+
+	// APIKeyID is set when the caller authenticated with an API key.
+	APIKeyID int
+	Scopes   []Scope
+}
+
+// Can reports whether the principal holds the permission: its role must grant
+// it and, for API keys, one of the key's scopes too.
+func (p Principal) Can(perm Permission) bool {
+	if !p.Role.Can(perm) {
+		return false
+	}
+	if p.APIKeyID == 0 {
+		return true
+	}
+	for _, scope := range p.Scopes {
+		if scope.Grants(perm) {
+			return true
+		}
+	}
+	return false
+}
+
+type principalKey struct{}
+
+// WithPrincipal returns a copy of ctx carrying the authenticated caller.
+func WithPrincipal(ctx context.Context, p Principal) context.Context {
+	return context.WithValue(ctx, principalKey{}, p)
+}
+
+// PrincipalFrom returns the authenticated caller carried by ctx.
+func PrincipalFrom(ctx context.Context) (Principal, bool) {
+	p, ok := ctx.Value(principalKey{}).(Principal)
+	return p, ok
+}
+
+// UserID returns the authenticated user carried by ctx.
+func UserID(ctx context.Context) (int, bool) {
+	p, ok := PrincipalFrom(ctx)
+	return p.UserID, ok
+}
@@ -0,0 +1,60 @@
+package auth
+
+import (
+	"errors"
+	"fmt"
+
+	"golang.org/x/crypto/bcrypt"
+)
+
+const (
+	// MinPasswordLength is the shortest password accepted at registration.
+	MinPasswordLength = 8
+	// MaxPasswordLength is the bcrypt input limit in bytes; longer passwords
+	// would be silently truncated.
+	MaxPasswordLength = 72
+)
+
+var (
+	ErrPasswordTooShort = fmt.Errorf("password must be at least %d characters", MinPasswordLength)
+	ErrPasswordTooLong  = fmt.Errorf("password must be at most %d bytes", MaxPasswordLength)
+	ErrWrongPassword    = errors.New("wrong password")
+)
+
+// dummyHash is compared against when a login names an unknown user, so the
+// response time does not reveal which emails are registered.
+var dummyHash, _ = bcrypt.GenerateFromPassword([]byte("lang-portal-dummy-password"), bcrypt.DefaultCost)
+
+// ValidatePassword checks the length limits of a new password.
+func ValidatePassword(password string) error {
+	if len([]rune(password)) < MinPasswordLength {
+		return ErrPasswordTooShort
+	}
+	if len(password) > MaxPasswordLength {
+		return ErrPasswordTooLong
+	}
+	return nil
+}
+
+// HashPassword returns the bcrypt hash of password.
+func HashPassword(password string) (string, error) {
+	hash, err := bcrypt.GenerateFromPassword([]byte(password), bcrypt.DefaultCost)
+	if err != nil {
+		return "", fmt.Errorf("error hashing password: %w", err)
+	}
+	return string(hash), nil
+}
+
+// CheckPassword returns ErrWrongPassword unless password matches hash. An
+// empty hash stands for an unknown user and always fails, after the same
+// amount of work as a real comparison.
+func CheckPassword(hash, password string) error {
+	if hash == "" {
+		bcrypt.CompareHashAndPassword(dummyHash, []byte(password))
+		return ErrWrongPassword
+	}
+	if err := bcrypt.CompareHashAndPassword([]byte(hash), []byte(password)); err != nil {
+		return ErrWrongPassword
+	}
+	return nil
+}
@@ -0,0 +1,54 @@
+package auth
+
+import "fmt"
+
+// Role is the coarse access level of a user.
+type Role string
+
+const (
+	RoleAdmin   Role = "admin"
+	RoleTeacher Role = "teacher"
+	RoleLearner Role = "learner"
+)
+
+// Roles lists every role, most privileged first.
+var Roles = []Role{RoleAdmin, RoleTeacher, RoleLearner}
+
+// Permission is a single action a route can require.
+type Permission string
+
+const (
+	// PermStudy allows starting study sessions and recording reviews.
+	PermStudy Permission = "study"
+	// PermReadProgress allows listing study sessions and reading the dashboard.
+	PermReadProgress Permission = "progress:read"
+	// PermManageContent allows creating, changing and deleting words and groups.
+	PermManageContent Permission = "content:manage"
+	// PermManageUsers allows listing users and changing their roles.
+	PermManageUsers Permission = "users:manage"
+)
+
+var rolePermissions = map[Role][]Permission{
+	RoleAdmin:   {PermStudy, PermReadProgress, PermManageContent, PermManageUsers},
+	RoleTeacher: {PermStudy, PermReadProgress, PermManageContent},
+	RoleLearner: {PermStudy, PermReadProgress},
+}
+
+// ParseRole validates a role name.
+func ParseRole(name string) (Role, error) {
+	role := Role(name)
+	if _, ok := rolePermissions[role]; !ok {
+		return "", fmt.Errorf("unknown role %q, must be admin, teacher or learner", name)
+	}
+	return role, nil
+}
+
+// Can reports whether the role grants the permission.
+func (r Role) Can(perm Permission) bool {
+	for _, granted := range rolePermissions[r] {
+		if granted == perm {
+			return true
+		}
+	}
+	return false
+}
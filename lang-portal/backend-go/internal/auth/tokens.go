@@ -0,0 +1,115 @@
+package auth
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+// Issuer is the iss claim of every access token.
+const Issuer = "lang-portal"
+
+var ErrInvalidToken = errors.New("invalid or expired token")
+
+// Tokens issues and verifies short-lived JWT access tokens and creates the
+// opaque refresh tokens used to renew them.
+type Tokens struct {
+	secret     []byte
+	accessTTL  time.Duration
+	refreshTTL time.Duration
+	now        func() time.Time
+}
+
+func NewTokens(secret []byte, accessTTL, refreshTTL time.Duration) *Tokens {
+	return &Tokens{
+		secret:     secret,
+		accessTTL:  accessTTL,
+		refreshTTL: refreshTTL,
+		now:        time.Now,
+	}
+}
+
+// AccessTTL is the lifetime of an access token.
+func (t *Tokens) AccessTTL() time.Duration { return t.accessTTL }
+
+// accessClaims carries the role so permission checks need no database
+// lookup. A role change therefore takes effect when the token is refreshed.
+type accessClaims struct {
+	Role Role `json:"role"`
+	jwt.RegisteredClaims
+}
+
+// IssueAccess returns a signed access token for the principal.
+func (t *Tokens) IssueAccess(p Principal) (string, error) {
+	now := t.now()
+	claims := accessClaims{
+		Role: p.Role,
+		RegisteredClaims: jwt.RegisteredClaims{
+			Issuer:    Issuer,
+			Subject:   strconv.Itoa(p.UserID),
+			IssuedAt:  jwt.NewNumericDate(now),
+			ExpiresAt: jwt.NewNumericDate(now.Add(t.accessTTL)),
+		},
+	}
+
+	token, err := jwt.NewWithClaims(jwt.SigningMethodHS256, claims).SignedString(t.secret)
+	if err != nil {
+		return "", fmt.Errorf("error signing access token: %w", err)
+	}
+	return token, nil
+}
+
+// ParseAccess verifies an access token and returns the principal it was
+// issued to.
+func (t *Tokens) ParseAccess(token string) (Principal, error) {
+	var claims accessClaims
+	_, err := jwt.ParseWithClaims(token, &claims,
+		func(*jwt.Token) (interface{}, error) { return t.secret, nil },
+		jwt.WithValidMethods([]string{jwt.SigningMethodHS256.Alg()}),
+		jwt.WithIssuer(Issuer),
+		jwt.WithExpirationRequired(),
+		jwt.WithTimeFunc(t.now),
+	)
+	if err != nil {
+		return Principal{}, ErrInvalidToken
+	}
+
+	userID, err := strconv.Atoi(claims.Subject)
+	if err != nil || userID <= 0 {
+		return Principal{}, ErrInvalidToken
+	}
+	if _, err := ParseRole(string(claims.Role)); err != nil {
+		return Principal{}, ErrInvalidToken
+	}
+	return Principal{UserID: userID, Role: claims.Role}, nil
+}
+
+// NewRefresh returns a random refresh token, the hash to store in its place
+// and its expiry.
+func (t *Tokens) NewRefresh() (token, hash string, expiresAt time.Time, err error) {
+	raw := make([]byte, 32)
+	if _, err := rand.Read(raw); err != nil {
+		return "", "", time.Time{}, fmt.Errorf("error generating refresh token: %w", err)
+	}
+	token = base64.RawURLEncoding.EncodeToString(raw)
+	return token, HashRefresh(token), t.now().Add(t.refreshTTL), nil
+}
+
+// HashRefresh returns the stored form of a refresh token.
+func HashRefresh(token string) string {
+	return hashSecret(token)
+}
+
+// hashSecret returns the SHA-256 hex digest stored in place of a random
+// secret. The secrets carry 256 bits of entropy, so a fast hash is enough.
+func hashSecret(secret string) string {
+	sum := sha256.Sum256([]byte(secret))
+	return hex.EncodeToString(sum[:])
+}
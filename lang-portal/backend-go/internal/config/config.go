@@ -0,0 +1,573 @@
+package config
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"log/slog"
+	"net"
+	"net/url"
+	"os"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/pelletier/go-toml/v2"
+	"github.com/souheilbenslama/free-genai-bootcamp-2025/lang-portal/backend-go/internal/ratelimit"
+	"gopkg.in/yaml.v3"
+)
+
+// EnvPrefix prefixes every environment variable read by Load.
+const EnvPrefix = "LANGPORTAL_"
+
+// Config is the runtime configuration of the API server.
+type Config struct {
+	// ListenAddr is the host:port the HTTP server binds to.
+	ListenAddr string
+	// DBPath is the SQLite database file.
+	DBPath string
+	// MigrationsDir overrides the migrations embedded in the binary.
+	MigrationsDir string
+	// SeedDir overrides the seed data embedded in the binary.
+	SeedDir string
+	// WebDir overrides the frontend bundle embedded in the binary.
+	WebDir string
+	// Seed loads the seed data at startup.
+	Seed bool
+	// LogLevel is one of debug, info, warn or error.
+	LogLevel string
+	// CORSOrigins lists the origins allowed to call the API from a browser;
+	// "*" allows any. The default admits the Vite dev server.
+	CORSOrigins     []string
+	CORSMethods     []string
+	CORSHeaders     []string
+	CORSCredentials bool
+	// CORSMaxAge is how long browsers may cache preflight answers.
+	CORSMaxAge time.Duration
+
+	ReadTimeout  time.Duration
+	WriteTimeout time.Duration
+	IdleTimeout  time.Duration
+	// ShutdownTimeout bounds how long in-flight requests and background
+	// workers get to finish after a shutdown signal.
+	ShutdownTimeout time.Duration
+
+	// TracingExporter is one of none, stdout, file or otlp.
+	TracingExporter string
+	// TracingFile receives JSON spans when TracingExporter is file.
+	TracingFile string
+	// OTLPEndpoint is the collector URL used when TracingExporter is otlp.
+	OTLPEndpoint string
+	// TracingSampleRatio is the fraction of new traces that are recorded.
+	TracingSampleRatio float64
+
+	// AuthSecret signs access tokens. When empty a random secret is generated
+	// at startup, so tokens do not survive a restart.
+	AuthSecret      string
+	AccessTokenTTL  time.Duration
+	RefreshTokenTTL time.Duration
+
+	// RateLimitDefault applies to every API route, per API key, user or
+	// client IP. RateLimitAuth replaces it on the account routes and
+	// RateLimitReviews on recording reviews.
+	RateLimitDefault ratelimit.Policy
+	RateLimitAuth    ratelimit.Policy
+	RateLimitReviews ratelimit.Policy
+	// MaxBodyBytes caps request bodies.
+	MaxBodyBytes int64
+	// TrustedProxies are the proxies whose X-Forwarded-For header is believed
+	// when finding the client IP.
+	TrustedProxies []string
+
+	// SessionIdleTimeout is how long a study session may go without reviews
+	// before it is abandoned. Zero keeps sessions open until finished.
+	SessionIdleTimeout time.Duration
+	// GradingTolerance is how many letters a typed answer may be off and
+	// still count as almost correct.
+	GradingTolerance int
+}
+
+// Default returns the configuration used when nothing is overridden.
+func Default() *Config {
+	return &Config{
+		ListenAddr:   ":8080",
+		DBPath:       "words.db",
+		Seed:         true,
+		LogLevel:     "info",
+		ReadTimeout:  15 * time.Second,
+		WriteTimeout: 30 * time.Second,
+		IdleTimeout:  60 * time.Second,
+
+		ShutdownTimeout: 20 * time.Second,
+
+		CORSOrigins: []string{"http://localhost:5173", "http://127.0.0.1:5173"},
+		CORSMethods: []string{"GET", "POST", "PUT", "DELETE"},
+		CORSHeaders: []string{"Authorization", "Content-Type", "X-Request-ID"},
+		CORSMaxAge:  10 * time.Minute,
+
+		TracingExporter:    "none",
+		TracingFile:        "traces.json",
+		OTLPEndpoint:       "http://localhost:4318",
+		TracingSampleRatio: 1,
+
+		AccessTokenTTL:  15 * time.Minute,
+		RefreshTokenTTL: 30 * 24 * time.Hour,
+
+		RateLimitDefault: ratelimit.Policy{Requests: 300, Period: time.Minute},
+		RateLimitAuth:    ratelimit.Policy{Requests: 10, Period: time.Minute},
+		RateLimitReviews: ratelimit.Policy{Requests: 120, Period: time.Minute},
+		MaxBodyBytes:     1 << 20,
+
+		SessionIdleTimeout: 30 * time.Minute,
+		GradingTolerance:   1,
+	}
+}
+
+// option binds one configuration key to its flag, environment variable and
+// config file entry. Every source is applied as a string through set.
+type option struct {
+	key   string
+	usage string
+	set   func(c *Config, value string) error
+	get   func(c *Config) string
+}
+
+func (o option) flagName() string { return strings.ReplaceAll(o.key, "_", "-") }
+func (o option) envName() string  { return EnvPrefix + strings.ToUpper(o.key) }
+
+var options = []option{
+	{
+		key:   "listen_addr",
+		usage: "address the HTTP server listens on",
+		set:   func(c *Config, v string) error { c.ListenAddr = v; return nil },
+		get:   func(c *Config) string { return c.ListenAddr },
+	},
+	{
+		key:   "db_path",
+		usage: "path of the SQLite database file",
+		set:   func(c *Config, v string) error { c.DBPath = v; return nil },
+		get:   func(c *Config) string { return c.DBPath },
+	},
+	{
+		key:   "migrations_dir",
+		usage: "directory of SQL migrations (default: embedded)",
+		set:   func(c *Config, v string) error { c.MigrationsDir = v; return nil },
+		get:   func(c *Config) string { return orEmbedded(c.MigrationsDir) },
+	},
+	{
+		key:   "seed_dir",
+		usage: "directory of JSON seed data (default: embedded)",
+		set:   func(c *Config, v string) error { c.SeedDir = v; return nil },
+		get:   func(c *Config) string { return orEmbedded(c.SeedDir) },
+	},
+	{
+		key:   "web_dir",
+		usage: "directory of the built frontend (default: embedded)",
+		set:   func(c *Config, v string) error { c.WebDir = v; return nil },
+		get:   func(c *Config) string { return orEmbedded(c.WebDir) },
+	},
+	{
+		key:   "seed",
+		usage: "load seed data at startup",
+		set:   func(c *Config, v string) (err error) { c.Seed, err = strconv.ParseBool(v); return err },
+		get:   func(c *Config) string { return strconv.FormatBool(c.Seed) },
+	},
+	{
+		key:   "log_level",
+		usage: "log level: debug, info, warn or error",
+		set:   func(c *Config, v string) error { c.LogLevel = strings.ToLower(v); return nil },
+		get:   func(c *Config) string { return c.LogLevel },
+	},
+	{
+		key:   "cors_origins",
+		usage: "comma separated origins allowed to call the API from a browser",
+		set:   func(c *Config, v string) error { c.CORSOrigins = splitList(v); return nil },
+		get:   func(c *Config) string { return strings.Join(c.CORSOrigins, ",") },
+	},
+	{
+		key:   "cors_methods",
+		usage: "comma separated HTTP methods allowed from a browser",
+		set:   func(c *Config, v string) error { c.CORSMethods = splitList(strings.ToUpper(v)); return nil },
+		get:   func(c *Config) string { return strings.Join(c.CORSMethods, ",") },
+	},
+	{
+		key:   "cors_headers",
+		usage: "comma separated request headers allowed from a browser",
+		set:   func(c *Config, v string) error { c.CORSHeaders = splitList(v); return nil },
+		get:   func(c *Config) string { return strings.Join(c.CORSHeaders, ",") },
+	},
+	{
+		key:   "cors_credentials",
+		usage: "let browsers send cookies and HTTP auth with cross-origin requests",
+		set:   func(c *Config, v string) (err error) { c.CORSCredentials, err = strconv.ParseBool(v); return err },
+		get:   func(c *Config) string { return strconv.FormatBool(c.CORSCredentials) },
+	},
+	{
+		key:   "cors_max_age",
+		usage: "how long browsers may cache preflight answers",
+		set:   func(c *Config, v string) (err error) { c.CORSMaxAge, err = time.ParseDuration(v); return err },
+		get:   func(c *Config) string { return c.CORSMaxAge.String() },
+	},
+	{
+		key:   "read_timeout",
+		usage: "maximum duration for reading a request",
+		set:   func(c *Config, v string) (err error) { c.ReadTimeout, err = time.ParseDuration(v); return err },
+		get:   func(c *Config) string { return c.ReadTimeout.String() },
+	},
+	{
+		key:   "write_timeout",
+		usage: "maximum duration for writing a response",
+		set:   func(c *Config, v string) (err error) { c.WriteTimeout, err = time.ParseDuration(v); return err },
+		get:   func(c *Config) string { return c.WriteTimeout.String() },
+	},
+	{
+		key:   "idle_timeout",
+		usage: "maximum keep-alive idle time",
+		set:   func(c *Config, v string) (err error) { c.IdleTimeout, err = time.ParseDuration(v); return err },
+		get:   func(c *Config) string { return c.IdleTimeout.String() },
+	},
+	{
+		key:   "shutdown_timeout",
+		usage: "time allowed to drain connections and stop workers on shutdown",
+		set:   func(c *Config, v string) (err error) { c.ShutdownTimeout, err = time.ParseDuration(v); return err },
+		get:   func(c *Config) string { return c.ShutdownTimeout.String() },
+	},
+	{
+		key:   "tracing_exporter",
+		usage: "where to send traces: none, stdout, file or otlp",
+		set:   func(c *Config, v string) error { c.TracingExporter = strings.ToLower(v); return nil },
+		get:   func(c *Config) string { return c.TracingExporter },
+	},
+	{
+		key:   "tracing_file",
+		usage: "file receiving spans when tracing_exporter is file",
+		set:   func(c *Config, v string) error { c.TracingFile = v; return nil },
+		get:   func(c *Config) string { return c.TracingFile },
+	},
+	{
+		key:   "otlp_endpoint",
+		usage: "OTLP/HTTP collector URL used when tracing_exporter is otlp",
+		set:   func(c *Config, v string) error { c.OTLPEndpoint = v; return nil },
+		get:   func(c *Config) string { return c.OTLPEndpoint },
+	},
+	{
+		key:   "tracing_sample_ratio",
+		usage: "fraction of traces to record, from 0 to 1",
+		set: func(c *Config, v string) (err error) {
+			c.TracingSampleRatio, err = strconv.ParseFloat(v, 64)
+			return err
+		},
+		get: func(c *Config) string { return strconv.FormatFloat(c.TracingSampleRatio, 'g', -1, 64) },
+	},
+	{
+		key:   "auth_secret",
+		usage: "secret of at least 32 bytes signing access tokens (default: random per start)",
+		set:   func(c *Config, v string) error { c.AuthSecret = v; return nil },
+		get:   func(c *Config) string { return redacted(c.AuthSecret) },
+	},
+	{
+		key:   "access_token_ttl",
+		usage: "lifetime of access tokens",
+		set:   func(c *Config, v string) (err error) { c.AccessTokenTTL, err = time.ParseDuration(v); return err },
+		get:   func(c *Config) string { return c.AccessTokenTTL.String() },
+	},
+	{
+		key:   "refresh_token_ttl",
+		usage: "lifetime of refresh tokens",
+		set:   func(c *Config, v string) (err error) { c.RefreshTokenTTL, err = time.ParseDuration(v); return err },
+		get:   func(c *Config) string { return c.RefreshTokenTTL.String() },
+	},
+	{
+		key:   "rate_limit_default",
+		usage: "requests per period allowed on API routes, like 300/1m, or off",
+		set:   func(c *Config, v string) (err error) { c.RateLimitDefault, err = ratelimit.ParsePolicy(v); return err },
+		get:   func(c *Config) string { return c.RateLimitDefault.String() },
+	},
+	{
+		key:   "rate_limit_auth",
+		usage: "requests per period allowed on /api/auth routes, or off",
+		set:   func(c *Config, v string) (err error) { c.RateLimitAuth, err = ratelimit.ParsePolicy(v); return err },
+		get:   func(c *Config) string { return c.RateLimitAuth.String() },
+	},
+	{
+		key:   "rate_limit_reviews",
+		usage: "requests per period allowed for recording reviews, or off",
+		set:   func(c *Config, v string) (err error) { c.RateLimitReviews, err = ratelimit.ParsePolicy(v); return err },
+		get:   func(c *Config) string { return c.RateLimitReviews.String() },
+	},
+	{
+		key:   "max_body_bytes",
+		usage: "largest accepted request body in bytes, 0 for no limit",
+		set:   func(c *Config, v string) (err error) { c.MaxBodyBytes, err = strconv.ParseInt(v, 10, 64); return err },
+		get:   func(c *Config) string { return strconv.FormatInt(c.MaxBodyBytes, 10) },
+	},
+	{
+		key:   "trusted_proxies",
+		usage: "comma separated IPs or CIDRs of reverse proxies allowed to set X-Forwarded-For",
+		set:   func(c *Config, v string) error { c.TrustedProxies = splitList(v); return nil },
+		get:   func(c *Config) string { return strings.Join(c.TrustedProxies, ",") },
+	},
+	{
+		key:   "session_idle_timeout",
+		usage: "idle time after which study sessions are abandoned, 0 to keep them open",
+		set:   func(c *Config, v string) (err error) { c.SessionIdleTimeout, err = time.ParseDuration(v); return err },
+		get:   func(c *Config) string { return c.SessionIdleTimeout.String() },
+	},
+	{
+		key:   "grading_tolerance",
+		usage: "letters a typed answer may be off and still count as almost correct",
+		set:   func(c *Config, v string) (err error) { c.GradingTolerance, err = strconv.Atoi(v); return err },
+		get:   func(c *Config) string { return strconv.Itoa(c.GradingTolerance) },
+	},
+}
+
+// Load builds the configuration from, in increasing precedence, the
+// defaults, an optional YAML or TOML file, LANGPORTAL_* environment variables
+// and command line flags. The file is named by --config or LANGPORTAL_CONFIG.
+func Load(args []string, getenv func(string) string, output io.Writer) (*Config, error) {
+	cfg := Default()
+
+	fs := flag.NewFlagSet("lang-portal", flag.ContinueOnError)
+	fs.SetOutput(output)
+	configFile := fs.String("config", "", "optional YAML or TOML config file")
+	for _, opt := range options {
+		fs.String(opt.flagName(), "", opt.usage)
+	}
+	if err := fs.Parse(args); err != nil {
+		return nil, err
+	}
+
+	if *configFile == "" {
+		*configFile = getenv(EnvPrefix + "CONFIG")
+	}
+	if *configFile != "" {
+		if err := cfg.loadFile(*configFile); err != nil {
+			return nil, err
+		}
+	}
+
+	for _, opt := range options {
+		if value := getenv(opt.envName()); value != "" {
+			if err := opt.set(cfg, value); err != nil {
+				return nil, fmt.Errorf("invalid %s: %w", opt.envName(), err)
+			}
+		}
+	}
+
+	var flagErr error
+	fs.Visit(func(f *flag.Flag) {
+		for _, opt := range options {
+			if opt.flagName() == f.Name && flagErr == nil {
+				if err := opt.set(cfg, f.Value.String()); err != nil {
+					flagErr = fmt.Errorf("invalid --%s: %w", f.Name, err)
+				}
+			}
+		}
+	})
+	if flagErr != nil {
+		return nil, flagErr
+	}
+
+	if err := cfg.Validate(); err != nil {
+		return nil, err
+	}
+
+	return cfg, nil
+}
+
+func (c *Config) loadFile(path string) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return fmt.Errorf("error reading config file: %w", err)
+	}
+
+	values := map[string]interface{}{}
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".yaml", ".yml":
+		err = yaml.Unmarshal(data, &values)
+	case ".toml":
+		err = toml.Unmarshal(data, &values)
+	default:
+		return fmt.Errorf("unsupported config file type %q, use .yaml, .yml or .toml", filepath.Ext(path))
+	}
+	if err != nil {
+		return fmt.Errorf("error parsing config file %s: %w", path, err)
+	}
+
+	for key, raw := range values {
+		opt, ok := lookup(key)
+		if !ok {
+			return fmt.Errorf("unknown config key %q in %s", key, path)
+		}
+		if err := opt.set(c, stringify(raw)); err != nil {
+			return fmt.Errorf("invalid %s in %s: %w", key, path, err)
+		}
+	}
+
+	return nil
+}
+
+// Validate reports every invalid setting at once.
+func (c *Config) Validate() error {
+	var errs []error
+
+	if _, _, err := net.SplitHostPort(c.ListenAddr); err != nil {
+		errs = append(errs, fmt.Errorf("listen_addr %q: %w", c.ListenAddr, err))
+	}
+	if c.DBPath == "" {
+		errs = append(errs, errors.New("db_path must not be empty"))
+	}
+	for _, dir := range []struct{ key, path string }{
+		{"migrations_dir", c.MigrationsDir},
+		{"seed_dir", c.SeedDir},
+		{"web_dir", c.WebDir},
+	} {
+		if dir.path == "" {
+			continue
+		}
+		if info, err := os.Stat(dir.path); err != nil || !info.IsDir() {
+			errs = append(errs, fmt.Errorf("%s %q is not a directory", dir.key, dir.path))
+		}
+	}
+	switch c.LogLevel {
+	case "debug", "info", "warn", "error":
+	default:
+		errs = append(errs, fmt.Errorf("log_level %q must be debug, info, warn or error", c.LogLevel))
+	}
+	for _, origin := range c.CORSOrigins {
+		if origin == "*" {
+			continue
+		}
+		if u, err := url.Parse(origin); err != nil || u.Scheme == "" || u.Host == "" {
+			errs = append(errs, fmt.Errorf("cors_origins entry %q must be an origin like http://localhost:5173", origin))
+		}
+	}
+	if c.CORSCredentials && slices.Contains(c.CORSOrigins, "*") {
+		errs = append(errs, errors.New("cors_credentials cannot be combined with the cors_origins wildcard"))
+	}
+	for _, method := range c.CORSMethods {
+		switch method {
+		case "GET", "HEAD", "POST", "PUT", "PATCH", "DELETE":
+		default:
+			errs = append(errs, fmt.Errorf("cors_methods entry %q must be an HTTP method", method))
+		}
+	}
+	if c.CORSMaxAge < 0 {
+		errs = append(errs, fmt.Errorf("cors_max_age %s must not be negative", c.CORSMaxAge))
+	}
+	if c.SessionIdleTimeout < 0 {
+		errs = append(errs, fmt.Errorf("session_idle_timeout %s must not be negative", c.SessionIdleTimeout))
+	}
+	if c.GradingTolerance < 0 {
+		errs = append(errs, fmt.Errorf("grading_tolerance %d must not be negative", c.GradingTolerance))
+	}
+	for _, timeout := range []struct {
+		key   string
+		value time.Duration
+	}{
+		{"read_timeout", c.ReadTimeout},
+		{"write_timeout", c.WriteTimeout},
+		{"idle_timeout", c.IdleTimeout},
+		{"shutdown_timeout", c.ShutdownTimeout},
+		{"access_token_ttl", c.AccessTokenTTL},
+		{"refresh_token_ttl", c.RefreshTokenTTL},
+	} {
+		if timeout.value <= 0 {
+			errs = append(errs, fmt.Errorf("%s must be positive", timeout.key))
+		}
+	}
+	switch c.TracingExporter {
+	case "none", "stdout":
+	case "file":
+		if c.TracingFile == "" {
+			errs = append(errs, errors.New("tracing_file must not be empty when tracing_exporter is file"))
+		}
+	case "otlp":
+		if u, err := url.Parse(c.OTLPEndpoint); err != nil || u.Scheme == "" || u.Host == "" {
+			errs = append(errs, fmt.Errorf("otlp_endpoint %q must be a URL like http://localhost:4318", c.OTLPEndpoint))
+		}
+	default:
+		errs = append(errs, fmt.Errorf("tracing_exporter %q must be none, stdout, file or otlp", c.TracingExporter))
+	}
+	if c.AuthSecret != "" && len(c.AuthSecret) < 32 {
+		errs = append(errs, errors.New("auth_secret must be at least 32 bytes"))
+	}
+	for _, proxy := range c.TrustedProxies {
+		if _, _, err := net.ParseCIDR(proxy); err != nil && net.ParseIP(proxy) == nil {
+			errs = append(errs, fmt.Errorf("trusted_proxies entry %q must be an IP or CIDR", proxy))
+		}
+	}
+	if c.MaxBodyBytes < 0 {
+		errs = append(errs, fmt.Errorf("max_body_bytes %d must not be negative", c.MaxBodyBytes))
+	}
+	if c.TracingSampleRatio < 0 || c.TracingSampleRatio > 1 {
+		errs = append(errs, fmt.Errorf("tracing_sample_ratio %v must be between 0 and 1", c.TracingSampleRatio))
+	}
+
+	return errors.Join(errs...)
+}
+
+// String renders the effective configuration, one key per line.
+func (c *Config) String() string {
+	var b strings.Builder
+	for _, opt := range options {
+		fmt.Fprintf(&b, "%s=%s\n", opt.key, opt.get(c))
+	}
+	return b.String()
+}
+
+// LogValue renders the effective configuration as a group of attributes.
+func (c *Config) LogValue() slog.Value {
+	attrs := make([]slog.Attr, len(options))
+	for i, opt := range options {
+		attrs[i] = slog.String(opt.key, opt.get(c))
+	}
+	return slog.GroupValue(attrs...)
+}
+
+func lookup(key string) (option, bool) {
+	for _, opt := range options {
+		if opt.key == key {
+			return opt, true
+		}
+	}
+	return option{}, false
+}
+
+func stringify(raw interface{}) string {
+	if list, ok := raw.([]interface{}); ok {
+		items := make([]string, len(list))
+		for i, item := range list {
+			items[i] = fmt.Sprint(item)
+		}
+		return strings.Join(items, ",")
+	}
+	return fmt.Sprint(raw)
+}
+
+func splitList(value string) []string {
+	var items []string
+	for _, item := range strings.Split(value, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
+
+func redacted(secret string) string {
+	if secret == "" {
+		return "(generated)"
+	}
+	return "(set)"
+}
+
+func orEmbedded(dir string) string {
+	if dir == "" {
+		return "(embedded)"
+	}
+	return dir
+}
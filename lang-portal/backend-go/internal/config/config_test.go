@@ -0,0 +1,217 @@
+package config_test
+
+import (
+	"io"
+	"os"
+	"path/filepath"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"github.com/souheilbenslama/free-genai-bootcamp-2025/lang-portal/backend-go/internal/config"
+	"github.com/souheilbenslama/free-genai-bootcamp-2025/lang-portal/backend-go/internal/ratelimit"
+)
+
+var _ = Describe("Load", func() {
+	var env map[string]string
+
+	getenv := func(key string) string { return env[key] }
+
+	writeFile := func(name, content string) string {
+		path := filepath.Join(GinkgoT().TempDir(), name)
+		Expect(os.WriteFile(path, []byte(content), 0o644)).To(Succeed())
+		return path
+	}
+
+	BeforeEach(func() {
+		env = map[string]string{}
+	})
+
+	It("returns the defaults when nothing is set", func() {
+		cfg, err := config.Load(nil, getenv, io.Discard)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(cfg).To(Equal(config.Default()))
+	})
+
+	It("reads a YAML config file", func() {
+		path := writeFile("config.yaml", `
+listen_addr: 127.0.0.1:9090
+seed: false
+cors_origins:
+  - http://localhost:5173
+  - https://portal.example.com
+read_timeout: 5s
+`)
+
+		cfg, err := config.Load([]string{"--config", path}, getenv, io.Discard)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(cfg.ListenAddr).To(Equal("127.0.0.1:9090"))
+		Expect(cfg.Seed).To(BeFalse())
+		Expect(cfg.CORSOrigins).To(Equal([]string{"http://localhost:5173", "https://portal.example.com"}))
+		Expect(cfg.ReadTimeout).To(Equal(5 * time.Second))
+	})
+
+	It("reads a TOML config file named by the environment", func() {
+		env["LANGPORTAL_CONFIG"] = writeFile("config.toml", `
+db_path = "/var/lib/lang-portal/words.db"
+log_level = "debug"
+`)
+
+		cfg, err := config.Load(nil, getenv, io.Discard)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(cfg.DBPath).To(Equal("/var/lib/lang-portal/words.db"))
+		Expect(cfg.LogLevel).To(Equal("debug"))
+	})
+
+	It("prefers flags over environment over file", func() {
+		path := writeFile("config.yaml", "listen_addr: :7000\ndb_path: file.db\nlog_level: warn\n")
+		env["LANGPORTAL_LISTEN_ADDR"] = ":7001"
+		env["LANGPORTAL_DB_PATH"] = "env.db"
+
+		cfg, err := config.Load([]string{"--config", path, "--listen-addr", ":7002"}, getenv, io.Discard)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(cfg.ListenAddr).To(Equal(":7002"))
+		Expect(cfg.DBPath).To(Equal("env.db"))
+		Expect(cfg.LogLevel).To(Equal("warn"))
+	})
+
+	It("rejects unknown keys in the config file", func() {
+		path := writeFile("config.yaml", "listen_port: 8080\n")
+
+		_, err := config.Load([]string{"--config", path}, getenv, io.Discard)
+		Expect(err).To(MatchError(ContainSubstring(`unknown config key "listen_port"`)))
+	})
+
+	It("reports every invalid setting", func() {
+		env["LANGPORTAL_LOG_LEVEL"] = "verbose"
+		env["LANGPORTAL_CORS_ORIGINS"] = "localhost:5173"
+
+		_, err := config.Load([]string{"--listen-addr", "8080", "--seed-dir", "/does/not/exist"}, getenv, io.Discard)
+		Expect(err).To(HaveOccurred())
+		Expect(err.Error()).To(ContainSubstring("listen_addr"))
+		Expect(err.Error()).To(ContainSubstring("seed_dir"))
+		Expect(err.Error()).To(ContainSubstring("log_level"))
+		Expect(err.Error()).To(ContainSubstring("cors_origins"))
+	})
+
+	It("validates the tracing settings", func() {
+		env["LANGPORTAL_TRACING_EXPORTER"] = "jaeger"
+		env["LANGPORTAL_TRACING_SAMPLE_RATIO"] = "1.5"
+
+		_, err := config.Load(nil, getenv, io.Discard)
+		Expect(err).To(HaveOccurred())
+		Expect(err.Error()).To(ContainSubstring("tracing_exporter"))
+		Expect(err.Error()).To(ContainSubstring("tracing_sample_ratio"))
+
+		env = map[string]string{}
+		cfg, err := config.Load([]string{"--tracing-exporter", "otlp", "--otlp-endpoint", "http://collector:4318"}, getenv, io.Discard)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(cfg.TracingExporter).To(Equal("otlp"))
+		Expect(cfg.OTLPEndpoint).To(Equal("http://collector:4318"))
+	})
+
+	It("rejects malformed durations", func() {
+		env["LANGPORTAL_WRITE_TIMEOUT"] = "soon"
+
+		_, err := config.Load(nil, getenv, io.Discard)
+		Expect(err).To(MatchError(ContainSubstring("LANGPORTAL_WRITE_TIMEOUT")))
+	})
+
+	It("prints the effective configuration", func() {
+		cfg := config.Default()
+
+		Expect(cfg.String()).To(ContainSubstring("listen_addr=:8080\n"))
+		Expect(cfg.String()).To(ContainSubstring("migrations_dir=(embedded)\n"))
+	})
+
+	It("never prints the auth secret", func() {
+		env["LANGPORTAL_AUTH_SECRET"] = "0123456789abcdef0123456789abcdef"
+
+		cfg, err := config.Load(nil, getenv, io.Discard)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(cfg.AuthSecret).To(Equal("0123456789abcdef0123456789abcdef"))
+		Expect(cfg.String()).To(ContainSubstring("auth_secret=(set)\n"))
+		Expect(cfg.String()).NotTo(ContainSubstring("0123456789abcdef"))
+	})
+
+	It("rejects short auth secrets", func() {
+		env["LANGPORTAL_AUTH_SECRET"] = "secret"
+
+		_, err := config.Load(nil, getenv, io.Discard)
+		Expect(err).To(MatchError(ContainSubstring("auth_secret")))
+	})
+
+	It("lets the Vite dev server call the API by default", func() {
+		cfg, err := config.Load(nil, getenv, io.Discard)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(cfg.CORSOrigins).To(ContainElement("http://localhost:5173"))
+		Expect(cfg.CORSHeaders).To(ContainElement("Authorization"))
+		Expect(cfg.CORSCredentials).To(BeFalse())
+	})
+
+	It("validates the CORS settings", func() {
+		env["LANGPORTAL_CORS_ORIGINS"] = "*"
+		env["LANGPORTAL_CORS_CREDENTIALS"] = "true"
+		env["LANGPORTAL_CORS_METHODS"] = "get,fetch"
+
+		_, err := config.Load(nil, getenv, io.Discard)
+		Expect(err).To(HaveOccurred())
+		Expect(err.Error()).To(ContainSubstring("cors_credentials"))
+		Expect(err.Error()).To(ContainSubstring(`cors_methods entry "FETCH"`))
+	})
+
+	It("loads the configuration of every environment", func() {
+		for _, path := range []string{"../../config/development.yaml", "../../config/production.yaml"} {
+			_, err := config.Load([]string{"--config", path}, getenv, io.Discard)
+			Expect(err).NotTo(HaveOccurred(), path)
+		}
+	})
+
+	It("reads rate limits and request caps", func() {
+		env["LANGPORTAL_RATE_LIMIT_DEFAULT"] = "100/30s"
+		env["LANGPORTAL_RATE_LIMIT_REVIEWS"] = "off"
+		env["LANGPORTAL_MAX_BODY_BYTES"] = "4096"
+		env["LANGPORTAL_TRUSTED_PROXIES"] = "10.0.0.0/8,127.0.0.1"
+
+		cfg, err := config.Load(nil, getenv, io.Discard)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(cfg.RateLimitDefault).To(Equal(ratelimit.Policy{Requests: 100, Period: 30 * time.Second}))
+		Expect(cfg.RateLimitReviews.Enabled()).To(BeFalse())
+		Expect(cfg.RateLimitAuth).To(Equal(ratelimit.Policy{Requests: 10, Period: time.Minute}))
+		Expect(cfg.MaxBodyBytes).To(Equal(int64(4096)))
+		Expect(cfg.TrustedProxies).To(Equal([]string{"10.0.0.0/8", "127.0.0.1"}))
+
+		env["LANGPORTAL_RATE_LIMIT_AUTH"] = "lots"
+		_, err = config.Load(nil, getenv, io.Discard)
+		Expect(err).To(MatchError(ContainSubstring("rate limit")))
+
+		delete(env, "LANGPORTAL_RATE_LIMIT_AUTH")
+		env["LANGPORTAL_TRUSTED_PROXIES"] = "proxy.local"
+		_, err = config.Load(nil, getenv, io.Discard)
+		Expect(err).To(MatchError(ContainSubstring("trusted_proxies")))
+	})
+
+	It("reads the session idle timeout", func() {
+		Expect(config.Default().SessionIdleTimeout).To(Equal(30 * time.Minute))
+
+		cfg, err := config.Load([]string{"--session-idle-timeout", "0"}, getenv, io.Discard)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(cfg.SessionIdleTimeout).To(BeZero())
+
+		env["LANGPORTAL_SESSION_IDLE_TIMEOUT"] = "-1m"
+		_, err = config.Load(nil, getenv, io.Discard)
+		Expect(err).To(MatchError(ContainSubstring("session_idle_timeout")))
+	})
+
+	It("reads the grading tolerance", func() {
+		Expect(config.Default().GradingTolerance).To(Equal(1))
+
+		env["LANGPORTAL_GRADING_TOLERANCE"] = "2"
+		cfg, err := config.Load(nil, getenv, io.Discard)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(cfg.GradingTolerance).To(Equal(2))
+
+		_, err = config.Load([]string{"--grading-tolerance", "-1"}, getenv, io.Discard)
+		Expect(err).To(MatchError(ContainSubstring("grading_tolerance")))
+	})
+})
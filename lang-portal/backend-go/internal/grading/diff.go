@@ -0,0 +1,86 @@
+package grading
+
+// Operations of an Edit.
+const (
+	// DiffEqual is text both answers have.
+	DiffEqual = "equal"
+	// DiffInsert is text only the given answer has.
+	DiffInsert = "insert"
+	// DiffDelete is text only the expected answer has.
+	DiffDelete = "delete"
+)
+
+// Edit is a run of letters of a diff.
+type Edit struct {
+	Op   string `json:"op"`
+	Text string `json:"text"`
+}
+
+// Distance is the Levenshtein distance between a and b in letters.
+func Distance(a, b string) int {
+	return distances([]rune(a), []rune(b))[len([]rune(a))][len([]rune(b))]
+}
+
+// Diff lists the edits turning expected into answer letter by letter, with
+// the fewest inserted and deleted letters a Levenshtein alignment allows.
+func Diff(expected, answer string) []Edit {
+	e, a := []rune(expected), []rune(answer)
+	d := distances(e, a)
+
+	// Walk back from the end, so edits come out in reverse
+	var reversed []Edit
+	add := func(op string, r rune) {
+		if n := len(reversed); n > 0 && reversed[n-1].Op == op {
+			reversed[n-1].Text = string(r) + reversed[n-1].Text
+			return
+		}
+		reversed = append(reversed, Edit{Op: op, Text: string(r)})
+	}
+	i, j := len(e), len(a)
+	for i > 0 || j > 0 {
+		switch {
+		case i > 0 && j > 0 && e[i-1] == a[j-1] && d[i][j] == d[i-1][j-1]:
+			add(DiffEqual, e[i-1])
+			i, j = i-1, j-1
+		case i > 0 && j > 0 && d[i][j] == d[i-1][j-1]+1:
+			add(DiffInsert, a[j-1])
+			add(DiffDelete, e[i-1])
+			i, j = i-1, j-1
+		case i > 0 && d[i][j] == d[i-1][j]+1:
+			add(DiffDelete, e[i-1])
+			i--
+		default:
+			add(DiffInsert, a[j-1])
+			j--
+		}
+	}
+
+	edits := make([]Edit, 0, len(reversed))
+	for k := len(reversed) - 1; k >= 0; k-- {
+		edits = append(edits, reversed[k])
+	}
+	return edits
+}
+
+// distances holds the Levenshtein distance between every prefix of a and
+// every prefix of b.
+func distances(a, b []rune) [][]int {
+	d := make([][]int, len(a)+1)
+	for i := range d {
+		d[i] = make([]int, len(b)+1)
+		d[i][0] = i
+	}
+	for j := range d[0] {
+		d[0][j] = j
+	}
+	for i := 1; i <= len(a); i++ {
+		for j := 1; j <= len(b); j++ {
+			cost := 1
+			if a[i-1] == b[j-1] {
+				cost = 0
+			}
+			d[i][j] = min(d[i-1][j]+1, d[i][j-1]+1, d[i-1][j-1]+cost)
+		}
+	}
+	return d
+}
@@ -0,0 +1,124 @@
+// Package grading judges typed answers against the expected answer,
+// forgiving what a keyboard without umlauts or a quick typist gets wrong.
+package grading
+
+import (
+	"slices"
+	"strings"
+	"unicode"
+
+	"golang.org/x/text/unicode/norm"
+)
+
+// Verdicts on an answer.
+const (
+	VerdictCorrect = "correct"
+	// VerdictAlmost is an answer with small mistakes, listed in
+	// Result.Mistakes.
+	VerdictAlmost = "almost"
+	VerdictWrong  = "wrong"
+)
+
+// Mistakes of answers that are almost correct.
+const (
+	MistakeArticle        = "article"
+	MistakeCapitalization = "capitalization"
+	MistakeSpelling       = "spelling"
+)
+
+// Verdict is the verdict on an answer already known to be right or wrong.
+func Verdict(correct bool) string {
+	if correct {
+		return VerdictCorrect
+	}
+	return VerdictWrong
+}
+
+// Result is the verdict on an answer. Distance is the number of letters to
+// change to turn the answer into the expected one, not counting the article,
+// capitalization or spelled out umlauts.
+type Result struct {
+	Verdict  string   `json:"verdict"`
+	Expected string   `json:"expected"`
+	Answer   string   `json:"answer"`
+	Mistakes []string `json:"mistakes"`
+	Distance int      `json:"distance"`
+	Diff     []Edit   `json:"diff"`
+}
+
+// Grader grades answers. Answers within Tolerance letters of the expected
+// answer are almost correct.
+type Grader struct {
+	Tolerance int
+}
+
+// articles are the articles an answer may start with.
+var articles = []string{"der", "die", "das"}
+
+// spelledOut writes umlauts and ß the way keyboards without them do.
+var spelledOut = strings.NewReplacer("ä", "ae", "ö", "oe", "ü", "ue", "Ä", "Ae", "Ö", "Oe", "Ü", "Ue", "ß", "ss")
+
+// Grade judges answer against expected. Both are compared in Unicode NFC with
+// surrounding and repeated spaces removed, and ae, oe, ue and ss are as good
+// as ä, ö, ü and ß. When expected starts with an article, a missing or
+// different article is a mistake of its own, as is a wrongly capitalized
+// word.
+func (g Grader) Grade(expected, answer string) Result {
+	expected, answer = normalize(expected), normalize(answer)
+	result := Result{Expected: expected, Answer: answer, Mistakes: []string{}, Diff: Diff(expected, answer)}
+
+	want, wantWord := splitArticle(expected)
+	got, gotWord := answer, answer
+	if want != "" {
+		got, gotWord = splitArticle(answer)
+		// The article starts the answer, so its case does not matter
+		if !strings.EqualFold(want, got) {
+			result.Mistakes = append(result.Mistakes, MistakeArticle)
+		}
+	}
+
+	wantWord, gotWord = spelledOut.Replace(wantWord), spelledOut.Replace(gotWord)
+	if wantWord != gotWord {
+		if capitalizedDifferently(wantWord, gotWord) || strings.ToLower(wantWord) == strings.ToLower(gotWord) {
+			result.Mistakes = append(result.Mistakes, MistakeCapitalization)
+		}
+		result.Distance = Distance(strings.ToLower(wantWord), strings.ToLower(gotWord))
+		if result.Distance > 0 {
+			result.Mistakes = append(result.Mistakes, MistakeSpelling)
+		}
+	}
+
+	switch {
+	case result.Distance > g.Tolerance:
+		result.Verdict = VerdictWrong
+	case len(result.Mistakes) > 0:
+		result.Verdict = VerdictAlmost
+	default:
+		result.Verdict = VerdictCorrect
+	}
+	return result
+}
+
+func normalize(s string) string {
+	return strings.Join(strings.Fields(norm.NFC.String(s)), " ")
+}
+
+// splitArticle splits a leading article off s. It returns an empty article
+// when s does not start with one or is nothing but an article.
+func splitArticle(s string) (article, rest string) {
+	article, rest, ok := strings.Cut(s, " ")
+	if !ok || !slices.Contains(articles, strings.ToLower(article)) {
+		return "", s
+	}
+	return article, rest
+}
+
+// capitalizedDifferently reports whether a and b start with the same letter
+// in different case, like a noun typed in lower case.
+func capitalizedDifferently(a, b string) bool {
+	ra, rb := []rune(a), []rune(b)
+	if len(ra) == 0 || len(rb) == 0 || ra[0] == rb[0] {
+		return false
+	}
+	return unicode.ToLower(ra[0]) == unicode.ToLower(rb[0])
+}
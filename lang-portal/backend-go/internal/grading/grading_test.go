@@ -0,0 +1,73 @@
+package grading_test
+
+import (
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/souheilbenslama/free-genai-bootcamp-2025/lang-portal/backend-go/internal/grading"
+)
+
+var _ = Describe("Grader", func() {
+	grader := grading.Grader{Tolerance: 1}
+
+	DescribeTable("verdicts",
+		func(expected, answer, verdict string, mistakes ...string) {
+			result := grader.Grade(expected, answer)
+			Expect(result.Verdict).To(Equal(verdict))
+			if mistakes == nil {
+				mistakes = []string{}
+			}
+			Expect(result.Mistakes).To(Equal(mistakes))
+		},
+		Entry("exact", "das Haus", "das Haus", grading.VerdictCorrect),
+		Entry("extra spaces", "das Haus", "  das   Haus ", grading.VerdictCorrect),
+		Entry("decomposed umlaut", "die Häuser", "die Ha\u0308user", grading.VerdictCorrect),
+		Entry("spelled out umlaut", "die Häuser", "die Haeuser", grading.VerdictCorrect),
+		Entry("spelled out ß", "die Straße", "die Strasse", grading.VerdictCorrect),
+		Entry("capitalized article", "das Haus", "Das Haus", grading.VerdictCorrect),
+		Entry("wrong article", "das Haus", "der Haus", grading.VerdictAlmost, grading.MistakeArticle),
+		Entry("missing article", "das Haus", "Haus", grading.VerdictAlmost, grading.MistakeArticle),
+		Entry("lower case noun", "das Haus", "das haus", grading.VerdictAlmost, grading.MistakeCapitalization),
+		Entry("typo", "die Katze", "die Kaze", grading.VerdictAlmost, grading.MistakeSpelling),
+		Entry("missing umlaut", "die Häuser", "die Hauser", grading.VerdictAlmost, grading.MistakeSpelling),
+		Entry("typo in lower case", "die Katze", "die kaze", grading.VerdictAlmost, grading.MistakeCapitalization, grading.MistakeSpelling),
+		Entry("too many typos", "die Katze", "die Kazt", grading.VerdictWrong, grading.MistakeSpelling),
+		Entry("another word", "der Hund", "die Katze", grading.VerdictWrong, grading.MistakeArticle, grading.MistakeSpelling),
+		Entry("without article", "house", "house", grading.VerdictCorrect),
+		Entry("article where none is expected", "house", "the house", grading.VerdictWrong, grading.MistakeSpelling),
+	)
+
+	It("tolerates as many typos as configured", func() {
+		Expect(grading.Grader{Tolerance: 0}.Grade("Katze", "Kaze").Verdict).To(Equal(grading.VerdictWrong))
+		Expect(grading.Grader{Tolerance: 2}.Grade("Katze", "Kazt").Verdict).To(Equal(grading.VerdictAlmost))
+	})
+
+	It("reports the distance and the normalized answers", func() {
+		result := grader.Grade("die Straße", " die  Strase")
+		Expect(result.Expected).To(Equal("die Straße"))
+		Expect(result.Answer).To(Equal("die Strase"))
+		Expect(result.Distance).To(Equal(1))
+	})
+})
+
+var _ = Describe("Diff", func() {
+	It("lists the letters to delete and insert", func() {
+		Expect(grading.Diff("Katze", "Kaze")).To(Equal([]grading.Edit{
+			{Op: grading.DiffEqual, Text: "Ka"},
+			{Op: grading.DiffDelete, Text: "t"},
+			{Op: grading.DiffEqual, Text: "ze"},
+		}))
+		Expect(grading.Diff("Häuser", "Hauser")).To(Equal([]grading.Edit{
+			{Op: grading.DiffEqual, Text: "H"},
+			{Op: grading.DiffDelete, Text: "ä"},
+			{Op: grading.DiffInsert, Text: "a"},
+			{Op: grading.DiffEqual, Text: "user"},
+		}))
+		Expect(grading.Diff("", "ab")).To(Equal([]grading.Edit{{Op: grading.DiffInsert, Text: "ab"}}))
+	})
+
+	It("measures the Levenshtein distance", func() {
+		Expect(grading.Distance("kitten", "sitting")).To(Equal(3))
+		Expect(grading.Distance("Bär", "Bar")).To(Equal(1))
+	})
+})
@@ -0,0 +1,145 @@
+package metrics
+
+import (
+	"database/sql"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+
+	"github.com/souheilbenslama/free-genai-bootcamp-2025/lang-portal/backend-go/internal/models"
+)
+
+const namespace = "langportal"
+
+// Registry holds every metric exposed on /metrics.
+var Registry = prometheus.NewRegistry()
+
+var (
+	httpRequests = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "http_requests_total",
+		Help:      "HTTP requests by method, gin route and status code.",
+	}, []string{"method", "route", "status"})
+
+	httpDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Name:      "http_request_duration_seconds",
+		Help:      "HTTP request latency by method and gin route.",
+		Buckets:   prometheus.DefBuckets,
+	}, []string{"method", "route"})
+
+	dbQueryDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Name:      "db_query_duration_seconds",
+		Help:      "SQLite time spent per repository method.",
+		Buckets:   []float64{.0005, .001, .0025, .005, .01, .025, .05, .1, .25, .5, 1},
+	}, []string{"repository", "method"})
+
+	// SessionsStarted counts study sessions created since the process started.
+	SessionsStarted = prometheus.NewCounter(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "study_sessions_started_total",
+		Help:      "Study sessions started.",
+	})
+
+	// SessionsEnded counts study sessions by how they ended, completed or
+	// abandoned.
+	SessionsEnded = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "study_sessions_ended_total",
+		Help:      "Study sessions ended by final state.",
+	}, []string{"state"})
+
+	// ReviewsRecorded counts the reviews of every drill recorded by this
+	// process, by drill and result, correct or incorrect. Like the other
+	// counters it starts from zero when the process starts, so it measures
+	// traffic rather than the totals the dashboard reports.
+	ReviewsRecorded = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "word_reviews_total",
+		Help:      "Word reviews recorded since the process started, by drill and result.",
+	}, []string{"drill", "result"})
+
+	// WordsCreated counts words added through the API.
+	WordsCreated = prometheus.NewCounter(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "words_created_total",
+		Help:      "Words created.",
+	})
+)
+
+func init() {
+	Registry.MustRegister(
+		collectors.NewGoCollector(),
+		collectors.NewProcessCollector(collectors.ProcessCollectorOpts{}),
+		httpRequests,
+		httpDuration,
+		dbQueryDuration,
+		SessionsStarted,
+		SessionsEnded,
+		ReviewsRecorded,
+		WordsCreated,
+	)
+
+	// Expose both results of every drill from the start so alerts on ratios
+	// have data
+	for _, drill := range []string{models.DrillMeaning, models.DrillArticle, models.DrillPlural} {
+		ReviewsRecorded.WithLabelValues(drill, "correct")
+		ReviewsRecorded.WithLabelValues(drill, "incorrect")
+	}
+	SessionsEnded.WithLabelValues("completed")
+	SessionsEnded.WithLabelValues("abandoned")
+}
+
+// RegisterDB exposes the connection pool statistics of db.
+func RegisterDB(db *sql.DB, name string) error {
+	return Registry.Register(collectors.NewDBStatsCollector(db, name))
+}
+
+// Handler serves the registry in the Prometheus exposition format.
+func Handler() http.Handler {
+	return promhttp.HandlerFor(Registry, promhttp.HandlerOpts{Registry: Registry})
+}
+
+// Middleware records the count and latency of every request, labelled with
+// the gin route template rather than the raw path to keep cardinality low.
+func Middleware() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		start := time.Now()
+		c.Next()
+
+		route := c.FullPath()
+		if route == "" {
+			route = "unmatched"
+		}
+		method := c.Request.Method
+
+		httpRequests.WithLabelValues(method, route, strconv.Itoa(c.Writer.Status())).Inc()
+		httpDuration.WithLabelValues(method, route).Observe(time.Since(start).Seconds())
+	}
+}
+
+// ObserveQuery starts timing a repository method; call the returned function
+// when the method returns:
+//
+//	defer metrics.ObserveQuery("word", "GetWord")()
+func ObserveQuery(repository, method string) func() {
+	start := time.Now()
+	return func() {
+		dbQueryDuration.WithLabelValues(repository, method).Observe(time.Since(start).Seconds())
+	}
+}
+
+// RecordReview counts a review of drill by its result.
+func RecordReview(drill string, correct bool) {
+	result := "incorrect"
+	if correct {
+		result = "correct"
+	}
+	ReviewsRecorded.WithLabelValues(drill, result).Inc()
+}
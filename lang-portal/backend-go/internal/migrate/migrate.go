@@ -0,0 +1,132 @@
+package migrate
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"io/fs"
+	"sort"
+	"strings"
+	"time"
+)
+
+const createTableSQL = `
+	CREATE TABLE IF NOT EXISTS schema_migrations (
+		version TEXT PRIMARY KEY,
+		applied_at DATETIME NOT NULL
+	)
+`
+
+// Versions lists the *.sql files in fsys in the order they are applied.
+func Versions(fsys fs.FS) ([]string, error) {
+	names, err := fs.Glob(fsys, "*.sql")
+	if err != nil {
+		return nil, fmt.Errorf("error listing migrations: %w", err)
+	}
+	sort.Strings(names)
+
+	versions := make([]string, len(names))
+	for i, name := range names {
+		versions[i] = strings.TrimSuffix(name, ".sql")
+	}
+	return versions, nil
+}
+
+// Apply runs every migration in fsys that has not been recorded in
+// schema_migrations yet, each in its own transaction, and returns the
+// versions it applied.
+func Apply(ctx context.Context, db *sql.DB, fsys fs.FS) ([]string, error) {
+	if _, err := db.ExecContext(ctx, createTableSQL); err != nil {
+		return nil, fmt.Errorf("error creating schema_migrations: %w", err)
+	}
+
+	versions, err := Versions(fsys)
+	if err != nil {
+		return nil, err
+	}
+
+	var applied []string
+	for _, version := range versions {
+		var exists bool
+		err := db.QueryRowContext(ctx,
+			"SELECT EXISTS(SELECT 1 FROM schema_migrations WHERE version = ?)",
+			version,
+		).Scan(&exists)
+		if err != nil {
+			return applied, fmt.Errorf("error checking migration %s: %w", version, err)
+		}
+		if exists {
+			continue
+		}
+
+		if err := applyOne(ctx, db, fsys, version); err != nil {
+			return applied, err
+		}
+		applied = append(applied, version)
+	}
+
+	return applied, nil
+}
+
+func applyOne(ctx context.Context, db *sql.DB, fsys fs.FS, version string) error {
+	migrationSQL, err := fs.ReadFile(fsys, version+".sql")
+	if err != nil {
+		return fmt.Errorf("error reading migration %s: %w", version, err)
+	}
+
+	tx, err := db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("error beginning transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.ExecContext(ctx, string(migrationSQL)); err != nil {
+		return fmt.Errorf("error applying migration %s: %w", version, err)
+	}
+
+	if _, err := tx.ExecContext(ctx,
+		"INSERT INTO schema_migrations (version, applied_at) VALUES (?, ?)",
+		version,
+		time.Now(),
+	); err != nil {
+		return fmt.Errorf("error recording migration %s: %w", version, err)
+	}
+
+	return tx.Commit()
+}
+
+// Status reports the latest applied migration and the migrations in fsys
+// that have not been applied yet.
+func Status(ctx context.Context, db *sql.DB, fsys fs.FS) (current string, pending []string, err error) {
+	versions, err := Versions(fsys)
+	if err != nil {
+		return "", nil, err
+	}
+
+	rows, err := db.QueryContext(ctx, "SELECT version FROM schema_migrations ORDER BY version")
+	if err != nil {
+		return "", nil, fmt.Errorf("error reading schema_migrations: %w", err)
+	}
+	defer rows.Close()
+
+	applied := map[string]bool{}
+	for rows.Next() {
+		var version string
+		if err := rows.Scan(&version); err != nil {
+			return "", nil, fmt.Errorf("error scanning migration: %w", err)
+		}
+		applied[version] = true
+		current = version
+	}
+	if err := rows.Err(); err != nil {
+		return "", nil, fmt.Errorf("error reading schema_migrations: %w", err)
+	}
+
+	for _, version := range versions {
+		if !applied[version] {
+			pending = append(pending, version)
+		}
+	}
+
+	return current, pending, nil
+}
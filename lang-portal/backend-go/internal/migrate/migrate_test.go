@@ -0,0 +1,118 @@
+package migrate_test
+
+import (
+	"context"
+	"database/sql"
+	"io/fs"
+	"os"
+	"testing/fstest"
+	"time"
+
+	_ "github.com/mattn/go-sqlite3"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/souheilbenslama/free-genai-bootcamp-2025/lang-portal/backend-go/database"
+	"github.com/souheilbenslama/free-genai-bootcamp-2025/lang-portal/backend-go/internal/migrate"
+)
+
+// migrationsBefore returns the embedded migrations older than version.
+func migrationsBefore(version string) fs.FS {
+	versions, err := migrate.Versions(database.Migrations())
+	Expect(err).NotTo(HaveOccurred())
+
+	fsys := fstest.MapFS{}
+	for _, v := range versions {
+		if v >= version {
+			break
+		}
+		data, err := fs.ReadFile(database.Migrations(), v+".sql")
+		Expect(err).NotTo(HaveOccurred())
+		fsys[v+".sql"] = &fstest.MapFile{Data: data}
+	}
+	return fsys
+}
+
+var _ = Describe("Apply", func() {
+	var db *sql.DB
+
+	BeforeEach(func() {
+		tmpfile, err := os.CreateTemp("", "migrate-*.db")
+		Expect(err).NotTo(HaveOccurred())
+		DeferCleanup(os.Remove, tmpfile.Name())
+
+		db, err = sql.Open("sqlite3", tmpfile.Name())
+		Expect(err).NotTo(HaveOccurred())
+		DeferCleanup(db.Close)
+	})
+
+	It("applies every migration once", func() {
+		applied, err := migrate.Apply(context.Background(), db, database.Migrations())
+		Expect(err).NotTo(HaveOccurred())
+		Expect(applied).NotTo(BeEmpty())
+
+		applied, err = migrate.Apply(context.Background(), db, database.Migrations())
+		Expect(err).NotTo(HaveOccurred())
+		Expect(applied).To(BeEmpty())
+
+		_, pending, err := migrate.Status(context.Background(), db, database.Migrations())
+		Expect(err).NotTo(HaveOccurred())
+		Expect(pending).To(BeEmpty())
+	})
+
+	It("keeps the reviews recorded before attempts as first attempts", func() {
+		ctx := context.Background()
+		_, err := migrate.Apply(ctx, db, migrationsBefore("012_review_attempts"))
+		Expect(err).NotTo(HaveOccurred())
+
+		_, err = db.Exec(`
+			INSERT INTO words (id, german, english, parts) VALUES (1, 'Hund', 'dog', '{}'), (2, 'Katze', 'cat', '{}');
+			INSERT INTO groups (id, name) VALUES (1, 'Animals');
+			INSERT INTO study_sessions (id, group_id, created_at) VALUES (1, 1, CURRENT_TIMESTAMP);
+		`)
+		Expect(err).NotTo(HaveOccurred())
+
+		first := time.Date(2024, time.May, 1, 10, 0, 0, 0, time.UTC)
+		second := first.Add(time.Minute)
+		_, err = db.Exec(`
+			INSERT INTO word_review_items (word_id, study_session_id, correct, verdict, chosen_word_id, created_at)
+			VALUES (1, 1, 1, 'correct', 1, ?), (2, 1, 0, 'wrong', 1, ?)
+		`, first, second)
+		Expect(err).NotTo(HaveOccurred())
+
+		applied, err := migrate.Apply(ctx, db, database.Migrations())
+		Expect(err).NotTo(HaveOccurred())
+		Expect(applied).To(ContainElement("012_review_attempts"))
+
+		type review struct {
+			wordID, attempt, chosenWordID int
+			correct                       bool
+			verdict                       string
+			createdAt                     time.Time
+		}
+		rows, err := db.Query(`
+			SELECT word_id, attempt, correct, verdict, chosen_word_id, created_at
+			FROM word_review_items ORDER BY word_id
+		`)
+		Expect(err).NotTo(HaveOccurred())
+		defer rows.Close()
+		var reviews []review
+		for rows.Next() {
+			var r review
+			Expect(rows.Scan(&r.wordID, &r.attempt, &r.correct, &r.verdict, &r.chosenWordID, &r.createdAt)).To(Succeed())
+			reviews = append(reviews, r)
+		}
+		Expect(rows.Err()).NotTo(HaveOccurred())
+
+		Expect(reviews).To(HaveLen(2))
+		Expect(reviews[0]).To(Equal(review{wordID: 1, attempt: 1, chosenWordID: 1, correct: true, verdict: "correct", createdAt: first}))
+		Expect(reviews[1]).To(Equal(review{wordID: 2, attempt: 1, chosenWordID: 1, correct: false, verdict: "wrong", createdAt: second}))
+
+		// The log takes further attempts at the same word
+		_, err = db.Exec(`
+			INSERT INTO word_review_items (word_id, study_session_id, attempt, correct, verdict)
+			VALUES (1, 1, 2, 0, 'wrong')
+		`)
+		Expect(err).NotTo(HaveOccurred())
+	})
+})
@@ -16,13 +16,90 @@ type Group struct {
 	WordCount   int       `json:"word_count,omitempty"`
 	CreatedAt   time.Time `json:"created_at"`
 	UpdatedAt   time.Time `json:"updated_at"`
+	// Progress is only reported to callers allowed to read their progress.
+	Progress *GroupProgress `json:"progress,omitempty"`
 }
 
+type User struct {
+	ID           int       `json:"id"`
+	Email        string    `json:"email"`
+	Name         string    `json:"name"`
+	Role         string    `json:"role"`
+	Timezone     string    `json:"timezone"`
+	PasswordHash string    `json:"-"`
+	CreatedAt    time.Time `json:"created_at"`
+}
+
+// APIKey is an integration credential acting for UserID. The key itself is
+// only returned when it is created.
+type APIKey struct {
+	ID         int        `json:"id"`
+	Name       string     `json:"name"`
+	Prefix     string     `json:"prefix"`
+	Scopes     []string   `json:"scopes"`
+	UserID     int        `json:"user_id"`
+	ExpiresAt  *time.Time `json:"expires_at"`
+	LastUsedAt *time.Time `json:"last_used_at"`
+	RevokedAt  *time.Time `json:"revoked_at"`
+	CreatedAt  time.Time  `json:"created_at"`
+}
+
+// Study session states. A session starts active and ends completed when the
+// learner finishes it, or abandoned when it sits idle for too long.
+const (
+	SessionActive    = "active"
+	SessionCompleted = "completed"
+	SessionAbandoned = "abandoned"
+)
+
 type StudySession struct {
-	ID              int       `json:"id"`
-	GroupID         int       `json:"group_id"`
-	CreatedAt       time.Time `json:"created_at"`
-	StudyActivityID int       `json:"study_activity_id"`
+	ID              int        `json:"id"`
+	UserID          int        `json:"user_id"`
+	GroupID         int        `json:"group_id"`
+	CreatedAt       time.Time  `json:"created_at"`
+	StudyActivityID int        `json:"study_activity_id"`
+	State           string     `json:"state"`
+	LastActivityAt  time.Time  `json:"last_activity_at"`
+	EndedAt         *time.Time `json:"ended_at"`
+	// DurationSeconds is the time from the start of the session to its end,
+	// or to its latest activity while it is still active.
+	DurationSeconds int `json:"duration_seconds"`
+}
+
+// Strategies for choosing the next word of a study session. Every strategy
+// issues each word of the group once per session.
+const (
+	// StrategyShuffle picks at random.
+	StrategyShuffle = "shuffle"
+	// StrategyWeakest picks the word the user answers correctly least often.
+	StrategyWeakest = "weakest"
+	// StrategyDue picks the word the user has not reviewed for longest.
+	StrategyDue = "due"
+)
+
+// Drills decide what a study session asks about a word. Words are issued
+// by the reviews of their own drill, and only when they have the parts the
+// drill asks about.
+const (
+	// DrillMeaning asks for the meaning of any word.
+	DrillMeaning = "meaning"
+	// DrillArticle asks for the article of a noun.
+	DrillArticle = "article"
+	// DrillPlural asks for the plural of a noun.
+	DrillPlural = "plural"
+)
+
+// SessionItem is a word issued to a study session for review.
+type SessionItem struct {
+	ID             int       `json:"id"`
+	StudySessionID int       `json:"study_session_id"`
+	GroupID        int       `json:"group_id"`
+	Word           Word      `json:"word"`
+	Strategy       string    `json:"strategy"`
+	IssuedAt       time.Time `json:"issued_at"`
+	// Remaining counts the words of the group the session has yet to issue
+	// for the drill.
+	Remaining int `json:"remaining"`
 }
 
 type StudyActivity struct {
@@ -32,13 +109,110 @@ type StudyActivity struct {
 	CreatedAt       time.Time `json:"created_at"`
 }
 
+// Directions a word is asked in.
+const (
+	// DirectionGermanToEnglish shows the German word and asks for its
+	// meaning.
+	DirectionGermanToEnglish = "de_en"
+	// DirectionEnglishToGerman shows the meaning and asks for the German
+	// word.
+	DirectionEnglishToGerman = "en_de"
+)
+
+// WordReviewItem is one attempt at a word in a study session. Attempts are
+// numbered from 1 per word and session; the optional fields are unknown for
+// attempts recorded without them.
 type WordReviewItem struct {
+	ID             int       `json:"id"`
+	WordID         int       `json:"word_id"`
+	StudySessionID int       `json:"study_session_id"`
+	Attempt        int       `json:"attempt"`
+	Correct        bool      `json:"correct"`
+	CreatedAt      time.Time `json:"created_at"`
+	// ChosenWordID is the option picked in a multiple choice question.
+	ChosenWordID *int `json:"chosen_word_id,omitempty"`
+	// Verdict is correct, almost or wrong; only correct sets Correct.
+	Verdict    string  `json:"verdict"`
+	Answer     *string `json:"answer,omitempty"`
+	Expected   *string `json:"expected,omitempty"`
+	ResponseMS *int    `json:"response_ms,omitempty"`
+	Direction  *string `json:"direction,omitempty"`
+}
+
+// ArticleReview is an answer of the article drill, graded against the
+// article of the noun.
+type ArticleReview struct {
+	ID             int       `json:"id"`
+	WordID         int       `json:"word_id"`
+	StudySessionID int       `json:"study_session_id"`
+	Article        string    `json:"article"`
+	ChosenArticle  string    `json:"chosen_article"`
+	Correct        bool      `json:"correct"`
+	CreatedAt      time.Time `json:"created_at"`
+}
+
+// ArticleStats sums up the article drill answers for the nouns of one
+// article. ChosenInstead counts the wrong articles picked for them.
+type ArticleStats struct {
+	Article       string         `json:"article"`
+	Total         int            `json:"total"`
+	Correct       int            `json:"correct"`
+	Accuracy      float64        `json:"accuracy"`
+	ChosenInstead map[string]int `json:"chosen_instead"`
+}
+
+// NounStats sums up the article drill answers for one noun.
+type NounStats struct {
+	WordID   int     `json:"word_id"`
+	German   string  `json:"german"`
+	Article  string  `json:"article"`
+	Total    int     `json:"total"`
+	Correct  int     `json:"correct"`
+	Accuracy float64 `json:"accuracy"`
+}
+
+// ArticleBreakdown reports a learner's article drill accuracy. Nouns are
+// listed weakest first and MostConfused is the article answered wrong most
+// often relative to how often it was asked, or nil without mistakes.
+type ArticleBreakdown struct {
+	Articles     []ArticleStats `json:"articles"`
+	Nouns        []NounStats    `json:"nouns"`
+	MostConfused *string        `json:"most_confused"`
+}
+
+// PluralReview is an answer of the plural drill. Pattern is the way the
+// plural of the noun is formed and AnswerPattern the way the answer formed
+// it.
+type PluralReview struct {
+	ID             int       `json:"id"`
 	WordID         int       `json:"word_id"`
 	StudySessionID int       `json:"study_session_id"`
+	Answer         string    `json:"answer"`
+	Plural         string    `json:"plural"`
+	Pattern        string    `json:"pattern"`
+	AnswerPattern  string    `json:"answer_pattern"`
 	Correct        bool      `json:"correct"`
 	CreatedAt      time.Time `json:"created_at"`
 }
 
+// PatternStats sums up the plural drill answers for the nouns of one plural
+// pattern. AppliedInstead counts the patterns wrong answers used.
+type PatternStats struct {
+	Pattern        string         `json:"pattern"`
+	Total          int            `json:"total"`
+	Correct        int            `json:"correct"`
+	Accuracy       float64        `json:"accuracy"`
+	AppliedInstead map[string]int `json:"applied_instead"`
+}
+
+// PluralBreakdown reports a learner's plural drill accuracy per pattern.
+// WeakestPattern is the pattern answered wrong most often relative to how
+// often it was asked, or nil without mistakes.
+type PluralBreakdown struct {
+	Patterns       []PatternStats `json:"patterns"`
+	WeakestPattern *string        `json:"weakest_pattern"`
+}
+
 type DashboardStats struct {
 	SuccessRate         float64 `json:"success_rate"`
 	TotalStudySessions int     `json:"total_study_sessions"`
@@ -55,3 +229,90 @@ type StudyProgress struct {
 	TotalAvailableWords  int     `json:"total_available_words"`
 	MasteryPercentage    float64 `json:"mastery_percentage"`
 }
+
+// StatsFilter narrows statistics down to the study sessions of a group and
+// the reviews of a drill. The zero value keeps everything.
+type StatsFilter struct {
+	GroupID int
+	Drill   string
+}
+
+// SeriesPoint is the value of a metric over the bucket starting on Start.
+// Value is nil for the accuracy of a bucket without reviews.
+type SeriesPoint struct {
+	Start string   `json:"start"`
+	Value *float64 `json:"value"`
+}
+
+// Series is a metric over the buckets starting on From through the one
+// holding To. Total is the metric over the whole series.
+type Series struct {
+	From   string        `json:"from"`
+	To     string        `json:"to"`
+	Points []SeriesPoint `json:"points"`
+	Total  *float64      `json:"total"`
+}
+
+// TimeSeries is a metric of a learner over time. Previous covers as many
+// buckets right before, when asked for, and Change is the change of the
+// total from it in percent, or nil without a previous total to compare with.
+type TimeSeries struct {
+	Metric   string  `json:"metric"`
+	Bucket   string  `json:"bucket"`
+	GroupID  *int    `json:"group_id"`
+	Drill    *string `json:"drill"`
+	Series
+	Previous *Series  `json:"previous"`
+	Change   *float64 `json:"change"`
+}
+
+// CalendarDay is the study of a learner on one day of their time zone.
+// Sessions and their minutes count for the day they started. Level grades
+// the reviews from 0 for none to 4 for the busiest days of the year.
+type CalendarDay struct {
+	Date     string  `json:"date"`
+	Reviews  int     `json:"reviews"`
+	Sessions int     `json:"sessions"`
+	Minutes  float64 `json:"minutes"`
+	Level    int     `json:"level"`
+}
+
+// Calendar lays out a year of study of a learner day by day.
+type Calendar struct {
+	Year     int           `json:"year"`
+	Timezone string        `json:"timezone"`
+	Days     []CalendarDay `json:"days"`
+}
+
+// Streak counts the days in a row a learner studied, in their time zone.
+// Today counts once studied but does not break the streak before it ends.
+// Freezes is the number of freeze tokens held, FrozenDays the days they
+// were spent on. Days are in the layout 2006-01-02.
+type Streak struct {
+	Current      int      `json:"current"`
+	Longest      int      `json:"longest"`
+	StudiedToday bool     `json:"studied_today"`
+	LastStudyDay *string  `json:"last_study_day"`
+	Freezes      int      `json:"freezes"`
+	FrozenDays   []string `json:"frozen_days"`
+	Timezone     string   `json:"timezone"`
+	Today        string   `json:"today"`
+}
+
+// GroupProgress sums up how far a learner got with the words of a group.
+// A word is mastered once at least 80% of its reviews were correct, in any
+// session, and learning before. Accuracy covers every review of the words
+// of the group and is nil without any. EstimatedMinutes is the time the
+// correct reviews still needed for mastering every word take at the
+// learner's pace.
+type GroupProgress struct {
+	GroupID          int        `json:"group_id"`
+	TotalWords       int        `json:"total_words"`
+	NeverSeen        int        `json:"never_seen"`
+	Learning         int        `json:"learning"`
+	Mastered         int        `json:"mastered"`
+	Completed        bool       `json:"completed"`
+	Accuracy         *float64   `json:"accuracy"`
+	LastStudiedAt    *time.Time `json:"last_studied_at"`
+	EstimatedMinutes float64    `json:"estimated_minutes"`
+}
@@ -0,0 +1,138 @@
+package pagination
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/url"
+	"strconv"
+)
+
+const (
+	// DefaultLimit is the page size used when the client does not ask for one.
+	DefaultLimit = 100
+	// MaxLimit is the largest page size any list endpoint will serve.
+	MaxLimit = 500
+)
+
+var (
+	ErrInvalidLimit  = fmt.Errorf("limit must be an integer between 1 and %d", MaxLimit)
+	ErrInvalidCursor = errors.New("invalid cursor")
+)
+
+// Params is a keyset page request shared by every list endpoint.
+// AfterID is the last id the client has seen; zero means the first page.
+type Params struct {
+	Limit   int
+	AfterID int
+}
+
+// Page is the pagination block returned next to the items of a list response.
+type Page struct {
+	Limit      int    `json:"limit"`
+	TotalItems int    `json:"total_items"`
+	HasMore    bool   `json:"has_more"`
+	NextCursor string `json:"next_cursor,omitempty"`
+}
+
+type cursor struct {
+	AfterID int `json:"after_id"`
+}
+
+// Parse reads the limit and cursor query parameters.
+func Parse(query url.Values) (Params, error) {
+	params := Params{Limit: DefaultLimit}
+
+	if raw := query.Get("limit"); raw != "" {
+		limit, err := strconv.Atoi(raw)
+		if err != nil || limit < 1 || limit > MaxLimit {
+			return Params{}, ErrInvalidLimit
+		}
+		params.Limit = limit
+	}
+
+	if raw := query.Get("cursor"); raw != "" {
+		afterID, err := DecodeCursor(raw)
+		if err != nil {
+			return Params{}, err
+		}
+		params.AfterID = afterID
+	}
+
+	return params, nil
+}
+
+// EncodeCursor builds the opaque cursor pointing just past afterID.
+func EncodeCursor(afterID int) string {
+	data, _ := json.Marshal(cursor{AfterID: afterID})
+	return base64.RawURLEncoding.EncodeToString(data)
+}
+
+// DecodeCursor reverses EncodeCursor.
+func DecodeCursor(raw string) (int, error) {
+	data, err := base64.RawURLEncoding.DecodeString(raw)
+	if err != nil {
+		return 0, ErrInvalidCursor
+	}
+
+	var c cursor
+	if err := json.Unmarshal(data, &c); err != nil || c.AfterID < 1 {
+		return 0, ErrInvalidCursor
+	}
+
+	return c.AfterID, nil
+}
+
+// NewPage describes the page that was served. Repositories fetch one row more
+// than the limit, so fetched > limit means another page exists; lastID is the
+// id of the last item actually returned.
+func NewPage(params Params, total, fetched, lastID int) Page {
+	page := Page{
+		Limit:      params.Limit,
+		TotalItems: total,
+		HasMore:    fetched > params.Limit,
+	}
+	if page.HasMore {
+		page.NextCursor = EncodeCursor(lastID)
+	}
+	return page
+}
+
+// Trim serves a page of items fetched with one row more than the limit: it
+// drops the extra row and describes the page, using idOf for the cursor.
+func Trim[T any](items []T, params Params, total int, idOf func(T) int) ([]T, Page) {
+	fetched := len(items)
+	if fetched > params.Limit {
+		items = items[:params.Limit]
+	}
+
+	lastID := 0
+	if len(items) > 0 {
+		lastID = idOf(items[len(items)-1])
+	}
+
+	return items, NewPage(params, total, fetched, lastID)
+}
+
+// LinkHeader renders an RFC 8288 Link header with first and next relations
+// for the request URL.
+func LinkHeader(u *url.URL, page Page) string {
+	link := func(rel, cursor string) string {
+		next := *u
+		query := next.Query()
+		query.Set("limit", strconv.Itoa(page.Limit))
+		query.Del("cursor")
+		if cursor != "" {
+			query.Set("cursor", cursor)
+		}
+		next.RawQuery = query.Encode()
+		return fmt.Sprintf("<%s>; rel=%q", next.RequestURI(), rel)
+	}
+
+	header := link("first", "")
+	if page.NextCursor != "" {
+		header += ", " + link("next", page.NextCursor)
+	}
+	return header
+}
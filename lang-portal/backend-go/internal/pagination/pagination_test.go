@@ -0,0 +1,103 @@
+package pagination_test
+
+import (
+	"encoding/base64"
+	"net/url"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/souheilbenslama/free-genai-bootcamp-2025/lang-portal/backend-go/internal/pagination"
+)
+
+var _ = Describe("Parse", func() {
+	parse := func(query string) (pagination.Params, error) {
+		values, err := url.ParseQuery(query)
+		Expect(err).NotTo(HaveOccurred())
+		return pagination.Parse(values)
+	}
+
+	It("serves the first page of the default size without parameters", func() {
+		params, err := parse("")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(params).To(Equal(pagination.Params{Limit: pagination.DefaultLimit}))
+	})
+
+	DescribeTable("bounds the limit",
+		func(limit string, expected int) {
+			params, err := parse("limit=" + limit)
+			if expected == 0 {
+				Expect(err).To(MatchError(pagination.ErrInvalidLimit))
+				return
+			}
+			Expect(err).NotTo(HaveOccurred())
+			Expect(params.Limit).To(Equal(expected))
+		},
+		Entry("zero", "0", 0),
+		Entry("one", "1", 1),
+		Entry("the largest page", "500", 500),
+		Entry("past the largest page", "501", 0),
+		Entry("not a number", "ten", 0),
+	)
+
+	It("continues after the id of a cursor", func() {
+		params, err := parse("limit=10&cursor=" + pagination.EncodeCursor(42))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(params).To(Equal(pagination.Params{Limit: 10, AfterID: 42}))
+	})
+
+	DescribeTable("rejects cursors it did not issue",
+		func(cursor string) {
+			_, err := parse(url.Values{"cursor": {cursor}}.Encode())
+			Expect(err).To(MatchError(pagination.ErrInvalidCursor))
+		},
+		Entry("not base64", "not a cursor!"),
+		Entry("not JSON", base64.RawURLEncoding.EncodeToString([]byte("after 42"))),
+		Entry("an id of the wrong type", base64.RawURLEncoding.EncodeToString([]byte(`{"after_id":"42"}`))),
+		Entry("no id", base64.RawURLEncoding.EncodeToString([]byte(`{}`))),
+		Entry("an id of zero", base64.RawURLEncoding.EncodeToString([]byte(`{"after_id":0}`))),
+		Entry("a negative id", base64.RawURLEncoding.EncodeToString([]byte(`{"after_id":-1}`))),
+		Entry("padding", pagination.EncodeCursor(42)+"="),
+	)
+})
+
+var _ = Describe("Trim", func() {
+	id := func(i int) int { return i }
+	params := pagination.Params{Limit: 2}
+
+	It("drops the extra row and points the cursor at the last item served", func() {
+		items, page := pagination.Trim([]int{3, 5, 8}, params, 10, id)
+		Expect(items).To(Equal([]int{3, 5}))
+		Expect(page).To(Equal(pagination.Page{Limit: 2, TotalItems: 10, HasMore: true, NextCursor: pagination.EncodeCursor(5)}))
+	})
+
+	It("serves the last page without a cursor", func() {
+		items, page := pagination.Trim([]int{3, 5}, params, 2, id)
+		Expect(items).To(Equal([]int{3, 5}))
+		Expect(page).To(Equal(pagination.Page{Limit: 2, TotalItems: 2}))
+	})
+
+	It("serves an empty page", func() {
+		items, page := pagination.Trim([]int{}, params, 0, id)
+		Expect(items).To(BeEmpty())
+		Expect(page).To(Equal(pagination.Page{Limit: 2}))
+	})
+})
+
+var _ = Describe("LinkHeader", func() {
+	It("links the first and next pages and keeps the other parameters", func() {
+		u, err := url.Parse("/api/words?sort_by=german&order=desc&limit=2&cursor=" + pagination.EncodeCursor(3))
+		Expect(err).NotTo(HaveOccurred())
+
+		header := pagination.LinkHeader(u, pagination.Page{Limit: 2, HasMore: true, NextCursor: pagination.EncodeCursor(5)})
+		Expect(header).To(Equal(`</api/words?limit=2&order=desc&sort_by=german>; rel="first", ` +
+			`</api/words?cursor=` + pagination.EncodeCursor(5) + `&limit=2&order=desc&sort_by=german>; rel="next"`))
+	})
+
+	It("only links the first page on the last one", func() {
+		u, err := url.Parse("/api/groups?limit=2&cursor=" + pagination.EncodeCursor(3))
+		Expect(err).NotTo(HaveOccurred())
+
+		Expect(pagination.LinkHeader(u, pagination.Page{Limit: 2})).To(Equal(`</api/groups?limit=2>; rel="first"`))
+	})
+})
@@ -0,0 +1,87 @@
+package quiz
+
+import (
+	"math/rand/v2"
+	"strings"
+
+	"github.com/souheilbenslama/free-genai-bootcamp-2025/lang-portal/backend-go/internal/models"
+)
+
+// ChoiceOptions is how many options a multiple choice question offers when
+// the pool has enough words.
+const ChoiceOptions = 4
+
+// MultipleChoice asks for the English meaning of answer. The distractors are
+// drawn from pool, normally the words of the session's group: words with
+// the same article (or, for words without one, other words without one)
+// first, so the grammar does not give the answer away, then the rest.
+// Synonyms of the answer are never offered, nor two options with the same
+// text. Options are shuffled with rng, or the global source when rng is nil.
+func MultipleChoice(answer models.Word, pool []models.Word, rng *rand.Rand) []Option {
+	shuffle := rand.Shuffle
+	if rng != nil {
+		shuffle = rng.Shuffle
+	}
+
+	article := ParseParts(answer).Article
+	taken := meanings(answer.English)
+
+	var alike, other []models.Word
+	for _, w := range pool {
+		if w.ID == answer.ID || strings.EqualFold(w.German, answer.German) {
+			continue
+		}
+		if ParseParts(w).Article == article {
+			alike = append(alike, w)
+		} else {
+			other = append(other, w)
+		}
+	}
+	shuffle(len(alike), func(i, j int) { alike[i], alike[j] = alike[j], alike[i] })
+	shuffle(len(other), func(i, j int) { other[i], other[j] = other[j], other[i] })
+
+	options := []Option{{WordID: answer.ID, Text: answer.English}}
+	for _, w := range append(alike, other...) {
+		if len(options) == ChoiceOptions {
+			break
+		}
+		m := meanings(w.English)
+		if overlaps(taken, m) {
+			continue
+		}
+		for meaning := range m {
+			taken[meaning] = true
+		}
+		options = append(options, Option{WordID: w.ID, Text: w.English})
+	}
+
+	shuffle(len(options), func(i, j int) { options[i], options[j] = options[j], options[i] })
+	return options
+}
+
+// meanings splits an English gloss like "the house, home; building" into its
+// meanings, lower-cased and without leading articles or "to", so that
+// "to run" and "run" are recognised as the same meaning.
+func meanings(gloss string) map[string]bool {
+	set := map[string]bool{}
+	for _, meaning := range strings.FieldsFunc(gloss, func(r rune) bool { return r == ',' || r == ';' || r == '/' }) {
+		meaning = strings.ToLower(strings.TrimSpace(meaning))
+		for _, prefix := range []string{"the ", "a ", "an ", "to "} {
+			meaning = strings.TrimPrefix(meaning, prefix)
+		}
+		meaning = strings.Join(strings.Fields(meaning), " ")
+		if meaning != "" {
+			set[meaning] = true
+		}
+	}
+	return set
+}
+
+func overlaps(a, b map[string]bool) bool {
+	for meaning := range b {
+		if a[meaning] {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,91 @@
+package quiz_test
+
+import (
+	"math/rand/v2"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/souheilbenslama/free-genai-bootcamp-2025/lang-portal/backend-go/internal/models"
+	"github.com/souheilbenslama/free-genai-bootcamp-2025/lang-portal/backend-go/internal/quiz"
+)
+
+func noun(id int, german, english, article string) models.Word {
+	return models.Word{ID: id, German: german, English: english, Parts: `{"article":"` + article + `"}`}
+}
+
+func optionIDs(options []quiz.Option) []int {
+	ids := make([]int, len(options))
+	for i, option := range options {
+		ids[i] = option.WordID
+	}
+	return ids
+}
+
+var _ = Describe("MultipleChoice", func() {
+	var rng *rand.Rand
+
+	BeforeEach(func() {
+		rng = rand.New(rand.NewPCG(1, 2))
+	})
+
+	haus := noun(1, "Haus", "house", "das")
+
+	It("offers the answer among distractors of the same article first", func() {
+		pool := []models.Word{
+			haus,
+			noun(2, "Buch", "book", "das"),
+			noun(3, "Auto", "car", "das"),
+			noun(4, "Kind", "child", "das"),
+			noun(5, "Katze", "cat", "die"),
+			noun(6, "Hund", "dog", "der"),
+		}
+
+		for range 20 {
+			options := quiz.MultipleChoice(haus, pool, rng)
+			Expect(options).To(HaveLen(quiz.ChoiceOptions))
+			Expect(optionIDs(options)).To(ConsistOf(1, 2, 3, 4))
+		}
+	})
+
+	It("fills up with other words of the pool", func() {
+		pool := []models.Word{haus, noun(2, "Buch", "book", "das"), noun(5, "Katze", "cat", "die"), noun(6, "Hund", "dog", "der")}
+
+		options := quiz.MultipleChoice(haus, pool, rng)
+		Expect(optionIDs(options)).To(ConsistOf(1, 2, 5, 6))
+		Expect(options).To(ContainElement(quiz.Option{WordID: 1, Text: "house"}))
+	})
+
+	It("never offers synonyms of the answer", func() {
+		pool := []models.Word{
+			haus,
+			noun(2, "Gebäude", "building, the house", "das"),
+			noun(3, "Heim", "House", "das"),
+			noun(4, "Buch", "book", "das"),
+		}
+
+		options := quiz.MultipleChoice(haus, pool, rng)
+		Expect(optionIDs(options)).To(ConsistOf(1, 4))
+	})
+
+	It("does not offer two options with the same meaning", func() {
+		verb := func(id int, german, english string) models.Word {
+			return models.Word{ID: id, German: german, English: english, Parts: "{}"}
+		}
+		laufen := verb(1, "laufen", "to run")
+		pool := []models.Word{laufen, verb(2, "gehen", "to go"), verb(3, "fahren", "go"), verb(4, "essen", "to eat")}
+
+		options := quiz.MultipleChoice(laufen, pool, rng)
+		Expect(options).To(HaveLen(3))
+		Expect(optionIDs(options)).To(ContainElements(1, 4))
+	})
+
+	It("asks with the answer alone when the pool has nothing else", func() {
+		Expect(quiz.MultipleChoice(haus, []models.Word{haus}, rng)).To(Equal([]quiz.Option{{WordID: 1, Text: "house"}}))
+	})
+
+	It("reads the article from malformed parts as empty", func() {
+		Expect(quiz.ParseParts(models.Word{Parts: "not json"})).To(Equal(quiz.Parts{}))
+		Expect(quiz.ParseParts(noun(1, "Haus", "house", " Das "))).To(Equal(quiz.Parts{Article: "das"}))
+	})
+})
@@ -0,0 +1,67 @@
+package quiz
+
+import "strings"
+
+// Plural patterns, the ways German nouns form their plural. An umlaut on the
+// stem does not change the pattern, so Haus, Häuser is an -er plural and
+// Vater, Väter a zero plural.
+const (
+	PluralE    = "e"
+	PluralEr   = "er"
+	PluralEn   = "en" // -n and -en, including -nen
+	PluralS    = "s"
+	PluralZero = "zero"
+	// PluralOther covers irregular plurals like Museum, Museen.
+	PluralOther = "other"
+)
+
+// PluralPatterns lists the plural patterns.
+var PluralPatterns = []string{PluralE, PluralEr, PluralEn, PluralS, PluralZero, PluralOther}
+
+var umlauts = strings.NewReplacer("ä", "a", "ö", "o", "ü", "u")
+
+// PluralPattern classifies how plural is formed from singular.
+func PluralPattern(singular, plural string) string {
+	stem := umlauts.Replace(strings.ToLower(strings.TrimSpace(singular)))
+	suffix, ok := strings.CutPrefix(umlauts.Replace(strings.ToLower(strings.TrimSpace(plural))), stem)
+	if !ok {
+		return PluralOther
+	}
+
+	switch suffix {
+	case "":
+		return PluralZero
+	case "e":
+		return PluralE
+	case "er":
+		return PluralEr
+	case "n", "en", "nen":
+		return PluralEn
+	case "s":
+		return PluralS
+	}
+	return PluralOther
+}
+
+// PluralGrade is the verdict on a typed plural. Pattern is the pattern of
+// the expected plural, Applied the one the answer used.
+type PluralGrade struct {
+	Correct bool
+	Pattern string
+	Applied string
+}
+
+// GradePlural grades answer as the plural of singular. The plural article
+// is always die, so an answer may start with it.
+func GradePlural(singular, plural, answer string) PluralGrade {
+	answer = strings.TrimSpace(answer)
+	if article, rest, ok := strings.Cut(answer, " "); ok && strings.EqualFold(article, "die") {
+		answer = strings.TrimSpace(rest)
+	}
+
+	return PluralGrade{
+		Correct: answer == strings.TrimSpace(plural),
+		Pattern: PluralPattern(singular, plural),
+		Applied: PluralPattern(singular, answer),
+	}
+}
@@ -0,0 +1,46 @@
+package quiz_test
+
+import (
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/souheilbenslama/free-genai-bootcamp-2025/lang-portal/backend-go/internal/quiz"
+)
+
+var _ = DescribeTable("PluralPattern",
+	func(singular, plural, pattern string) {
+		Expect(quiz.PluralPattern(singular, plural)).To(Equal(pattern))
+	},
+	Entry("-e", "Hund", "Hunde", quiz.PluralE),
+	Entry("-e with umlaut", "Hand", "Hände", quiz.PluralE),
+	Entry("-er", "Kind", "Kinder", quiz.PluralEr),
+	Entry("-er with umlaut", "Haus", "Häuser", quiz.PluralEr),
+	Entry("-n", "Katze", "Katzen", quiz.PluralEn),
+	Entry("-en", "Frau", "Frauen", quiz.PluralEn),
+	Entry("-nen", "Lehrerin", "Lehrerinnen", quiz.PluralEn),
+	Entry("-s", "Auto", "Autos", quiz.PluralS),
+	Entry("zero", "Lehrer", "Lehrer", quiz.PluralZero),
+	Entry("zero with umlaut", "Vater", "Väter", quiz.PluralZero),
+	Entry("umlaut in the singular", "Bär", "Bären", quiz.PluralEn),
+	Entry("irregular", "Museum", "Museen", quiz.PluralOther),
+)
+
+var _ = Describe("GradePlural", func() {
+	It("accepts the plural with or without its article", func() {
+		Expect(quiz.GradePlural("Haus", "Häuser", "Häuser")).To(Equal(quiz.PluralGrade{Correct: true, Pattern: quiz.PluralEr, Applied: quiz.PluralEr}))
+		Expect(quiz.GradePlural("Haus", "Häuser", " die Häuser ").Correct).To(BeTrue())
+	})
+
+	It("reports the pattern a wrong answer applied", func() {
+		grade := quiz.GradePlural("Haus", "Häuser", "Hause")
+		Expect(grade.Correct).To(BeFalse())
+		Expect(grade.Pattern).To(Equal(quiz.PluralEr))
+		Expect(grade.Applied).To(Equal(quiz.PluralE))
+	})
+
+	It("does not accept a missing umlaut", func() {
+		grade := quiz.GradePlural("Haus", "Häuser", "Hauser")
+		Expect(grade.Correct).To(BeFalse())
+		Expect(grade.Applied).To(Equal(quiz.PluralEr))
+	})
+})
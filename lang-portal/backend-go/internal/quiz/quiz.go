@@ -0,0 +1,84 @@
+// Package quiz builds the questions study activities ask about the words of a
+// session.
+package quiz
+
+import (
+	"encoding/json"
+	"strings"
+
+	"github.com/souheilbenslama/free-genai-bootcamp-2025/lang-portal/backend-go/internal/models"
+)
+
+// Question types.
+const (
+	// TypeMultipleChoice asks for the meaning of a German word among options.
+	TypeMultipleChoice = "mc"
+	// TypeArticle asks for the article of a German noun.
+	TypeArticle = "article"
+	// TypePlural asks for the plural of a German noun, given with its article.
+	TypePlural = "plural"
+)
+
+// Types lists the supported question types.
+var Types = []string{TypeMultipleChoice, TypeArticle, TypePlural}
+
+// Drills maps each question type to the drill its answers are recorded for.
+var Drills = map[string]string{
+	TypeMultipleChoice: models.DrillMeaning,
+	TypeArticle:        models.DrillArticle,
+	TypePlural:         models.DrillPlural,
+}
+
+// Articles are the articles an article question offers.
+var Articles = []string{"der", "die", "das"}
+
+// Question is asked about the word of a session item. Reviews answering it
+// are posted for WordID.
+type Question struct {
+	Type           string   `json:"type"`
+	ItemID         int      `json:"item_id"`
+	StudySessionID int      `json:"study_session_id"`
+	WordID         int      `json:"word_id"`
+	Prompt         string   `json:"prompt"`
+	Options        []Option `json:"options,omitempty"`
+	// Articles are offered by article questions instead of options.
+	Articles []string `json:"articles,omitempty"`
+	// Remaining counts the words of the group the session has yet to issue
+	// for the drill of the question.
+	Remaining int `json:"remaining"`
+}
+
+// Option is one answer offered by a multiple choice question, identified by
+// the word it is the meaning of.
+type Option struct {
+	WordID int    `json:"word_id"`
+	Text   string `json:"text"`
+}
+
+// Parts are the grammatical parts stored as JSON in models.Word.Parts.
+type Parts struct {
+	Article string `json:"article"`
+	Plural  string `json:"plural"`
+}
+
+// ParseParts reads the parts of w. Missing or malformed parts read as zero
+// Parts.
+func ParseParts(w models.Word) Parts {
+	var parts Parts
+	_ = json.Unmarshal([]byte(w.Parts), &parts)
+	parts.Article = strings.ToLower(strings.TrimSpace(parts.Article))
+	parts.Plural = strings.TrimSpace(parts.Plural)
+	return parts
+}
+
+// Expected is the answer to w asked in direction: the meaning, or the German
+// word with its article for nouns.
+func Expected(w models.Word, direction string) string {
+	if direction == models.DirectionGermanToEnglish {
+		return w.English
+	}
+	if article := ParseParts(w).Article; article != "" {
+		return article + " " + w.German
+	}
+	return w.German
+}
@@ -0,0 +1,161 @@
+// Package ratelimit implements in-memory token bucket rate limits.
+package ratelimit
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Policy allows Requests per Period for each caller. A caller may spend the
+// whole allowance at once; it then refills evenly over the period.
+type Policy struct {
+	Requests int
+	Period   time.Duration
+}
+
+// ParsePolicy reads a policy written as "<requests>/<period>", for example
+// "60/1m". "off" and "0" disable the limit and return the zero Policy.
+func ParsePolicy(s string) (Policy, error) {
+	s = strings.TrimSpace(s)
+	if s == "off" || s == "0" {
+		return Policy{}, nil
+	}
+
+	requests, period, ok := strings.Cut(s, "/")
+	if !ok {
+		return Policy{}, fmt.Errorf("rate limit %q must look like 60/1m", s)
+	}
+	n, err := strconv.Atoi(requests)
+	if err != nil || n <= 0 {
+		return Policy{}, fmt.Errorf("rate limit %q must allow a positive number of requests", s)
+	}
+	d, err := time.ParseDuration(period)
+	if err != nil || d <= 0 {
+		return Policy{}, fmt.Errorf("rate limit %q must have a positive period", s)
+	}
+	return Policy{Requests: n, Period: d}, nil
+}
+
+// Enabled reports whether the policy limits anything.
+func (p Policy) Enabled() bool {
+	return p.Requests > 0 && p.Period > 0
+}
+
+func (p Policy) String() string {
+	if !p.Enabled() {
+		return "off"
+	}
+	return fmt.Sprintf("%d/%s", p.Requests, p.Period)
+}
+
+// rate returns how many tokens refill per second.
+func (p Policy) rate() float64 {
+	return float64(p.Requests) / p.Period.Seconds()
+}
+
+// Decision is the outcome of one request against a policy.
+type Decision struct {
+	Allowed bool
+	// Limit is the size of the bucket and Remaining the whole tokens left.
+	Limit     int
+	Remaining int
+	// Reset is how long until the bucket is full again.
+	Reset time.Duration
+	// RetryAfter is how long until the next request is allowed; zero when
+	// this one was.
+	RetryAfter time.Duration
+}
+
+type bucket struct {
+	tokens float64
+	last   time.Time
+}
+
+// Limiter keeps one bucket per caller key. It is safe for concurrent use.
+type Limiter struct {
+	Name   string
+	policy Policy
+	now    func() time.Time
+
+	mu        sync.Mutex
+	buckets   map[string]*bucket
+	lastSweep time.Time
+}
+
+// New returns a limiter enforcing policy, or nil when the policy is disabled.
+// A nil *Limiter allows every request.
+func New(name string, policy Policy) *Limiter {
+	if !policy.Enabled() {
+		return nil
+	}
+	return &Limiter{Name: name, policy: policy, now: time.Now, buckets: map[string]*bucket{}}
+}
+
+// WithClock makes the limiter read the time from now. It is meant for tests.
+func (l *Limiter) WithClock(now func() time.Time) *Limiter {
+	l.now = now
+	l.lastSweep = time.Time{}
+	return l
+}
+
+// Policy returns the policy the limiter enforces.
+func (l *Limiter) Policy() Policy {
+	return l.policy
+}
+
+// Allow takes a token from the bucket of key if one is left.
+func (l *Limiter) Allow(key string) Decision {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	now := l.now()
+	l.sweep(now)
+
+	capacity := float64(l.policy.Requests)
+	rate := l.policy.rate()
+
+	b, ok := l.buckets[key]
+	if !ok {
+		b = &bucket{tokens: capacity, last: now}
+		l.buckets[key] = b
+	}
+	if elapsed := now.Sub(b.last).Seconds(); elapsed > 0 {
+		b.tokens = math.Min(capacity, b.tokens+elapsed*rate)
+	}
+	b.last = now
+
+	decision := Decision{Limit: l.policy.Requests}
+	if b.tokens >= 1 {
+		b.tokens--
+		decision.Allowed = true
+	} else {
+		decision.RetryAfter = seconds((1 - b.tokens) / rate)
+	}
+	decision.Remaining = int(b.tokens)
+	decision.Reset = seconds((capacity - b.tokens) / rate)
+	return decision
+}
+
+// sweep drops the buckets that have refilled completely, which are
+// indistinguishable from new ones, so idle callers do not pile up. It runs
+// at most once per period.
+func (l *Limiter) sweep(now time.Time) {
+	if now.Sub(l.lastSweep) < l.policy.Period {
+		return
+	}
+	l.lastSweep = now
+
+	for key, b := range l.buckets {
+		if now.Sub(b.last) >= l.policy.Period {
+			delete(l.buckets, key)
+		}
+	}
+}
+
+func seconds(s float64) time.Duration {
+	return time.Duration(s * float64(time.Second))
+}
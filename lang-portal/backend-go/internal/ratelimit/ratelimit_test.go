@@ -0,0 +1,87 @@
+package ratelimit_test
+
+import (
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"github.com/souheilbenslama/free-genai-bootcamp-2025/lang-portal/backend-go/internal/ratelimit"
+)
+
+var _ = Describe("Policy", func() {
+	It("parses requests per period", func() {
+		policy, err := ratelimit.ParsePolicy("60/1m")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(policy).To(Equal(ratelimit.Policy{Requests: 60, Period: time.Minute}))
+		Expect(policy.String()).To(Equal("60/1m0s"))
+	})
+
+	It("turns limits off", func() {
+		for _, value := range []string{"off", "0"} {
+			policy, err := ratelimit.ParsePolicy(value)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(policy.Enabled()).To(BeFalse())
+			Expect(ratelimit.New("test", policy)).To(BeNil())
+		}
+	})
+
+	It("rejects malformed policies", func() {
+		for _, value := range []string{"60", "-1/1m", "60/soon", "60/0s"} {
+			_, err := ratelimit.ParsePolicy(value)
+			Expect(err).To(HaveOccurred(), value)
+		}
+	})
+})
+
+var _ = Describe("Limiter", func() {
+	var (
+		now     time.Time
+		limiter *ratelimit.Limiter
+	)
+
+	BeforeEach(func() {
+		now = time.Date(2025, 3, 1, 12, 0, 0, 0, time.UTC)
+		limiter = ratelimit.New("test", ratelimit.Policy{Requests: 3, Period: 3 * time.Second}).
+			WithClock(func() time.Time { return now })
+	})
+
+	It("allows a burst of the whole allowance and then refuses", func() {
+		for remaining := 2; remaining >= 0; remaining-- {
+			decision := limiter.Allow("ip:1")
+			Expect(decision.Allowed).To(BeTrue())
+			Expect(decision.Limit).To(Equal(3))
+			Expect(decision.Remaining).To(Equal(remaining))
+		}
+
+		decision := limiter.Allow("ip:1")
+		Expect(decision.Allowed).To(BeFalse())
+		Expect(decision.RetryAfter).To(Equal(time.Second))
+		Expect(decision.Reset).To(Equal(3 * time.Second))
+	})
+
+	It("refills evenly over the period", func() {
+		for i := 0; i < 3; i++ {
+			limiter.Allow("ip:1")
+		}
+
+		now = now.Add(500 * time.Millisecond)
+		decision := limiter.Allow("ip:1")
+		Expect(decision.Allowed).To(BeFalse())
+		Expect(decision.RetryAfter).To(Equal(500 * time.Millisecond))
+
+		now = now.Add(500 * time.Millisecond)
+		Expect(limiter.Allow("ip:1").Allowed).To(BeTrue())
+		Expect(limiter.Allow("ip:1").Allowed).To(BeFalse())
+
+		now = now.Add(time.Hour)
+		Expect(limiter.Allow("ip:1").Remaining).To(Equal(2))
+	})
+
+	It("keeps a bucket per caller", func() {
+		for i := 0; i < 3; i++ {
+			limiter.Allow("user:1")
+		}
+		Expect(limiter.Allow("user:1").Allowed).To(BeFalse())
+		Expect(limiter.Allow("user:2").Allowed).To(BeTrue())
+	})
+})
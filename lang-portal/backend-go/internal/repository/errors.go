@@ -0,0 +1,32 @@
+package repository
+
+import "errors"
+
+var (
+	// ErrEmailTaken is returned when registering an email that already has an account.
+	ErrEmailTaken = errors.New("email already registered")
+	// ErrInvalidRefreshToken is returned for unknown, expired or revoked refresh tokens.
+	ErrInvalidRefreshToken = errors.New("invalid refresh token")
+	// ErrInvalidAPIKey is returned for unknown, expired or revoked API keys.
+	ErrInvalidAPIKey = errors.New("invalid, expired or revoked API key")
+	// ErrLastAdmin is returned when a change would leave no admin.
+	ErrLastAdmin = errors.New("cannot remove the last admin")
+	// ErrGroupNotFound is returned when starting a study session for a group
+	// that does not exist.
+	ErrGroupNotFound = errors.New("group not found")
+	// ErrSessionEnded is returned when changing a study session that is no
+	// longer active.
+	ErrSessionEnded = errors.New("study session has ended")
+	// ErrWordNotIssued is returned when reviewing a word the study session
+	// did not issue for the drill of the review.
+	ErrWordNotIssued = errors.New("word was not issued in this study session")
+	// ErrOptionNotOffered is returned when a review picks an option the
+	// question about the word did not offer.
+	ErrOptionNotOffered = errors.New("chosen word was not offered as an option")
+	// ErrNotANoun is returned when drilling the article of a word that has
+	// none.
+	ErrNotANoun = errors.New("word has no article")
+	// ErrNoPlural is returned when drilling the plural of a word that has
+	// none.
+	ErrNoPlural = errors.New("word has no plural")
+)
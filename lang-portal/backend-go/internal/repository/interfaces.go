@@ -2,29 +2,84 @@ package repository
 
 import (
 	"context"
+	"time"
 
 	"github.com/souheilbenslama/free-genai-bootcamp-2025/lang-portal/backend-go/internal/models"
+	"github.com/souheilbenslama/free-genai-bootcamp-2025/lang-portal/backend-go/internal/pagination"
+	"github.com/souheilbenslama/free-genai-bootcamp-2025/lang-portal/backend-go/internal/stats"
 )
 
 type WordRepository interface {
 	GetWord(ctx context.Context, id int) (*models.Word, error)
-	ListWords(ctx context.Context, offset, limit int) ([]models.Word, error)
+	ListWords(ctx context.Context, params pagination.Params) ([]models.Word, pagination.Page, error)
 	CreateWord(ctx context.Context, word *models.Word) error
 	UpdateWord(ctx context.Context, word *models.Word) error
 	DeleteWord(ctx context.Context, id int) error
 }
 
 type GroupRepository interface {
-	GetGroup(ctx context.Context, id int) (*models.Group, error)
-	ListGroups(ctx context.Context, offset, limit int) ([]models.Group, error)
+	GetByID(ctx context.Context, id int) (*models.Group, error)
+	ListGroups(ctx context.Context, params pagination.Params) ([]models.Group, pagination.Page, error)
+	GetGroupWords(ctx context.Context, groupID int) ([]models.Word, error)
 	CreateGroup(ctx context.Context, group *models.Group) error
 	UpdateGroup(ctx context.Context, group *models.Group) error
 	DeleteGroup(ctx context.Context, id int) error
+	AddWordToGroup(ctx context.Context, groupID, wordID int) error
+	RemoveWordFromGroup(ctx context.Context, groupID, wordID int) error
+	// GetProgress sums up the progress of a user with each of the groups.
+	GetProgress(ctx context.Context, userID int, groupIDs []int) (map[int]*models.GroupProgress, error)
 }
 
+// StudySessionRepository scopes every method to the sessions of one user.
 type StudySessionRepository interface {
-	GetStudySession(ctx context.Context, id int) (*models.StudySession, error)
-	ListStudySessions(ctx context.Context, offset, limit int) ([]models.StudySession, error)
-	CreateStudySession(ctx context.Context, session *models.StudySession) error
-	AddWordReview(ctx context.Context, review *models.WordReviewItem) error
+	ListStudySessions(ctx context.Context, userID int, params pagination.Params) ([]models.StudySession, pagination.Page, error)
+	GetLastStudySession(ctx context.Context, userID int) (*models.StudySession, error)
+	CreateStudySession(ctx context.Context, userID, groupID int) (*models.StudySession, error)
+	// FinishStudySession completes an active session and returns it.
+	FinishStudySession(ctx context.Context, userID, sessionID int) (*models.StudySession, error)
+	// NextItem issues the next word of an active session for a drill,
+	// chosen by strategy.
+	NextItem(ctx context.Context, userID, sessionID int, strategy, drill string) (*models.SessionItem, error)
+	// SetItemOptions stores the options of a multiple choice question about
+	// an issued word.
+	SetItemOptions(ctx context.Context, userID, itemID int, wordIDs []int) error
+	// IssuedWord returns a word issued to a session for a drill.
+	IssuedWord(ctx context.Context, userID, sessionID, wordID int, drill string) (*models.Word, error)
+	RecordWordReview(ctx context.Context, userID int, review *models.WordReviewItem) error
+	// RecordArticleReview grades and stores an answer of the article drill.
+	RecordArticleReview(ctx context.Context, userID int, review *models.ArticleReview) error
+	GetArticleBreakdown(ctx context.Context, userID int) (*models.ArticleBreakdown, error)
+	// RecordPluralReview grades and stores an answer of the plural drill.
+	RecordPluralReview(ctx context.Context, userID int, review *models.PluralReview) error
+	GetPluralBreakdown(ctx context.Context, userID int) (*models.PluralBreakdown, error)
+	GetStudyProgress(ctx context.Context, userID int) (*models.StudyProgress, error)
+	// GetQuickStats sums up the study of a user but for the streak.
+	GetQuickStats(ctx context.Context, userID int) (*models.DashboardStats, error)
+	// GetTimeSeries reports a stats metric over a range of days.
+	GetTimeSeries(ctx context.Context, userID int, metric string, rng stats.Range, filter models.StatsFilter) (*models.Series, error)
+	// GetCalendar reports the study of every day of a year, 0 for the
+	// current one, in the time zone of the user.
+	GetCalendar(ctx context.Context, userID, year int) (*models.Calendar, error)
+	// Timezone returns the time zone of a user, UTC for unknown users.
+	Timezone(ctx context.Context, userID int) (string, error)
+}
+
+type UserRepository interface {
+	CreateUser(ctx context.Context, user *models.User) error
+	GetUserByID(ctx context.Context, id int) (*models.User, error)
+	GetUserByEmail(ctx context.Context, email string) (*models.User, error)
+	ListUsers(ctx context.Context, params pagination.Params) ([]models.User, pagination.Page, error)
+	SetRole(ctx context.Context, id int, role string) (*models.User, error)
+	SetTimezone(ctx context.Context, id int, timezone string) (*models.User, error)
+	CreateRefreshToken(ctx context.Context, userID int, hash string, expiresAt time.Time) error
+	RevokeRefreshToken(ctx context.Context, hash string) (userID int, err error)
+}
+
+type APIKeyRepository interface {
+	CreateAPIKey(ctx context.Context, key *models.APIKey, hash string) error
+	ListAPIKeys(ctx context.Context, params pagination.Params) ([]models.APIKey, pagination.Page, error)
+	RevokeAPIKey(ctx context.Context, id int) (*models.APIKey, error)
+	// UseAPIKey finds a live key by hash, records the use at most about once a
+	// minute and returns the key with the role of its owner.
+	UseAPIKey(ctx context.Context, hash string) (key *models.APIKey, ownerRole string, err error)
 }
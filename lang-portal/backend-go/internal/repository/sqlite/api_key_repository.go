@@ -0,0 +1,149 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/souheilbenslama/free-genai-bootcamp-2025/lang-portal/backend-go/internal/models"
+	"github.com/souheilbenslama/free-genai-bootcamp-2025/lang-portal/backend-go/internal/pagination"
+	"github.com/souheilbenslama/free-genai-bootcamp-2025/lang-portal/backend-go/internal/repository"
+)
+
+const apiKeyColumns = "id, name, prefix, scopes, user_id, expires_at, last_used_at, revoked_at, created_at"
+
+// apiKeyUseInterval is how stale the recorded last use of a key gets before
+// a use updates it.
+const apiKeyUseInterval = time.Minute
+
+type APIKeyRepository struct {
+	db *sql.DB
+}
+
+func NewAPIKeyRepository(db *sql.DB) *APIKeyRepository {
+	return &APIKeyRepository{db: db}
+}
+
+func (r *APIKeyRepository) CreateAPIKey(ctx context.Context, key *models.APIKey, hash string) error {
+	defer observe(ctx, "api_key", "CreateAPIKey")()
+
+	createdAt := time.Now().UTC()
+	var expiresAt *time.Time
+	if key.ExpiresAt != nil {
+		utc := key.ExpiresAt.UTC()
+		expiresAt = &utc
+	}
+
+	result, err := execStatement(ctx, r.db, "api_keys.insert",
+		"INSERT INTO api_keys (name, prefix, key_hash, scopes, user_id, expires_at, created_at) VALUES (?, ?, ?, ?, ?, ?, ?)",
+		key.Name, key.Prefix, hash, strings.Join(key.Scopes, " "), key.UserID, expiresAt, createdAt)
+	if err != nil {
+		return fmt.Errorf("error creating API key: %w", err)
+	}
+
+	id, err := result.LastInsertId()
+	if err != nil {
+		return fmt.Errorf("error getting last insert id: %w", err)
+	}
+
+	key.ID = int(id)
+	key.ExpiresAt = expiresAt
+	key.CreatedAt = createdAt
+	return nil
+}
+
+func (r *APIKeyRepository) ListAPIKeys(ctx context.Context, params pagination.Params) ([]models.APIKey, pagination.Page, error) {
+	defer observe(ctx, "api_key", "ListAPIKeys")()
+
+	var total int
+	if err := queryRowStatement(ctx, r.db, "api_keys.count", "SELECT COUNT(*) FROM api_keys").Scan(&total); err != nil {
+		return nil, pagination.Page{}, fmt.Errorf("error counting API keys: %w", err)
+	}
+
+	// Fetch one extra row to find out whether another page follows
+	rows, err := queryStatement(ctx, r.db, "api_keys.list",
+		"SELECT "+apiKeyColumns+" FROM api_keys WHERE id > ? ORDER BY id LIMIT ?",
+		params.AfterID, params.Limit+1)
+	if err != nil {
+		return nil, pagination.Page{}, fmt.Errorf("error querying API keys: %w", err)
+	}
+	defer rows.Close()
+
+	keys := []models.APIKey{}
+	for rows.Next() {
+		key, err := scanAPIKey(rows)
+		if err != nil {
+			return nil, pagination.Page{}, fmt.Errorf("error scanning API key: %w", err)
+		}
+		keys = append(keys, *key)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, pagination.Page{}, fmt.Errorf("error iterating API keys: %w", err)
+	}
+
+	keys, page := pagination.Trim(keys, params, total, func(key models.APIKey) int { return key.ID })
+	return keys, page, nil
+}
+
+// RevokeAPIKey stops a key from authenticating and returns it. Revoking a key
+// twice keeps the first revocation time. It returns sql.ErrNoRows for unknown
+// keys.
+func (r *APIKeyRepository) RevokeAPIKey(ctx context.Context, id int) (*models.APIKey, error) {
+	defer observe(ctx, "api_key", "RevokeAPIKey")()
+
+	return scanAPIKey(queryRowStatement(ctx, r.db, "api_keys.revoke",
+		"UPDATE api_keys SET revoked_at = COALESCE(revoked_at, ?) WHERE id = ? RETURNING "+apiKeyColumns,
+		time.Now().UTC(), id))
+}
+
+// UseAPIKey finds a live key by hash and its owner's role. Uses within
+// apiKeyUseInterval of the recorded one only read, so requests with the key
+// do not each write to the database.
+func (r *APIKeyRepository) UseAPIKey(ctx context.Context, hash string) (*models.APIKey, string, error) {
+	defer observe(ctx, "api_key", "UseAPIKey")()
+
+	now := time.Now().UTC()
+	var role string
+	row := queryRowStatement(ctx, r.db, "api_keys.select_live", `
+		SELECT `+apiKeyColumns+`, role
+		FROM api_keys
+		JOIN (SELECT id AS owner_id, role FROM users) ON owner_id = user_id
+		WHERE key_hash = ? AND revoked_at IS NULL AND (expires_at IS NULL OR expires_at > ?)`,
+		hash, now)
+	key, err := scanAPIKey(row, &role)
+	if err == sql.ErrNoRows {
+		return nil, "", repository.ErrInvalidAPIKey
+	}
+	if err != nil {
+		return nil, "", fmt.Errorf("error using API key: %w", err)
+	}
+
+	if key.LastUsedAt == nil || key.LastUsedAt.Before(now.Add(-apiKeyUseInterval)) {
+		// Concurrent requests record the use once
+		_, err := execStatement(ctx, r.db, "api_keys.use",
+			"UPDATE api_keys SET last_used_at = ? WHERE id = ? AND (last_used_at IS NULL OR last_used_at < ?)",
+			now, key.ID, now.Add(-apiKeyUseInterval))
+		if err != nil {
+			return nil, "", fmt.Errorf("error recording API key use: %w", err)
+		}
+		key.LastUsedAt = &now
+	}
+
+	return key, role, nil
+}
+
+// scanAPIKey reads the apiKeyColumns of one row, then any columns after them
+// into extra.
+func scanAPIKey(row interface{ Scan(dest ...any) error }, extra ...any) (*models.APIKey, error) {
+	var key models.APIKey
+	var scopes string
+	err := row.Scan(append([]any{&key.ID, &key.Name, &key.Prefix, &scopes, &key.UserID,
+		&key.ExpiresAt, &key.LastUsedAt, &key.RevokedAt, &key.CreatedAt}, extra...)...)
+	if err != nil {
+		return nil, err
+	}
+	key.Scopes = strings.Fields(scopes)
+	return &key, nil
+}
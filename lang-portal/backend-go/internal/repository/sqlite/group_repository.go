@@ -3,9 +3,13 @@ package sqlite
 import (
 	"context"
 	"database/sql"
+	"encoding/json"
 	"fmt"
+	"math"
+	"time"
 
 	"github.com/souheilbenslama/free-genai-bootcamp-2025/lang-portal/backend-go/internal/models"
+	"github.com/souheilbenslama/free-genai-bootcamp-2025/lang-portal/backend-go/internal/pagination"
 )
 
 type GroupRepository struct {
@@ -13,8 +17,10 @@ type GroupRepository struct {
 }
 
 func (r *GroupRepository) CreateGroup(ctx context.Context, group *models.Group) error {
-	result, err := r.db.ExecContext(
-		ctx,
+	defer observe(ctx, "group", "CreateGroup")()
+
+	result, err := execStatement(
+		ctx, r.db, "groups.insert",
 		"INSERT INTO groups (name, description) VALUES (?, ?)",
 		group.Name,
 		group.Description,
@@ -33,8 +39,10 @@ func (r *GroupRepository) CreateGroup(ctx context.Context, group *models.Group)
 }
 
 func (r *GroupRepository) UpdateGroup(ctx context.Context, group *models.Group) error {
-	result, err := r.db.ExecContext(
-		ctx,
+	defer observe(ctx, "group", "UpdateGroup")()
+
+	result, err := execStatement(
+		ctx, r.db, "groups.update",
 		"UPDATE groups SET name = ?, description = ? WHERE id = ?",
 		group.Name,
 		group.Description,
@@ -57,14 +65,16 @@ func (r *GroupRepository) UpdateGroup(ctx context.Context, group *models.Group)
 }
 
 func (r *GroupRepository) DeleteGroup(ctx context.Context, id int) error {
+	defer observe(ctx, "group", "DeleteGroup")()
+
 	// First delete any word associations
-	_, err := r.db.ExecContext(ctx, "DELETE FROM words_groups WHERE group_id = ?", id)
+	_, err := execStatement(ctx, r.db, "words_groups.delete_by_group", "DELETE FROM words_groups WHERE group_id = ?", id)
 	if err != nil {
 		return fmt.Errorf("error deleting group associations: %w", err)
 	}
 
 	// Then delete the group
-	result, err := r.db.ExecContext(ctx, "DELETE FROM groups WHERE id = ?", id)
+	result, err := execStatement(ctx, r.db, "groups.delete", "DELETE FROM groups WHERE id = ?", id)
 	if err != nil {
 		return fmt.Errorf("error deleting group: %w", err)
 	}
@@ -82,10 +92,12 @@ func (r *GroupRepository) DeleteGroup(ctx context.Context, id int) error {
 }
 
 func (r *GroupRepository) AddWordToGroup(ctx context.Context, groupID, wordID int) error {
+	defer observe(ctx, "group", "AddWordToGroup")()
+
 	// Check if the word exists
 	var wordExists bool
-	err := r.db.QueryRowContext(
-		ctx,
+	err := queryRowStatement(
+		ctx, r.db, "words.exists",
 		"SELECT EXISTS(SELECT 1 FROM words WHERE id = ?)",
 		wordID,
 	).Scan(&wordExists)
@@ -100,8 +112,8 @@ func (r *GroupRepository) AddWordToGroup(ctx context.Context, groupID, wordID in
 
 	// Check if the association already exists
 	var exists bool
-	err = r.db.QueryRowContext(
-		ctx,
+	err = queryRowStatement(
+		ctx, r.db, "words_groups.exists",
 		"SELECT EXISTS(SELECT 1 FROM words_groups WHERE group_id = ? AND word_id = ?)",
 		groupID,
 		wordID,
@@ -116,8 +128,8 @@ func (r *GroupRepository) AddWordToGroup(ctx context.Context, groupID, wordID in
 	}
 
 	// Add the association
-	_, err = r.db.ExecContext(
-		ctx,
+	_, err = execStatement(
+		ctx, r.db, "words_groups.insert",
 		"INSERT INTO words_groups (group_id, word_id) VALUES (?, ?)",
 		groupID,
 		wordID,
@@ -131,8 +143,10 @@ func (r *GroupRepository) AddWordToGroup(ctx context.Context, groupID, wordID in
 }
 
 func (r *GroupRepository) RemoveWordFromGroup(ctx context.Context, groupID, wordID int) error {
-	result, err := r.db.ExecContext(
-		ctx,
+	defer observe(ctx, "group", "RemoveWordFromGroup")()
+
+	result, err := execStatement(
+		ctx, r.db, "words_groups.delete",
 		"DELETE FROM words_groups WHERE group_id = ? AND word_id = ?",
 		groupID,
 		wordID,
@@ -158,47 +172,54 @@ func NewGroupRepository(db *sql.DB) *GroupRepository {
 	return &GroupRepository{db: db}
 }
 
-func (r *GroupRepository) GetAll(page, pageSize int) ([]models.Group, int, error) {
-	offset := (page - 1) * pageSize
+func (r *GroupRepository) ListGroups(ctx context.Context, params pagination.Params) ([]models.Group, pagination.Page, error) {
+	defer observe(ctx, "group", "ListGroups")()
 
 	// Get total count
 	var total int
-	err := r.db.QueryRow("SELECT COUNT(*) FROM groups").Scan(&total)
+	err := queryRowStatement(ctx, r.db, "groups.count", "SELECT COUNT(*) FROM groups").Scan(&total)
 	if err != nil {
-		return nil, 0, fmt.Errorf("error counting groups: %w", err)
+		return nil, pagination.Page{}, fmt.Errorf("error counting groups: %w", err)
 	}
 
-	// Get groups with word count
+	// Get groups with word count, fetching one extra row to detect a next page
 	query := `
 		SELECT g.id, g.name, COUNT(wg.word_id) as word_count
 		FROM groups g
 		LEFT JOIN words_groups wg ON g.id = wg.group_id
+		WHERE g.id > ?
 		GROUP BY g.id
 		ORDER BY g.id
-		LIMIT ? OFFSET ?
+		LIMIT ?
 	`
 
-	rows, err := r.db.Query(query, pageSize, offset)
+	rows, err := queryStatement(ctx, r.db, "groups.list", query, params.AfterID, params.Limit+1)
 	if err != nil {
-		return nil, 0, fmt.Errorf("error querying groups: %w", err)
+		return nil, pagination.Page{}, fmt.Errorf("error querying groups: %w", err)
 	}
 	defer rows.Close()
 
-	var groups []models.Group
+	groups := []models.Group{}
 	for rows.Next() {
 		var g models.Group
 		if err := rows.Scan(&g.ID, &g.Name, &g.WordCount); err != nil {
-			return nil, 0, fmt.Errorf("error scanning group: %w", err)
+			return nil, pagination.Page{}, fmt.Errorf("error scanning group: %w", err)
 		}
 		groups = append(groups, g)
 	}
+	if err := rows.Err(); err != nil {
+		return nil, pagination.Page{}, fmt.Errorf("error iterating groups: %w", err)
+	}
 
-	return groups, total, nil
+	groups, page := pagination.Trim(groups, params, total, func(group models.Group) int { return group.ID })
+	return groups, page, nil
 }
 
-func (r *GroupRepository) GetByID(id int) (*models.Group, error) {
+func (r *GroupRepository) GetByID(ctx context.Context, id int) (*models.Group, error) {
+	defer observe(ctx, "group", "GetByID")()
+
 	query := `
-		SELECT g.id, g.name, g.description, COUNT(wg.word_id) as word_count
+		SELECT g.id, g.name, COALESCE(g.description, ''), COUNT(wg.word_id) as word_count
 		FROM groups g
 		LEFT JOIN words_groups wg ON g.id = wg.group_id
 		WHERE g.id = ?
@@ -206,7 +227,7 @@ func (r *GroupRepository) GetByID(id int) (*models.Group, error) {
 	`
 
 	var group models.Group
-	err := r.db.QueryRow(query, id).Scan(&group.ID, &group.Name, &group.Description, &group.WordCount)
+	err := queryRowStatement(ctx, r.db, "groups.select_by_id", query, id).Scan(&group.ID, &group.Name, &group.Description, &group.WordCount)
 	if err == sql.ErrNoRows {
 		return nil, nil
 	}
@@ -217,7 +238,9 @@ func (r *GroupRepository) GetByID(id int) (*models.Group, error) {
 	return &group, nil
 }
 
-func (r *GroupRepository) GetGroupWords(groupID int) ([]models.Word, error) {
+func (r *GroupRepository) GetGroupWords(ctx context.Context, groupID int) ([]models.Word, error) {
+	defer observe(ctx, "group", "GetGroupWords")()
+
 	query := `
 		SELECT w.id, w.german, w.english, w.parts
 		FROM words w
@@ -226,13 +249,13 @@ func (r *GroupRepository) GetGroupWords(groupID int) ([]models.Word, error) {
 		ORDER BY w.id
 	`
 
-	rows, err := r.db.Query(query, groupID)
+	rows, err := queryStatement(ctx, r.db, "words.list_by_group", query, groupID)
 	if err != nil {
 		return nil, fmt.Errorf("error querying group words: %w", err)
 	}
 	defer rows.Close()
 
-	var words []models.Word
+	words := []models.Word{}
 	for rows.Next() {
 		var w models.Word
 		if err := rows.Scan(&w.ID, &w.German, &w.English, &w.Parts); err != nil {
@@ -243,3 +266,141 @@ func (r *GroupRepository) GetGroupWords(groupID int) ([]models.Word, error) {
 
 	return words, nil
 }
+
+// defaultReviewSeconds is the pace of learners without a review yet.
+const defaultReviewSeconds = 15
+
+// GetProgress sums up the progress of the user with each group of groupIDs,
+// keyed by group. Groups that do not exist have no words.
+func (r *GroupRepository) GetProgress(ctx context.Context, userID int, groupIDs []int) (map[int]*models.GroupProgress, error) {
+	defer observe(ctx, "group", "GetProgress")()
+
+	progress := map[int]*models.GroupProgress{}
+	for _, id := range groupIDs {
+		progress[id] = &models.GroupProgress{GroupID: id}
+	}
+	if len(groupIDs) == 0 {
+		return progress, nil
+	}
+	ids, err := json.Marshal(groupIDs)
+	if err != nil {
+		return nil, fmt.Errorf("error encoding group IDs: %w", err)
+	}
+
+	rows, err := queryStatement(ctx, r.db, "groups.word_progress", `
+		WITH word_stats AS (
+			SELECT wri.word_id,
+				   COUNT(*) AS total,
+				   SUM(CASE WHEN wri.correct THEN 1 ELSE 0 END) AS correct
+			FROM word_review_items wri
+			JOIN study_sessions s ON s.id = wri.study_session_id
+			WHERE s.user_id = ?1
+			GROUP BY wri.word_id
+		)
+		SELECT wg.group_id, COALESCE(ws.total, 0), COALESCE(ws.correct, 0)
+		FROM (SELECT DISTINCT group_id, word_id FROM words_groups WHERE group_id IN (SELECT value FROM json_each(?2))) wg
+		LEFT JOIN word_stats ws ON ws.word_id = wg.word_id
+	`, userID, string(ids))
+	if err != nil {
+		return nil, fmt.Errorf("error querying word progress: %w", err)
+	}
+	defer rows.Close()
+
+	type tally struct{ total, correct int }
+	groupReviews := map[int]*tally{}
+	remaining := map[int]int{}
+	for rows.Next() {
+		var groupID int
+		var word tally
+		if err := rows.Scan(&groupID, &word.total, &word.correct); err != nil {
+			return nil, fmt.Errorf("error scanning word progress: %w", err)
+		}
+
+		p := progress[groupID]
+		p.TotalWords++
+		switch {
+		case word.total == 0:
+			p.NeverSeen++
+			remaining[groupID]++
+		// At least 80% correct, like GetStudyProgress
+		case 5*word.correct >= 4*word.total:
+			p.Mastered++
+		default:
+			p.Learning++
+			// The correct reviews that lift the word to 80%
+			remaining[groupID] += 4*word.total - 5*word.correct
+		}
+
+		if groupReviews[groupID] == nil {
+			groupReviews[groupID] = &tally{}
+		}
+		groupReviews[groupID].total += word.total
+		groupReviews[groupID].correct += word.correct
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating word progress: %w", err)
+	}
+
+	rows, err = queryStatement(ctx, r.db, "groups.last_studied", `
+		SELECT group_id, last_activity_at
+		FROM study_sessions
+		WHERE user_id = ?1 AND group_id IN (SELECT value FROM json_each(?2))
+	`, userID, string(ids))
+	if err != nil {
+		return nil, fmt.Errorf("error querying study sessions: %w", err)
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var groupID int
+		var lastActivity time.Time
+		if err := rows.Scan(&groupID, &lastActivity); err != nil {
+			return nil, fmt.Errorf("error scanning study session: %w", err)
+		}
+		if p := progress[groupID]; p.LastStudiedAt == nil || lastActivity.After(*p.LastStudiedAt) {
+			p.LastStudiedAt = &lastActivity
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating study sessions: %w", err)
+	}
+
+	// The pace is the time the user spent in sessions per review of any drill
+	reviews, err := reviewsOf("")
+	if err != nil {
+		return nil, err
+	}
+	var seconds float64
+	var reviewed int
+	err = queryRowStatement(ctx, r.db, "study_sessions.pace", `
+		SELECT
+			COALESCE((
+				SELECT SUM(MAX(julianday(COALESCE(ended_at, last_activity_at)) - julianday(created_at), 0)) * 86400
+				FROM study_sessions
+				WHERE user_id = ?1
+			), 0),
+			(
+				SELECT COUNT(*)
+				FROM (`+reviews+`) rv
+				JOIN study_sessions s ON s.id = rv.study_session_id
+				WHERE s.user_id = ?1
+			)
+	`, userID).Scan(&seconds, &reviewed)
+	if err != nil {
+		return nil, fmt.Errorf("error measuring pace: %w", err)
+	}
+	pace := float64(defaultReviewSeconds)
+	if reviewed > 0 && seconds > 0 {
+		pace = seconds / float64(reviewed)
+	}
+
+	for id, p := range progress {
+		p.Completed = p.TotalWords > 0 && p.Mastered == p.TotalWords
+		if group := groupReviews[id]; group != nil && group.total > 0 {
+			accuracy := percentage(group.correct, group.total)
+			p.Accuracy = &accuracy
+		}
+		p.EstimatedMinutes = math.Round(float64(remaining[id])*pace/60*10) / 10
+	}
+
+	return progress, nil
+}
@@ -0,0 +1,119 @@
+package sqlite
+
+import (
+	"cmp"
+	"context"
+	"database/sql"
+	"log/slog"
+	"sync"
+	"time"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+
+	"github.com/souheilbenslama/free-genai-bootcamp-2025/lang-portal/backend-go/internal/metrics"
+)
+
+var tracer = otel.Tracer("github.com/souheilbenslama/free-genai-bootcamp-2025/lang-portal/backend-go/internal/repository/sqlite")
+
+// querier is satisfied by both *sql.DB and *sql.Tx.
+type querier interface {
+	ExecContext(ctx context.Context, query string, args ...interface{}) (sql.Result, error)
+	QueryContext(ctx context.Context, query string, args ...interface{}) (*sql.Rows, error)
+	QueryRowContext(ctx context.Context, query string, args ...interface{}) *sql.Row
+}
+
+// observe times a repository method for the query duration metric and logs it
+// at debug level with the request ID carried by ctx. Call the returned
+// function when the method returns:
+//
+//	defer observe(ctx, "word", "GetWord")()
+func observe(ctx context.Context, repository, method string) func() {
+	start := time.Now()
+	done := metrics.ObserveQuery(repository, method)
+	return func() {
+		done()
+		slog.DebugContext(ctx, "repository call",
+			slog.String("repository", repository),
+			slog.String("method", method),
+			slog.Duration("duration", time.Since(start)),
+		)
+	}
+}
+
+// startStatement opens a span for one SQL statement. Spans carry the
+// statement name and its SQL text with placeholders, never the bound values.
+func startStatement(ctx context.Context, name, query string) (context.Context, trace.Span) {
+	return tracer.Start(ctx, name,
+		trace.WithSpanKind(trace.SpanKindClient),
+		trace.WithAttributes(
+			attribute.String("db.system", "sqlite"),
+			attribute.String("db.statement.name", name),
+			attribute.String("db.statement", query),
+		),
+	)
+}
+
+func endStatement(span trace.Span, err error) {
+	if err != nil && err != sql.ErrNoRows {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+	span.End()
+}
+
+func execStatement(ctx context.Context, q querier, name, query string, args ...interface{}) (sql.Result, error) {
+	ctx, span := startStatement(ctx, name, query)
+	result, err := q.ExecContext(ctx, query, args...)
+	endStatement(span, err)
+	return result, err
+}
+
+func queryRowStatement(ctx context.Context, q querier, name, query string, args ...interface{}) *sql.Row {
+	ctx, span := startStatement(ctx, name, query)
+	row := q.QueryRowContext(ctx, query, args...)
+	endStatement(span, row.Err())
+	return row
+}
+
+// queryStatement leaves the span open until the rows are closed, so it
+// covers reading them as well as running the query.
+func queryStatement(ctx context.Context, q querier, name, query string, args ...interface{}) (*statementRows, error) {
+	ctx, span := startStatement(ctx, name, query)
+	rows, err := q.QueryContext(ctx, query, args...)
+	if err != nil {
+		endStatement(span, err)
+		return nil, err
+	}
+	return &statementRows{Rows: rows, span: span}, nil
+}
+
+// statementRows ends the span of its statement once the rows run out or are
+// closed, with the error that stopped them if any.
+type statementRows struct {
+	*sql.Rows
+	span trace.Span
+	once sync.Once
+}
+
+func (r *statementRows) Next() bool {
+	if r.Rows.Next() {
+		return true
+	}
+	r.end(nil)
+	return false
+}
+
+func (r *statementRows) Close() error {
+	err := r.Rows.Close()
+	r.end(err)
+	return err
+}
+
+func (r *statementRows) end(err error) {
+	r.once.Do(func() {
+		endStatement(r.span, cmp.Or(r.Rows.Err(), err))
+	})
+}
@@ -1,13 +1,23 @@
 package sqlite
 
 import (
+	"cmp"
+	"context"
 	"database/sql"
 	"fmt"
+	"slices"
+	"strconv"
+	"strings"
 	"time"
 
 	"github.com/souheilbenslama/free-genai-bootcamp-2025/lang-portal/backend-go/internal/models"
+	"github.com/souheilbenslama/free-genai-bootcamp-2025/lang-portal/backend-go/internal/pagination"
+	"github.com/souheilbenslama/free-genai-bootcamp-2025/lang-portal/backend-go/internal/quiz"
+	"github.com/souheilbenslama/free-genai-bootcamp-2025/lang-portal/backend-go/internal/repository"
 )
 
+const studySessionColumns = "id, user_id, group_id, COALESCE(study_activity_id, 0), created_at, state, last_activity_at, ended_at"
+
 type StudyRepository struct {
 	db *sql.DB
 }
@@ -16,18 +26,20 @@ func NewStudyRepository(db *sql.DB) *StudyRepository {
 	return &StudyRepository{db: db}
 }
 
-func (r *StudyRepository) CreateStudySession(groupID int) (*models.StudySession, error) {
+func (r *StudyRepository) CreateStudySession(ctx context.Context, userID, groupID int) (*models.StudySession, error) {
+	defer observe(ctx, "study", "CreateStudySession")()
+
 	// First check if group exists
 	var exists bool
-	err := r.db.QueryRow("SELECT EXISTS(SELECT 1 FROM groups WHERE id = ?)", groupID).Scan(&exists)
+	err := queryRowStatement(ctx, r.db, "groups.exists", "SELECT EXISTS(SELECT 1 FROM groups WHERE id = ?)", groupID).Scan(&exists)
 	if err != nil {
 		return nil, fmt.Errorf("error checking group existence: %w", err)
 	}
 	if !exists {
-		return nil, fmt.Errorf("group with id %d does not exist", groupID)
+		return nil, repository.ErrGroupNotFound
 	}
 
-	tx, err := r.db.Begin()
+	tx, err := r.db.BeginTx(ctx, nil)
 	if err != nil {
 		return nil, fmt.Errorf("error beginning transaction: %w", err)
 	}
@@ -37,13 +49,16 @@ func (r *StudyRepository) CreateStudySession(groupID int) (*models.StudySession,
 		}
 	}()
 
-	createdAt := time.Now()
+	createdAt := time.Now().UTC()
 
 	// Create study session first
-	sessionResult, err := tx.Exec(
-		"INSERT INTO study_sessions (group_id, created_at) VALUES (?, ?)",
+	sessionResult, err := execStatement(ctx, tx, "study_sessions.insert",
+		"INSERT INTO study_sessions (user_id, group_id, created_at, state, last_activity_at) VALUES (?, ?, ?, ?, ?)",
+		userID,
 		groupID,
 		createdAt,
+		models.SessionActive,
+		createdAt,
 	)
 	if err != nil {
 		return nil, fmt.Errorf("error creating study session: %w", err)
@@ -55,7 +70,7 @@ func (r *StudyRepository) CreateStudySession(groupID int) (*models.StudySession,
 	}
 
 	// Create study activity
-	activityResult, err := tx.Exec(
+	activityResult, err := execStatement(ctx, tx, "study_activities.insert",
 		"INSERT INTO study_activities (study_session_id, group_id, created_at) VALUES (?, ?, ?)",
 		sessionID,
 		groupID,
@@ -71,7 +86,7 @@ func (r *StudyRepository) CreateStudySession(groupID int) (*models.StudySession,
 	}
 
 	// Update study session with activity ID
-	_, err = tx.Exec(
+	_, err = execStatement(ctx, tx, "study_sessions.set_activity",
 		"UPDATE study_sessions SET study_activity_id = ? WHERE id = ?",
 		activityID,
 		sessionID,
@@ -86,27 +101,27 @@ func (r *StudyRepository) CreateStudySession(groupID int) (*models.StudySession,
 
 	return &models.StudySession{
 		ID:              int(sessionID),
+		UserID:          userID,
 		GroupID:         groupID,
 		StudyActivityID: int(activityID),
-		CreatedAt:       time.Now(),
+		CreatedAt:       createdAt,
+		State:           models.SessionActive,
+		LastActivityAt:  createdAt,
 	}, nil
 }
 
-func (r *StudyRepository) GetLastStudySession() (*models.StudySession, error) {
+func (r *StudyRepository) GetLastStudySession(ctx context.Context, userID int) (*models.StudySession, error) {
+	defer observe(ctx, "study", "GetLastStudySession")()
+
 	query := `
-		SELECT id, group_id, study_activity_id, created_at
+		SELECT ` + studySessionColumns + `
 		FROM study_sessions
+		WHERE user_id = ?
 		ORDER BY created_at DESC
 		LIMIT 1
 	`
 
-	var session models.StudySession
-	err := r.db.QueryRow(query).Scan(
-		&session.ID,
-		&session.GroupID,
-		&session.StudyActivityID,
-		&session.CreatedAt,
-	)
+	session, err := scanStudySession(queryRowStatement(ctx, r.db, "study_sessions.select_last", query, userID))
 	if err == sql.ErrNoRows {
 		return nil, nil
 	}
@@ -114,56 +129,730 @@ func (r *StudyRepository) GetLastStudySession() (*models.StudySession, error) {
 		return nil, fmt.Errorf("error querying last study session: %w", err)
 	}
 
-	return &session, nil
+	return session, nil
 }
 
-func (r *StudyRepository) RecordWordReview(sessionID, wordID int, correct bool) error {
-	_, err := r.db.Exec(
-		"INSERT INTO word_review_items (word_id, study_session_id, correct, created_at) VALUES (?, ?, ?, ?)",
-		wordID,
-		sessionID,
-		correct,
-		time.Now(),
-	)
+func (r *StudyRepository) ListStudySessions(ctx context.Context, userID int, params pagination.Params) ([]models.StudySession, pagination.Page, error) {
+	defer observe(ctx, "study", "ListStudySessions")()
+
+	var total int
+	if err := queryRowStatement(ctx, r.db, "study_sessions.count", "SELECT COUNT(*) FROM study_sessions WHERE user_id = ?", userID).Scan(&total); err != nil {
+		return nil, pagination.Page{}, fmt.Errorf("error counting study sessions: %w", err)
+	}
+
+	// Fetch one extra row to find out whether another page follows
+	rows, err := queryStatement(ctx, r.db, "study_sessions.list", `
+		SELECT `+studySessionColumns+`
+		FROM study_sessions
+		WHERE user_id = ? AND id > ?
+		ORDER BY id
+		LIMIT ?
+	`, userID, params.AfterID, params.Limit+1)
+	if err != nil {
+		return nil, pagination.Page{}, fmt.Errorf("error querying study sessions: %w", err)
+	}
+	defer rows.Close()
+
+	sessions := []models.StudySession{}
+	for rows.Next() {
+		session, err := scanStudySession(rows)
+		if err != nil {
+			return nil, pagination.Page{}, fmt.Errorf("error scanning study session: %w", err)
+		}
+		sessions = append(sessions, *session)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, pagination.Page{}, fmt.Errorf("error iterating study sessions: %w", err)
+	}
+
+	sessions, page := pagination.Trim(sessions, params, total, func(session models.StudySession) int { return session.ID })
+	return sessions, page, nil
+}
+
+// FinishStudySession marks an active session of the user completed. It
+// returns sql.ErrNoRows for sessions the user does not own and
+// repository.ErrSessionEnded for sessions that already ended.
+func (r *StudyRepository) FinishStudySession(ctx context.Context, userID, sessionID int) (*models.StudySession, error) {
+	defer observe(ctx, "study", "FinishStudySession")()
+
+	session, err := scanStudySession(queryRowStatement(ctx, r.db, "study_sessions.finish", `
+		UPDATE study_sessions SET state = ?, ended_at = ?
+		WHERE id = ? AND user_id = ? AND state = ?
+		RETURNING `+studySessionColumns,
+		models.SessionCompleted, time.Now().UTC(), sessionID, userID, models.SessionActive))
+	if err == sql.ErrNoRows {
+		return nil, sessionNotActive(ctx, r.db, userID, sessionID)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("error finishing study session: %w", err)
+	}
+
+	return session, nil
+}
+
+// nextItemOrder ranks the words a session has yet to issue for each strategy.
+// Ties go to the lowest word ID so the deterministic strategies stay
+// predictable.
+var nextItemOrder = map[string]string{
+	models.StrategyShuffle: "RANDOM()",
+	// Words the user never reviewed count as weakest
+	models.StrategyWeakest: "COALESCE(CAST(stats.correct AS REAL) / stats.total, 0), w.id",
+	// Words the user never reviewed are due first
+	models.StrategyDue: "stats.last_reviewed_at IS NOT NULL, stats.last_reviewed_at, w.id",
+}
+
+// drillSources name the reviews the strategies rank the words of each drill
+// by, and the condition words must meet to be drilled.
+var drillSources = map[string]struct{ reviews, words string }{
+	models.DrillMeaning: {reviews: "word_review_items", words: "1"},
+	models.DrillArticle: {reviews: "article_reviews", words: wordHasPart("$.article")},
+	models.DrillPlural:  {reviews: "plural_reviews", words: wordHasPart("$.plural")},
+}
+
+// wordHasPart is a condition on words w whose JSON parts have a non-empty
+// value at path. Parts that are not JSON have none.
+func wordHasPart(path string) string {
+	return fmt.Sprintf("CASE WHEN json_valid(w.parts) THEN COALESCE(json_extract(w.parts, '%s'), '') <> '' ELSE 0 END", path)
+}
+
+// NextItem issues the next word of the group of an active session of the
+// user for drill, chosen by strategy among the words the session has not
+// issued for the drill yet, and counts it as activity. It returns nil when every word was
+// issued and the same errors as FinishStudySession.
+func (r *StudyRepository) NextItem(ctx context.Context, userID, sessionID int, strategy, drill string) (*models.SessionItem, error) {
+	defer observe(ctx, "study", "NextItem")()
+
+	order, ok := nextItemOrder[strategy]
+	if !ok {
+		return nil, fmt.Errorf("unknown strategy %q", strategy)
+	}
+	source, ok := drillSources[drill]
+	if !ok {
+		return nil, fmt.Errorf("unknown drill %q", drill)
+	}
+
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, fmt.Errorf("error beginning transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	now := time.Now().UTC()
+
+	var groupID int
+	err = queryRowStatement(ctx, tx, "study_sessions.touch_group",
+		"UPDATE study_sessions SET last_activity_at = ? WHERE id = ? AND user_id = ? AND state = ? RETURNING group_id",
+		now, sessionID, userID, models.SessionActive).Scan(&groupID)
+	if err == sql.ErrNoRows {
+		return nil, sessionNotActive(ctx, tx, userID, sessionID)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("error updating study session: %w", err)
+	}
+
+	// The candidates are the words of the group the session has not issued
+	// for the drill that suit it, with the user's history of the drill across all
+	// sessions
+	candidates := `
+		WITH stats AS (
+			SELECT r.word_id,
+				   COUNT(*) AS total,
+				   SUM(CASE WHEN r.correct THEN 1 ELSE 0 END) AS correct,
+				   MAX(r.created_at) AS last_reviewed_at
+			FROM %[2]s r
+			JOIN study_sessions s ON s.id = r.study_session_id
+			WHERE s.user_id = ?1
+			GROUP BY r.word_id
+		)
+		SELECT %[1]s
+		FROM words w
+		LEFT JOIN stats ON stats.word_id = w.id
+		WHERE w.id IN (SELECT word_id FROM words_groups WHERE group_id = ?2)
+		  AND w.id NOT IN (SELECT word_id FROM study_session_items WHERE study_session_id = ?3 AND drill = ?4)
+		  AND %[3]s
+	`
+
+	item := models.SessionItem{StudySessionID: sessionID, GroupID: groupID, Strategy: strategy, IssuedAt: now}
+	err = queryRowStatement(ctx, tx, "words.next_for_session",
+		fmt.Sprintf(candidates, "w.id, w.german, w.english, w.parts", source.reviews, source.words)+" ORDER BY "+order+" LIMIT 1",
+		userID, groupID, sessionID, drill).Scan(&item.Word.ID, &item.Word.German, &item.Word.English, &item.Word.Parts)
+	if err == sql.ErrNoRows {
+		// Nothing is left to issue, but asking still counts as activity
+		if err := tx.Commit(); err != nil {
+			return nil, fmt.Errorf("error committing transaction: %w", err)
+		}
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("error choosing next word: %w", err)
+	}
+
+	result, err := execStatement(ctx, tx, "study_session_items.insert",
+		"INSERT INTO study_session_items (study_session_id, word_id, drill, strategy, issued_at) VALUES (?, ?, ?, ?, ?)",
+		sessionID, item.Word.ID, drill, strategy, now)
+	if err != nil {
+		return nil, fmt.Errorf("error issuing word: %w", err)
+	}
+	id, err := result.LastInsertId()
+	if err != nil {
+		return nil, fmt.Errorf("error getting last insert id: %w", err)
+	}
+	item.ID = int(id)
+
+	err = queryRowStatement(ctx, tx, "words.count_left_for_session",
+		fmt.Sprintf(candidates, "COUNT(*)", source.reviews, source.words), userID, groupID, sessionID, drill).Scan(&item.Remaining)
+	if err != nil {
+		return nil, fmt.Errorf("error counting remaining words: %w", err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("error committing transaction: %w", err)
+	}
+
+	return &item, nil
+}
+
+// IssuedWord returns a word issued to a session of the user for drill. It
+// returns sql.ErrNoRows for sessions of other users and
+// repository.ErrWordNotIssued for words the session did not issue for drill.
+func (r *StudyRepository) IssuedWord(ctx context.Context, userID, sessionID, wordID int, drill string) (*models.Word, error) {
+	defer observe(ctx, "study", "IssuedWord")()
+
+	var word models.Word
+	err := queryRowStatement(ctx, r.db, "study_session_items.select_word", `
+		SELECT w.id, w.german, w.english, w.parts
+		FROM study_session_items i
+		JOIN study_sessions s ON s.id = i.study_session_id
+		JOIN words w ON w.id = i.word_id
+		WHERE s.user_id = ? AND i.study_session_id = ? AND i.word_id = ? AND i.drill = ?
+	`, userID, sessionID, wordID, drill).Scan(&word.ID, &word.German, &word.English, &word.Parts)
+	if err == sql.ErrNoRows {
+		owned, err := sessionOwned(ctx, r.db, userID, sessionID)
+		if err != nil {
+			return nil, err
+		}
+		if !owned {
+			return nil, sql.ErrNoRows
+		}
+		return nil, repository.ErrWordNotIssued
+	}
+	if err != nil {
+		return nil, fmt.Errorf("error getting issued word: %w", err)
+	}
+
+	return &word, nil
+}
+
+// RecordWordReview appends an attempt at a word the session issued for the
+// meaning drill to an active session of the user and counts it as activity,
+// which keeps the session from being abandoned. It fills in the ID and number of the attempt,
+// and the expected answer when only the direction is given. Besides the
+// errors of FinishStudySession it returns repository.ErrWordNotIssued, and
+// repository.ErrOptionNotOffered when the review picks an option the
+// question did not offer.
+func (r *StudyRepository) RecordWordReview(ctx context.Context, userID int, review *models.WordReviewItem) error {
+	defer observe(ctx, "study", "RecordWordReview")()
+
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("error beginning transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	now := time.Now().UTC()
+
+	// Reviews can only be added to the user's own sessions while they last
+	if err := touchSession(ctx, tx, userID, review.StudySessionID, now); err != nil {
+		return err
+	}
+
+	var options sql.NullString
+	var word models.Word
+	err = queryRowStatement(ctx, tx, "study_session_items.select_options", `
+		SELECT i.options, w.id, w.german, w.english, w.parts
+		FROM study_session_items i
+		JOIN words w ON w.id = i.word_id
+		WHERE i.study_session_id = ? AND i.word_id = ? AND i.drill = ?
+	`, review.StudySessionID, review.WordID, models.DrillMeaning).Scan(&options, &word.ID, &word.German, &word.English, &word.Parts)
+	if err == sql.ErrNoRows {
+		return repository.ErrWordNotIssued
+	}
+	if err != nil {
+		return fmt.Errorf("error checking issued words: %w", err)
+	}
+	if review.ChosenWordID != nil && !slices.Contains(strings.Fields(options.String), strconv.Itoa(*review.ChosenWordID)) {
+		return repository.ErrOptionNotOffered
+	}
+	if review.Direction != nil && review.Expected == nil {
+		expected := quiz.Expected(word, *review.Direction)
+		review.Expected = &expected
+	}
+
+	// Attempts are appended, numbered after the earlier attempts at the word
+	// in the session
+	err = queryRowStatement(ctx, tx, "word_review_items.insert", `
+		INSERT INTO word_review_items
+			(word_id, study_session_id, attempt, correct, verdict, chosen_word_id, answer, expected, response_ms, direction, created_at)
+		SELECT ?1, ?2, COALESCE(MAX(attempt), 0) + 1, ?3, ?4, ?5, ?6, ?7, ?8, ?9, ?10
+		FROM word_review_items
+		WHERE study_session_id = ?2 AND word_id = ?1
+		RETURNING id, attempt
+	`,
+		review.WordID,
+		review.StudySessionID,
+		review.Correct,
+		review.Verdict,
+		review.ChosenWordID,
+		review.Answer,
+		review.Expected,
+		review.ResponseMS,
+		review.Direction,
+		now,
+	).Scan(&review.ID, &review.Attempt)
 	if err != nil {
 		return fmt.Errorf("error recording word review: %w", err)
 	}
 
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("error committing transaction: %w", err)
+	}
+
+	review.CreatedAt = now
+	return nil
+}
+
+// SetItemOptions stores the options of a multiple choice question about an
+// item issued to a session of the user. It returns sql.ErrNoRows for items of
+// other users.
+func (r *StudyRepository) SetItemOptions(ctx context.Context, userID, itemID int, wordIDs []int) error {
+	defer observe(ctx, "study", "SetItemOptions")()
+
+	options := make([]string, len(wordIDs))
+	for i, id := range wordIDs {
+		options[i] = strconv.Itoa(id)
+	}
+
+	result, err := execStatement(ctx, r.db, "study_session_items.set_options", `
+		UPDATE study_session_items SET options = ?
+		WHERE id = ? AND study_session_id IN (SELECT id FROM study_sessions WHERE user_id = ?)
+	`, strings.Join(options, " "), itemID, userID)
+	if err != nil {
+		return fmt.Errorf("error storing question options: %w", err)
+	}
+
+	updated, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("error getting affected rows: %w", err)
+	}
+	if updated == 0 {
+		return sql.ErrNoRows
+	}
+
 	return nil
 }
 
-func (r *StudyRepository) GetStudyProgress() (*models.StudyProgress, error) {
+// RecordArticleReview grades the article chosen for a noun the session
+// issued for the article drill against the article of the noun and adds the answer to an active
+// session of the user, counting it as activity. Besides the errors of
+// FinishStudySession it returns repository.ErrWordNotIssued, and
+// repository.ErrNotANoun for words without an article.
+func (r *StudyRepository) RecordArticleReview(ctx context.Context, userID int, review *models.ArticleReview) error {
+	defer observe(ctx, "study", "RecordArticleReview")()
+
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("error beginning transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	now := time.Now().UTC()
+
+	if err := touchSession(ctx, tx, userID, review.StudySessionID, now); err != nil {
+		return err
+	}
+
+	var article sql.NullString
+	err = queryRowStatement(ctx, tx, "study_session_items.select_article", `
+		SELECT CASE WHEN json_valid(w.parts) THEN lower(trim(json_extract(w.parts, '$.article'))) END
+		FROM study_session_items i
+		JOIN words w ON w.id = i.word_id
+		WHERE i.study_session_id = ? AND i.word_id = ? AND i.drill = ?
+	`, review.StudySessionID, review.WordID, models.DrillArticle).Scan(&article)
+	if err == sql.ErrNoRows {
+		return repository.ErrWordNotIssued
+	}
+	if err != nil {
+		return fmt.Errorf("error checking issued words: %w", err)
+	}
+	if article.String == "" {
+		return repository.ErrNotANoun
+	}
+
+	review.Article = article.String
+	review.Correct = review.ChosenArticle == review.Article
+	result, err := execStatement(ctx, tx, "article_reviews.insert",
+		"INSERT INTO article_reviews (study_session_id, word_id, article, chosen_article, correct, created_at) VALUES (?, ?, ?, ?, ?, ?)",
+		review.StudySessionID, review.WordID, review.Article, review.ChosenArticle, review.Correct, now)
+	if err != nil {
+		return fmt.Errorf("error recording article review: %w", err)
+	}
+	id, err := result.LastInsertId()
+	if err != nil {
+		return fmt.Errorf("error getting last insert id: %w", err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("error committing transaction: %w", err)
+	}
+
+	review.ID = int(id)
+	review.CreatedAt = now
+	return nil
+}
+
+// GetArticleBreakdown sums up the article drill answers of the user per
+// article and per noun. Every article of quiz.Articles is reported, answered
+// or not.
+func (r *StudyRepository) GetArticleBreakdown(ctx context.Context, userID int) (*models.ArticleBreakdown, error) {
+	defer observe(ctx, "study", "GetArticleBreakdown")()
+
+	rows, err := queryStatement(ctx, r.db, "article_reviews.breakdown", `
+		SELECT ar.word_id, COALESCE(w.german, ''), ar.article, ar.chosen_article, COUNT(*)
+		FROM article_reviews ar
+		JOIN study_sessions s ON s.id = ar.study_session_id
+		LEFT JOIN words w ON w.id = ar.word_id
+		WHERE s.user_id = ?
+		GROUP BY ar.word_id, ar.article, ar.chosen_article
+		ORDER BY ar.word_id, ar.article
+	`, userID)
+	if err != nil {
+		return nil, fmt.Errorf("error querying article reviews: %w", err)
+	}
+	defer rows.Close()
+
+	breakdown := &models.ArticleBreakdown{Nouns: []models.NounStats{}}
+	articles := map[string]*models.ArticleStats{}
+	for _, article := range quiz.Articles {
+		breakdown.Articles = append(breakdown.Articles, models.ArticleStats{Article: article, ChosenInstead: map[string]int{}})
+	}
+	for i := range breakdown.Articles {
+		articles[breakdown.Articles[i].Article] = &breakdown.Articles[i]
+	}
+
+	for rows.Next() {
+		var noun models.NounStats
+		var chosen string
+		var count int
+		if err := rows.Scan(&noun.WordID, &noun.German, &noun.Article, &chosen, &count); err != nil {
+			return nil, fmt.Errorf("error scanning article review: %w", err)
+		}
+
+		// Rows of a noun are adjacent, one per chosen article
+		if n := len(breakdown.Nouns); n == 0 || breakdown.Nouns[n-1].WordID != noun.WordID || breakdown.Nouns[n-1].Article != noun.Article {
+			breakdown.Nouns = append(breakdown.Nouns, noun)
+		}
+		last := &breakdown.Nouns[len(breakdown.Nouns)-1]
+		last.Total += count
+
+		stats, ok := articles[noun.Article]
+		if !ok {
+			// Articles outside quiz.Articles only count for their nouns
+			stats = &models.ArticleStats{ChosenInstead: map[string]int{}}
+		}
+		stats.Total += count
+		if chosen == noun.Article {
+			last.Correct += count
+			stats.Correct += count
+		} else {
+			stats.ChosenInstead[chosen] += count
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating article reviews: %w", err)
+	}
+
+	for i := range breakdown.Nouns {
+		noun := &breakdown.Nouns[i]
+		noun.Accuracy = percentage(noun.Correct, noun.Total)
+	}
+	slices.SortStableFunc(breakdown.Nouns, func(a, b models.NounStats) int {
+		return cmp.Compare(a.Accuracy, b.Accuracy)
+	})
+
+	var worst *models.ArticleStats
+	for i := range breakdown.Articles {
+		stats := &breakdown.Articles[i]
+		stats.Accuracy = percentage(stats.Correct, stats.Total)
+		if stats.Correct < stats.Total && (worst == nil || stats.Accuracy < worst.Accuracy) {
+			worst = stats
+		}
+	}
+	if worst != nil {
+		breakdown.MostConfused = &worst.Article
+	}
+
+	return breakdown, nil
+}
+
+// RecordPluralReview grades the plural typed for a noun the session issued
+// for the plural drill with quiz.GradePlural and adds the answer to an active session of the
+// user, counting it as activity. Besides the errors of FinishStudySession it
+// returns repository.ErrWordNotIssued, and repository.ErrNoPlural for words
+// without a plural.
+func (r *StudyRepository) RecordPluralReview(ctx context.Context, userID int, review *models.PluralReview) error {
+	defer observe(ctx, "study", "RecordPluralReview")()
+
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("error beginning transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	now := time.Now().UTC()
+
+	if err := touchSession(ctx, tx, userID, review.StudySessionID, now); err != nil {
+		return err
+	}
+
+	var singular string
+	var plural sql.NullString
+	err = queryRowStatement(ctx, tx, "study_session_items.select_plural", `
+		SELECT w.german, CASE WHEN json_valid(w.parts) THEN trim(json_extract(w.parts, '$.plural')) END
+		FROM study_session_items i
+		JOIN words w ON w.id = i.word_id
+		WHERE i.study_session_id = ? AND i.word_id = ? AND i.drill = ?
+	`, review.StudySessionID, review.WordID, models.DrillPlural).Scan(&singular, &plural)
+	if err == sql.ErrNoRows {
+		return repository.ErrWordNotIssued
+	}
+	if err != nil {
+		return fmt.Errorf("error checking issued words: %w", err)
+	}
+	if plural.String == "" {
+		return repository.ErrNoPlural
+	}
+
+	grade := quiz.GradePlural(singular, plural.String, review.Answer)
+	review.Plural = plural.String
+	review.Pattern = grade.Pattern
+	review.AnswerPattern = grade.Applied
+	review.Correct = grade.Correct
+
+	result, err := execStatement(ctx, tx, "plural_reviews.insert", `
+		INSERT INTO plural_reviews (study_session_id, word_id, answer, plural, pattern, answer_pattern, correct, created_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?)
+	`, review.StudySessionID, review.WordID, review.Answer, review.Plural, review.Pattern, review.AnswerPattern, review.Correct, now)
+	if err != nil {
+		return fmt.Errorf("error recording plural review: %w", err)
+	}
+	id, err := result.LastInsertId()
+	if err != nil {
+		return fmt.Errorf("error getting last insert id: %w", err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("error committing transaction: %w", err)
+	}
+
+	review.ID = int(id)
+	review.CreatedAt = now
+	return nil
+}
+
+// GetPluralBreakdown sums up the plural drill answers of the user per plural
+// pattern. Every pattern of quiz.PluralPatterns is reported, answered or not.
+func (r *StudyRepository) GetPluralBreakdown(ctx context.Context, userID int) (*models.PluralBreakdown, error) {
+	defer observe(ctx, "study", "GetPluralBreakdown")()
+
+	rows, err := queryStatement(ctx, r.db, "plural_reviews.breakdown", `
+		SELECT pr.pattern, pr.answer_pattern, pr.correct, COUNT(*)
+		FROM plural_reviews pr
+		JOIN study_sessions s ON s.id = pr.study_session_id
+		WHERE s.user_id = ?
+		GROUP BY pr.pattern, pr.answer_pattern, pr.correct
+	`, userID)
+	if err != nil {
+		return nil, fmt.Errorf("error querying plural reviews: %w", err)
+	}
+	defer rows.Close()
+
+	breakdown := &models.PluralBreakdown{}
+	for _, pattern := range quiz.PluralPatterns {
+		breakdown.Patterns = append(breakdown.Patterns, models.PatternStats{Pattern: pattern, AppliedInstead: map[string]int{}})
+	}
+
+	for rows.Next() {
+		var pattern, applied string
+		var correct bool
+		var count int
+		if err := rows.Scan(&pattern, &applied, &correct, &count); err != nil {
+			return nil, fmt.Errorf("error scanning plural review: %w", err)
+		}
+
+		i := slices.Index(quiz.PluralPatterns, pattern)
+		if i < 0 {
+			continue
+		}
+		stats := &breakdown.Patterns[i]
+		stats.Total += count
+		if correct {
+			stats.Correct += count
+		} else if applied != pattern {
+			// A wrong answer of the right pattern, like a missing umlaut,
+			// was not confused with another pattern
+			stats.AppliedInstead[applied] += count
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating plural reviews: %w", err)
+	}
+
+	var weakest *models.PatternStats
+	for i := range breakdown.Patterns {
+		stats := &breakdown.Patterns[i]
+		stats.Accuracy = percentage(stats.Correct, stats.Total)
+		if stats.Correct < stats.Total && (weakest == nil || stats.Accuracy < weakest.Accuracy) {
+			weakest = stats
+		}
+	}
+	if weakest != nil {
+		breakdown.WeakestPattern = &weakest.Pattern
+	}
+
+	return breakdown, nil
+}
+
+// percentage is part of total in percent, or 0 for an empty total.
+func percentage(part, total int) float64 {
+	if total == 0 {
+		return 0
+	}
+	return float64(part) / float64(total) * 100
+}
+
+// AbandonIdleSessions ends the active sessions of every user without
+// activity since before cutoff. They end at their last activity, so the time
+// spent idle does not count as studying.
+func (r *StudyRepository) AbandonIdleSessions(ctx context.Context, cutoff time.Time) (int, error) {
+	defer observe(ctx, "study", "AbandonIdleSessions")()
+
+	result, err := execStatement(ctx, r.db, "study_sessions.abandon_idle", `
+		UPDATE study_sessions SET state = ?, ended_at = last_activity_at
+		WHERE state = ? AND last_activity_at < ?
+	`, models.SessionAbandoned, models.SessionActive, cutoff.UTC())
+	if err != nil {
+		return 0, fmt.Errorf("error abandoning idle study sessions: %w", err)
+	}
+
+	abandoned, err := result.RowsAffected()
+	if err != nil {
+		return 0, fmt.Errorf("error getting affected rows: %w", err)
+	}
+
+	return int(abandoned), nil
+}
+
+// AdoptLegacySessions gives the user the study sessions recorded before
+// accounts existed, which have no owner, along with their reviews.
+func (r *StudyRepository) AdoptLegacySessions(ctx context.Context, userID int) (int, error) {
+	defer observe(ctx, "study", "AdoptLegacySessions")()
+
+	result, err := execStatement(ctx, r.db, "study_sessions.adopt_legacy",
+		"UPDATE study_sessions SET user_id = ? WHERE user_id IS NULL", userID)
+	if err != nil {
+		return 0, fmt.Errorf("error adopting study sessions: %w", err)
+	}
+
+	adopted, err := result.RowsAffected()
+	if err != nil {
+		return 0, fmt.Errorf("error getting affected rows: %w", err)
+	}
+
+	return int(adopted), nil
+}
+
+// touchSession counts now as activity of an active session of the user,
+// returning the errors of sessionNotActive when it is not one.
+func touchSession(ctx context.Context, q querier, userID, sessionID int, now time.Time) error {
+	result, err := execStatement(ctx, q, "study_sessions.touch",
+		"UPDATE study_sessions SET last_activity_at = ? WHERE id = ? AND user_id = ? AND state = ?",
+		now, sessionID, userID, models.SessionActive)
+	if err != nil {
+		return fmt.Errorf("error updating study session: %w", err)
+	}
+	touched, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("error getting affected rows: %w", err)
+	}
+	if touched == 0 {
+		return sessionNotActive(ctx, q, userID, sessionID)
+	}
+	return nil
+}
+
+// sessionNotActive explains why a session of the user could not be changed:
+// sql.ErrNoRows when the user does not own it, repository.ErrSessionEnded
+// when it already ended.
+func sessionNotActive(ctx context.Context, q querier, userID, sessionID int) error {
+	owned, err := sessionOwned(ctx, q, userID, sessionID)
+	if err != nil {
+		return err
+	}
+	if !owned {
+		return sql.ErrNoRows
+	}
+	return repository.ErrSessionEnded
+}
+
+func sessionOwned(ctx context.Context, q querier, userID, sessionID int) (bool, error) {
+	var owned bool
+	err := queryRowStatement(ctx, q, "study_sessions.owned",
+		"SELECT EXISTS(SELECT 1 FROM study_sessions WHERE id = ? AND user_id = ?)",
+		sessionID, userID).Scan(&owned)
+	if err != nil {
+		return false, fmt.Errorf("error checking study session: %w", err)
+	}
+	return owned, nil
+}
+
+func (r *StudyRepository) GetStudyProgress(ctx context.Context, userID int) (*models.StudyProgress, error) {
+	defer observe(ctx, "study", "GetStudyProgress")()
+
 	// Get total available words
 	var totalWords int
-	err := r.db.QueryRow("SELECT COUNT(*) FROM words").Scan(&totalWords)
+	err := queryRowStatement(ctx, r.db, "words.count", "SELECT COUNT(*) FROM words").Scan(&totalWords)
 	if err != nil {
 		return nil, fmt.Errorf("error counting words: %w", err)
 	}
 
-	// Get total words studied (unique words in word_review_items)
+	// Get total words studied (unique words reviewed by the user)
 	var totalStudied int
-	err = r.db.QueryRow(`
-		SELECT COUNT(DISTINCT word_id)
-		FROM word_review_items
-	`).Scan(&totalStudied)
+	err = queryRowStatement(ctx, r.db, "word_review_items.count_words", `
+		SELECT COUNT(DISTINCT wri.word_id)
+		FROM word_review_items wri
+		JOIN study_sessions s ON s.id = wri.study_session_id
+		WHERE s.user_id = ?
+	`, userID).Scan(&totalStudied)
 	if err != nil {
 		return nil, fmt.Errorf("error counting studied words: %w", err)
 	}
 
 	// Calculate mastery percentage (words with > 80% correct answers)
 	var masteredWords int
-	err = r.db.QueryRow(`
+	err = queryRowStatement(ctx, r.db, "word_review_items.count_mastered", `
 		WITH word_stats AS (
-			SELECT word_id,
+			SELECT wri.word_id,
 				   COUNT(*) as total_reviews,
-				   SUM(CASE WHEN correct THEN 1 ELSE 0 END) as correct_reviews
-			FROM word_review_items
-			GROUP BY word_id
+				   SUM(CASE WHEN wri.correct THEN 1 ELSE 0 END) as correct_reviews
+			FROM word_review_items wri
+			JOIN study_sessions s ON s.id = wri.study_session_id
+			WHERE s.user_id = ?
+			GROUP BY wri.word_id
 		)
 		SELECT COUNT(*)
 		FROM word_stats
 		WHERE CAST(correct_reviews AS FLOAT) / total_reviews >= 0.8
-	`).Scan(&masteredWords)
+	`, userID).Scan(&masteredWords)
 	if err != nil {
 		return nil, fmt.Errorf("error calculating mastery: %w", err)
 	}
@@ -180,21 +869,26 @@ func (r *StudyRepository) GetStudyProgress() (*models.StudyProgress, error) {
 	}, nil
 }
 
-func (r *StudyRepository) GetQuickStats() (*models.DashboardStats, error) {
+// GetQuickStats sums up the study of the user. StudyStreakDays is left to
+// the streak service, which knows the time zone of the user.
+func (r *StudyRepository) GetQuickStats(ctx context.Context, userID int) (*models.DashboardStats, error) {
+	defer observe(ctx, "study", "GetQuickStats")()
+
 	stats := &models.DashboardStats{}
 
-	// Get total words, groups, and study sessions
-	err := r.db.QueryRow(`
-		SELECT 
-			COUNT(*) as total_words,
+	// Vocabulary is shared, everything else only counts the user's own study
+	err := queryRowStatement(ctx, r.db, "dashboard.totals", `
+		SELECT
+			(SELECT COUNT(*) FROM words) as total_words,
 			(SELECT COUNT(*) FROM groups) as total_groups,
-			(SELECT COUNT(*) FROM study_sessions) as total_sessions,
-			(SELECT COUNT(DISTINCT group_id) FROM study_sessions) as active_groups,
-			COALESCE(SUM(CASE WHEN correct THEN 1 ELSE 0 END), 0) as correct_answers,
-			COALESCE(SUM(CASE WHEN NOT correct THEN 1 ELSE 0 END), 0) as incorrect_answers
-		FROM words
-		LEFT JOIN word_review_items ON 1=1
-	`).Scan(
+			(SELECT COUNT(*) FROM study_sessions WHERE user_id = ?1) as total_sessions,
+			(SELECT COUNT(DISTINCT group_id) FROM study_sessions WHERE user_id = ?1) as active_groups,
+			COALESCE(SUM(CASE WHEN wri.correct THEN 1 ELSE 0 END), 0) as correct_answers,
+			COALESCE(SUM(CASE WHEN NOT wri.correct THEN 1 ELSE 0 END), 0) as incorrect_answers
+		FROM word_review_items wri
+		JOIN study_sessions s ON s.id = wri.study_session_id
+		WHERE s.user_id = ?1
+	`, userID).Scan(
 		&stats.TotalWords,
 		&stats.TotalGroups,
 		&stats.TotalStudySessions,
@@ -206,62 +900,29 @@ func (r *StudyRepository) GetQuickStats() (*models.DashboardStats, error) {
 		return nil, fmt.Errorf("error getting quick stats: %w", err)
 	}
 
-	successRate := 0.0
 	totalAnswers := stats.CorrectAnswers + stats.IncorrectAnswers
 	if totalAnswers > 0 {
-		successRate = float64(stats.CorrectAnswers) / float64(totalAnswers)
+		stats.SuccessRate = float64(stats.CorrectAnswers) / float64(totalAnswers)
 	}
 
-	// Get total study sessions
-	var totalSessions int
-	err = r.db.QueryRow("SELECT COALESCE(COUNT(*), 0) FROM study_sessions").Scan(&totalSessions)
-	if err != nil {
-		return nil, fmt.Errorf("error counting study sessions: %w", err)
-	}
+	return stats, nil
+}
 
-	// Get total active groups (groups with at least one study session)
-	var activeGroups int
-	err = r.db.QueryRow(`
-		SELECT COALESCE(COUNT(DISTINCT group_id), 0)
-		FROM study_sessions
-	`).Scan(&activeGroups)
-	if err != nil {
-		return nil, fmt.Errorf("error counting active groups: %w", err)
-	}
-
-	// Calculate study streak (consecutive days with study sessions)
-	var streak int
-	err = r.db.QueryRow(`
-		WITH RECURSIVE dates AS (
-			SELECT date(created_at) as study_date
-			FROM (
-				SELECT created_at
-				FROM study_sessions
-				ORDER BY created_at DESC
-				LIMIT 1
-			)
-		
-			UNION ALL
-		
-			SELECT date(d.study_date, '-1 day')
-			FROM dates d
-			WHERE EXISTS (
-				SELECT 1
-				FROM study_sessions s
-				WHERE date(s.created_at) = date(d.study_date, '-1 day')
-			)
-		)
-		SELECT COALESCE(COUNT(*), 0)
-		FROM dates
-	`).Scan(&streak)
+// scanStudySession reads the studySessionColumns of one row.
+func scanStudySession(row interface{ Scan(dest ...any) error }) (*models.StudySession, error) {
+	var session models.StudySession
+	err := row.Scan(&session.ID, &session.UserID, &session.GroupID, &session.StudyActivityID,
+		&session.CreatedAt, &session.State, &session.LastActivityAt, &session.EndedAt)
 	if err != nil {
-		return nil, fmt.Errorf("error calculating study streak: %w", err)
+		return nil, err
 	}
 
-	return &models.DashboardStats{
-		SuccessRate:         successRate,
-		TotalStudySessions: totalSessions,
-		TotalActiveGroups:  activeGroups,
-		StudyStreakDays:    streak,
-	}, nil
+	end := session.LastActivityAt
+	if session.EndedAt != nil {
+		end = *session.EndedAt
+	}
+	if d := end.Sub(session.CreatedAt); d > 0 {
+		session.DurationSeconds = int(d.Seconds())
+	}
+	return &session, nil
 }
@@ -0,0 +1,407 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"math"
+	"strings"
+	"time"
+
+	"github.com/souheilbenslama/free-genai-bootcamp-2025/lang-portal/backend-go/internal/models"
+	"github.com/souheilbenslama/free-genai-bootcamp-2025/lang-portal/backend-go/internal/stats"
+)
+
+// seriesQueries select the bucket, a value and the number of rows behind it
+// for each metric, over the reviews in %[1]s of the sessions of ?1, limited
+// to the group ?4 unless it is 0. The days ?2 through ?3 are in the series.
+// %[2]s is the bucket of a time column, %[3]s a condition on the sessions s
+// and %[4]s the time column in the time zone of the user.
+var seriesQueries = map[string]string{
+	stats.MetricReviews: `
+		SELECT %[2]s AS bucket, COUNT(*), COUNT(*)
+		FROM (%[1]s) rv
+		JOIN study_sessions s ON s.id = rv.study_session_id
+		WHERE s.user_id = ?1 AND (?4 = 0 OR s.group_id = ?4) AND date(%[4]s) BETWEEN ?2 AND ?3
+		GROUP BY bucket
+	`,
+	stats.MetricAccuracy: `
+		SELECT %[2]s AS bucket, SUM(CASE WHEN rv.correct THEN 1 ELSE 0 END), COUNT(*)
+		FROM (%[1]s) rv
+		JOIN study_sessions s ON s.id = rv.study_session_id
+		WHERE s.user_id = ?1 AND (?4 = 0 OR s.group_id = ?4) AND date(%[4]s) BETWEEN ?2 AND ?3
+		GROUP BY bucket
+	`,
+	// A word is new on its first review ever, so earlier reviews keep it
+	// out of the series
+	stats.MetricNewWords: `
+		SELECT %[2]s AS bucket, COUNT(*), COUNT(*)
+		FROM (
+			SELECT MIN(rv.created_at) AS created_at
+			FROM (%[1]s) rv
+			JOIN study_sessions s ON s.id = rv.study_session_id
+			WHERE s.user_id = ?1 AND (?4 = 0 OR s.group_id = ?4)
+			GROUP BY rv.word_id
+		) rv
+		WHERE date(%[4]s) BETWEEN ?2 AND ?3
+		GROUP BY bucket
+	`,
+	// Sessions last until they end, or while active until their last
+	// activity, like their duration
+	stats.MetricMinutes: `
+		SELECT %[2]s AS bucket,
+			SUM(MAX(julianday(COALESCE(s.ended_at, s.last_activity_at)) - julianday(s.created_at), 0)) * 1440,
+			COUNT(*)
+		FROM study_sessions s
+		WHERE s.user_id = ?1 AND (?4 = 0 OR s.group_id = ?4) AND date(%[4]s) BETWEEN ?2 AND ?3 AND %[3]s
+		GROUP BY bucket
+	`,
+}
+
+// reviewsOf selects the study_session_id, word_id, correct and created_at of
+// the reviews of drill, or of every drill when it is empty.
+func reviewsOf(drill string) (string, error) {
+	drills := []string{models.DrillMeaning, models.DrillArticle, models.DrillPlural}
+	if drill != "" {
+		if _, ok := drillSources[drill]; !ok {
+			return "", fmt.Errorf("unknown drill %q", drill)
+		}
+		drills = []string{drill}
+	}
+
+	selects := make([]string, len(drills))
+	for i, d := range drills {
+		selects[i] = "SELECT study_session_id, word_id, correct, created_at FROM " + drillSources[d].reviews
+	}
+	return strings.Join(selects, " UNION ALL "), nil
+}
+
+// bucketOf is the first day of the bucket holding the time in column, in
+// the stats.DateLayout.
+func bucketOf(bucket, column string) string {
+	if bucket == stats.BucketWeek {
+		// The next Sunday, or the day itself, ends the week
+		return fmt.Sprintf("date(%s, 'weekday 0', '-6 days')", column)
+	}
+	return fmt.Sprintf("date(%s)", column)
+}
+
+// localTime is the time in column at the offset of the zones holding it.
+func localTime(column string) string {
+	at := fmt.Sprintf("CAST(strftime('%%s', %s) AS INTEGER)", column)
+	return fmt.Sprintf(`(SELECT datetime(%[1]s + z.utc_offset, 'unixepoch') FROM zones z
+		WHERE (z.starts_at IS NULL OR %[1]s >= z.starts_at) AND (z.ends_at IS NULL OR %[1]s < z.ends_at))`, at)
+}
+
+// GetTimeSeries reports metric for the user over the buckets of rng, as days
+// in the time zone of the user, with a point for every bucket. Minutes are
+// rounded to tenths.
+func (r *StudyRepository) GetTimeSeries(ctx context.Context, userID int, metric string, rng stats.Range, filter models.StatsFilter) (*models.Series, error) {
+	defer observe(ctx, "study", "GetTimeSeries")()
+
+	query, ok := seriesQueries[metric]
+	if !ok {
+		return nil, fmt.Errorf("unknown metric %q", metric)
+	}
+	reviews, err := reviewsOf(filter.Drill)
+	if err != nil {
+		return nil, err
+	}
+	timezone, err := r.Timezone(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	loc, err := time.LoadLocation(timezone)
+	if err != nil {
+		return nil, fmt.Errorf("error loading time zone: %w", err)
+	}
+	// The zones cover the range with a day to spare on either side; earlier
+	// and later times take the first and last offset, enough to leave them out
+	zones := zonesSince(loc, rng.From.AddDate(0, 0, -1), rng.To.AddDate(0, 0, 2))
+
+	// Minutes count the sessions with reviews of the drill
+	column, sessions := "rv.created_at", "1"
+	if metric == stats.MetricMinutes {
+		column = "s.created_at"
+		if filter.Drill != "" {
+			sessions = fmt.Sprintf("EXISTS (SELECT 1 FROM %s dr WHERE dr.study_session_id = s.id)", drillSources[filter.Drill].reviews)
+		}
+	}
+
+	rows, err := queryStatement(ctx, r.db, "stats.series_"+metric,
+		"WITH zones (starts_at, ends_at, utc_offset) AS (VALUES "+zones+")"+
+			fmt.Sprintf(query, reviews, bucketOf(rng.Bucket, localTime(column)), sessions, localTime(column)),
+		userID, rng.From.Format(stats.DateLayout), rng.To.Format(stats.DateLayout), filter.GroupID)
+	if err != nil {
+		return nil, fmt.Errorf("error querying %s: %w", metric, err)
+	}
+	defer rows.Close()
+
+	type bucket struct {
+		value float64
+		count int
+	}
+	buckets := map[string]bucket{}
+	for rows.Next() {
+		var start string
+		var b bucket
+		if err := rows.Scan(&start, &b.value, &b.count); err != nil {
+			return nil, fmt.Errorf("error scanning %s: %w", metric, err)
+		}
+		buckets[start] = b
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating %s: %w", metric, err)
+	}
+
+	value := func(b bucket) *float64 {
+		var v float64
+		switch metric {
+		case stats.MetricAccuracy:
+			if b.count == 0 {
+				return nil
+			}
+			v = b.value / float64(b.count) * 100
+		case stats.MetricMinutes:
+			v = math.Round(b.value*10) / 10
+		default:
+			v = b.value
+		}
+		return &v
+	}
+
+	series := &models.Series{
+		From:   rng.From.Format(stats.DateLayout),
+		To:     rng.To.Format(stats.DateLayout),
+		Points: []models.SeriesPoint{},
+	}
+	var total bucket
+	for _, day := range rng.Starts() {
+		start := day.Format(stats.DateLayout)
+		b := buckets[start]
+		total.value += b.value
+		total.count += b.count
+		series.Points = append(series.Points, models.SeriesPoint{Start: start, Value: value(b)})
+	}
+	series.Total = value(total)
+
+	return series, nil
+}
+
+// GetCalendar reports the study of the user on every day of year, or of the
+// current year when it is 0, in the time zone of the user.
+func (r *StudyRepository) GetCalendar(ctx context.Context, userID, year int) (*models.Calendar, error) {
+	defer observe(ctx, "study", "GetCalendar")()
+
+	timezone, err := r.Timezone(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	loc, err := time.LoadLocation(timezone)
+	if err != nil {
+		return nil, fmt.Errorf("error loading time zone: %w", err)
+	}
+
+	if year == 0 {
+		year = time.Now().In(loc).Year()
+	}
+	start := time.Date(year, time.January, 1, 0, 0, 0, 0, loc)
+	end := start.AddDate(1, 0, 0)
+
+	calendar := &models.Calendar{Year: year, Timezone: timezone}
+	for day := start; day.Before(end); day = day.AddDate(0, 0, 1) {
+		calendar.Days = append(calendar.Days, models.CalendarDay{Date: day.Format(stats.DateLayout)})
+	}
+	// Times come back as Unix seconds, which the time zone turns into days
+	dayOf := func(unix int64) *models.CalendarDay {
+		return &calendar.Days[time.Unix(unix, 0).In(loc).YearDay()-1]
+	}
+
+	reviews, err := reviewsOf("")
+	if err != nil {
+		return nil, err
+	}
+	rows, err := queryStatement(ctx, r.db, "stats.calendar_reviews", `
+		SELECT CAST(strftime('%s', rv.created_at) AS INTEGER) AS at
+		FROM (`+reviews+`) rv
+		JOIN study_sessions s ON s.id = rv.study_session_id
+		WHERE s.user_id = ?1 AND at >= ?2 AND at < ?3
+	`, userID, start.Unix(), end.Unix())
+	if err != nil {
+		return nil, fmt.Errorf("error querying reviews: %w", err)
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var at int64
+		if err := rows.Scan(&at); err != nil {
+			return nil, fmt.Errorf("error scanning review: %w", err)
+		}
+		dayOf(at).Reviews++
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating reviews: %w", err)
+	}
+
+	rows, err = queryStatement(ctx, r.db, "stats.calendar_sessions", `
+		SELECT CAST(strftime('%s', created_at) AS INTEGER) AS at,
+			MAX(julianday(COALESCE(ended_at, last_activity_at)) - julianday(created_at), 0) * 1440
+		FROM study_sessions
+		WHERE user_id = ?1 AND at >= ?2 AND at < ?3
+	`, userID, start.Unix(), end.Unix())
+	if err != nil {
+		return nil, fmt.Errorf("error querying study sessions: %w", err)
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var at int64
+		var minutes float64
+		if err := rows.Scan(&at, &minutes); err != nil {
+			return nil, fmt.Errorf("error scanning study session: %w", err)
+		}
+		day := dayOf(at)
+		day.Sessions++
+		day.Minutes += minutes
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating study sessions: %w", err)
+	}
+
+	busiest := 0
+	for _, day := range calendar.Days {
+		busiest = max(busiest, day.Reviews)
+	}
+	for i := range calendar.Days {
+		day := &calendar.Days[i]
+		day.Minutes = math.Round(day.Minutes*10) / 10
+		day.Level = stats.Level(day.Reviews, busiest)
+	}
+
+	return calendar, nil
+}
+
+// Timezone returns the time zone of the user, UTC for unknown users.
+func (r *StudyRepository) Timezone(ctx context.Context, userID int) (string, error) {
+	defer observe(ctx, "study", "Timezone")()
+
+	var timezone string
+	err := queryRowStatement(ctx, r.db, "users.select_timezone",
+		"SELECT COALESCE((SELECT timezone FROM users WHERE id = ?), 'UTC')", userID).Scan(&timezone)
+	if err != nil {
+		return "", fmt.Errorf("error getting time zone: %w", err)
+	}
+	return timezone, nil
+}
+
+// StudyDays returns the days in loc, as dates at midnight UTC, on which
+// the user started study sessions or reviewed words of any drill.
+func (r *StudyRepository) StudyDays(ctx context.Context, userID int, loc *time.Location) ([]time.Time, error) {
+	defer observe(ctx, "study", "StudyDays")()
+
+	// Reviews come after the start of their session, so the first session
+	// starts the study of the user
+	var first sql.NullInt64
+	err := queryRowStatement(ctx, r.db, "stats.first_study_time",
+		"SELECT CAST(strftime('%s', MIN(created_at)) AS INTEGER) FROM study_sessions WHERE user_id = ?", userID).Scan(&first)
+	if err != nil {
+		return nil, fmt.Errorf("error getting first study time: %w", err)
+	}
+	if !first.Valid {
+		return nil, nil
+	}
+
+	reviews, err := reviewsOf("")
+	if err != nil {
+		return nil, err
+	}
+	rows, err := queryStatement(ctx, r.db, "stats.study_days", `
+		WITH zones (starts_at, ends_at, utc_offset) AS (VALUES `+zonesSince(loc, time.Unix(first.Int64, 0), time.Now())+`),
+		times (at) AS (
+			SELECT CAST(strftime('%s', created_at) AS INTEGER)
+			FROM study_sessions
+			WHERE user_id = ?1
+			UNION
+			SELECT CAST(strftime('%s', rv.created_at) AS INTEGER)
+			FROM (`+reviews+`) rv
+			JOIN study_sessions s ON s.id = rv.study_session_id
+			WHERE s.user_id = ?1
+		)
+		SELECT DISTINCT date(t.at + z.utc_offset, 'unixepoch')
+		FROM times t
+		JOIN zones z ON (z.starts_at IS NULL OR t.at >= z.starts_at) AND (z.ends_at IS NULL OR t.at < z.ends_at)
+	`, userID)
+	if err != nil {
+		return nil, fmt.Errorf("error querying study days: %w", err)
+	}
+	defer rows.Close()
+
+	var days []time.Time
+	for rows.Next() {
+		var day string
+		if err := rows.Scan(&day); err != nil {
+			return nil, fmt.Errorf("error scanning study day: %w", err)
+		}
+		date, err := time.Parse(stats.DateLayout, day)
+		if err != nil {
+			return nil, fmt.Errorf("error parsing study day: %w", err)
+		}
+		days = append(days, date)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating study days: %w", err)
+	}
+	return days, nil
+}
+
+// zonesSince lists the offsets of loc from first through now as SQL rows of
+// the Unix seconds each starts and ends at and its seconds east of UTC. The
+// first offset has no start and the last no end, so they cover any time.
+func zonesSince(loc *time.Location, first, now time.Time) string {
+	var zones []string
+	starts := "NULL"
+	for t := first.In(loc); ; {
+		_, offset := t.Zone()
+		_, end := t.ZoneBounds()
+		if end.IsZero() || end.After(now) {
+			return strings.Join(append(zones, fmt.Sprintf("(%s, NULL, %d)", starts, offset)), ", ")
+		}
+		zones = append(zones, fmt.Sprintf("(%s, %d, %d)", starts, end.Unix(), offset))
+		starts, t = fmt.Sprint(end.Unix()), end
+	}
+}
+
+// StreakFreezes returns the days the user froze, oldest first.
+func (r *StudyRepository) StreakFreezes(ctx context.Context, userID int) ([]string, error) {
+	defer observe(ctx, "study", "StreakFreezes")()
+
+	rows, err := queryStatement(ctx, r.db, "streak_freezes.select",
+		"SELECT day FROM streak_freezes WHERE user_id = ? ORDER BY day", userID)
+	if err != nil {
+		return nil, fmt.Errorf("error querying streak freezes: %w", err)
+	}
+	defer rows.Close()
+
+	var days []string
+	for rows.Next() {
+		var day string
+		if err := rows.Scan(&day); err != nil {
+			return nil, fmt.Errorf("error scanning streak freeze: %w", err)
+		}
+		days = append(days, day)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating streak freezes: %w", err)
+	}
+	return days, nil
+}
+
+// AddStreakFreeze freezes a day of the user; freezing it again does nothing.
+func (r *StudyRepository) AddStreakFreeze(ctx context.Context, userID int, day string) error {
+	defer observe(ctx, "study", "AddStreakFreeze")()
+
+	_, err := execStatement(ctx, r.db, "streak_freezes.insert",
+		"INSERT OR IGNORE INTO streak_freezes (user_id, day) VALUES (?, ?)", userID, day)
+	if err != nil {
+		return fmt.Errorf("error freezing day: %w", err)
+	}
+	return nil
+}
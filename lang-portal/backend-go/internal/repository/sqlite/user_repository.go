@@ -0,0 +1,219 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/mattn/go-sqlite3"
+	"github.com/souheilbenslama/free-genai-bootcamp-2025/lang-portal/backend-go/internal/models"
+	"github.com/souheilbenslama/free-genai-bootcamp-2025/lang-portal/backend-go/internal/pagination"
+	"github.com/souheilbenslama/free-genai-bootcamp-2025/lang-portal/backend-go/internal/repository"
+)
+
+type UserRepository struct {
+	db *sql.DB
+}
+
+func NewUserRepository(db *sql.DB) *UserRepository {
+	return &UserRepository{db: db}
+}
+
+func (r *UserRepository) CreateUser(ctx context.Context, user *models.User) error {
+	defer observe(ctx, "user", "CreateUser")()
+
+	if user.Role == "" {
+		user.Role = "learner"
+	}
+	if user.Timezone == "" {
+		user.Timezone = "UTC"
+	}
+
+	createdAt := time.Now().UTC()
+	result, err := execStatement(ctx, r.db, "users.insert",
+		"INSERT INTO users (email, name, role, timezone, password_hash, created_at) VALUES (?, ?, ?, ?, ?, ?)",
+		user.Email, user.Name, user.Role, user.Timezone, user.PasswordHash, createdAt)
+	if err != nil {
+		var sqliteErr sqlite3.Error
+		if errors.As(err, &sqliteErr) && sqliteErr.ExtendedCode == sqlite3.ErrConstraintUnique {
+			return repository.ErrEmailTaken
+		}
+		return fmt.Errorf("error creating user: %w", err)
+	}
+
+	id, err := result.LastInsertId()
+	if err != nil {
+		return fmt.Errorf("error getting last insert id: %w", err)
+	}
+
+	user.ID = int(id)
+	user.CreatedAt = createdAt
+	return nil
+}
+
+func (r *UserRepository) GetUserByID(ctx context.Context, id int) (*models.User, error) {
+	defer observe(ctx, "user", "GetUserByID")()
+
+	return r.scanUser(queryRowStatement(ctx, r.db, "users.select_by_id",
+		"SELECT id, email, name, role, timezone, password_hash, created_at FROM users WHERE id = ?", id))
+}
+
+func (r *UserRepository) GetUserByEmail(ctx context.Context, email string) (*models.User, error) {
+	defer observe(ctx, "user", "GetUserByEmail")()
+
+	return r.scanUser(queryRowStatement(ctx, r.db, "users.select_by_email",
+		"SELECT id, email, name, role, timezone, password_hash, created_at FROM users WHERE email = ?", email))
+}
+
+func (r *UserRepository) scanUser(row *sql.Row) (*models.User, error) {
+	var user models.User
+	if err := row.Scan(&user.ID, &user.Email, &user.Name, &user.Role, &user.Timezone, &user.PasswordHash, &user.CreatedAt); err != nil {
+		return nil, err
+	}
+	return &user, nil
+}
+
+func (r *UserRepository) ListUsers(ctx context.Context, params pagination.Params) ([]models.User, pagination.Page, error) {
+	defer observe(ctx, "user", "ListUsers")()
+
+	var total int
+	if err := queryRowStatement(ctx, r.db, "users.count", "SELECT COUNT(*) FROM users").Scan(&total); err != nil {
+		return nil, pagination.Page{}, fmt.Errorf("error counting users: %w", err)
+	}
+
+	// Fetch one extra row to find out whether another page follows
+	rows, err := queryStatement(ctx, r.db, "users.list",
+		"SELECT id, email, name, role, timezone, created_at FROM users WHERE id > ? ORDER BY id LIMIT ?",
+		params.AfterID, params.Limit+1)
+	if err != nil {
+		return nil, pagination.Page{}, fmt.Errorf("error querying users: %w", err)
+	}
+	defer rows.Close()
+
+	users := []models.User{}
+	for rows.Next() {
+		var user models.User
+		if err := rows.Scan(&user.ID, &user.Email, &user.Name, &user.Role, &user.Timezone, &user.CreatedAt); err != nil {
+			return nil, pagination.Page{}, fmt.Errorf("error scanning user: %w", err)
+		}
+		users = append(users, user)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, pagination.Page{}, fmt.Errorf("error iterating users: %w", err)
+	}
+
+	users, page := pagination.Trim(users, params, total, func(user models.User) int { return user.ID })
+	return users, page, nil
+}
+
+// SetRole changes the role of a user and returns the updated user. Demoting
+// the only admin fails with ErrLastAdmin so the portal cannot lock itself out.
+func (r *UserRepository) SetRole(ctx context.Context, id int, role string) (*models.User, error) {
+	defer observe(ctx, "user", "SetRole")()
+
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, fmt.Errorf("error beginning transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	var current string
+	err = queryRowStatement(ctx, tx, "users.select_role", "SELECT role FROM users WHERE id = ?", id).Scan(&current)
+	if err != nil {
+		return nil, err
+	}
+
+	if current == "admin" && role != "admin" {
+		var admins int
+		err = queryRowStatement(ctx, tx, "users.count_admins", "SELECT COUNT(*) FROM users WHERE role = 'admin'").Scan(&admins)
+		if err != nil {
+			return nil, fmt.Errorf("error counting admins: %w", err)
+		}
+		if admins <= 1 {
+			return nil, repository.ErrLastAdmin
+		}
+	}
+
+	var user models.User
+	err = queryRowStatement(ctx, tx, "users.update_role", `
+		UPDATE users SET role = ? WHERE id = ?
+		RETURNING id, email, name, role, timezone, created_at
+	`, role, id).Scan(&user.ID, &user.Email, &user.Name, &user.Role, &user.Timezone, &user.CreatedAt)
+	if err != nil {
+		return nil, fmt.Errorf("error updating role: %w", err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("error committing transaction: %w", err)
+	}
+	return &user, nil
+}
+
+// SetTimezone changes the time zone of a user and returns the updated user.
+func (r *UserRepository) SetTimezone(ctx context.Context, id int, timezone string) (*models.User, error) {
+	defer observe(ctx, "user", "SetTimezone")()
+
+	var user models.User
+	err := queryRowStatement(ctx, r.db, "users.update_timezone", `
+		UPDATE users SET timezone = ? WHERE id = ?
+		RETURNING id, email, name, role, timezone, created_at
+	`, timezone, id).Scan(&user.ID, &user.Email, &user.Name, &user.Role, &user.Timezone, &user.CreatedAt)
+	if err != nil {
+		return nil, err
+	}
+	return &user, nil
+}
+
+// PromoteAdmin gives the admin role to the account registered with email.
+// It bootstraps the first admin and returns sql.ErrNoRows when nobody
+// registered with the email.
+func (r *UserRepository) PromoteAdmin(ctx context.Context, email string) (*models.User, error) {
+	defer observe(ctx, "user", "PromoteAdmin")()
+
+	var user models.User
+	err := queryRowStatement(ctx, r.db, "users.promote_admin", `
+		UPDATE users SET role = 'admin' WHERE email = ?
+		RETURNING id, email, name, role, timezone, created_at
+	`, email).Scan(&user.ID, &user.Email, &user.Name, &user.Role, &user.Timezone, &user.CreatedAt)
+	if err != nil {
+		return nil, err
+	}
+	return &user, nil
+}
+
+func (r *UserRepository) CreateRefreshToken(ctx context.Context, userID int, hash string, expiresAt time.Time) error {
+	defer observe(ctx, "user", "CreateRefreshToken")()
+
+	_, err := execStatement(ctx, r.db, "refresh_tokens.insert",
+		"INSERT INTO refresh_tokens (user_id, token_hash, expires_at, created_at) VALUES (?, ?, ?, ?)",
+		userID, hash, expiresAt.UTC(), time.Now().UTC())
+	if err != nil {
+		return fmt.Errorf("error storing refresh token: %w", err)
+	}
+	return nil
+}
+
+// RevokeRefreshToken marks a live refresh token as used and returns its
+// owner. A token can be revoked only once, so a stolen token that has already
+// been rotated is useless.
+func (r *UserRepository) RevokeRefreshToken(ctx context.Context, hash string) (int, error) {
+	defer observe(ctx, "user", "RevokeRefreshToken")()
+
+	now := time.Now().UTC()
+	var userID int
+	err := queryRowStatement(ctx, r.db, "refresh_tokens.revoke", `
+		UPDATE refresh_tokens
+		SET revoked_at = ?
+		WHERE token_hash = ? AND revoked_at IS NULL AND expires_at > ?
+		RETURNING user_id
+	`, now, hash, now).Scan(&userID)
+	if err == sql.ErrNoRows {
+		return 0, repository.ErrInvalidRefreshToken
+	}
+	if err != nil {
+		return 0, fmt.Errorf("error revoking refresh token: %w", err)
+	}
+	return userID, nil
+}
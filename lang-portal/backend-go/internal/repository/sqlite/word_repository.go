@@ -6,6 +6,7 @@ import (
 	"fmt"
 
 	"github.com/souheilbenslama/free-genai-bootcamp-2025/lang-portal/backend-go/internal/models"
+	"github.com/souheilbenslama/free-genai-bootcamp-2025/lang-portal/backend-go/internal/pagination"
 )
 
 type WordRepository struct {
@@ -19,8 +20,10 @@ func NewWordRepository(db *sql.DB) *WordRepository {
 }
 
 func (r *WordRepository) GetWord(ctx context.Context, id int) (*models.Word, error) {
+	defer observe(ctx, "word", "GetWord")()
+
 	var word models.Word
-	err := r.db.QueryRowContext(ctx,
+	err := queryRowStatement(ctx, r.db, "words.select_by_id",
 		"SELECT id, german, english, parts FROM words WHERE id = ?",
 		id).Scan(&word.ID, &word.German, &word.English, &word.Parts)
 	if err != nil {
@@ -29,28 +32,43 @@ func (r *WordRepository) GetWord(ctx context.Context, id int) (*models.Word, err
 	return &word, nil
 }
 
-func (r *WordRepository) ListWords(ctx context.Context, offset, limit int) ([]models.Word, error) {
-	rows, err := r.db.QueryContext(ctx,
-		"SELECT id, german, english, parts FROM words LIMIT ? OFFSET ?",
-		limit, offset)
+func (r *WordRepository) ListWords(ctx context.Context, params pagination.Params) ([]models.Word, pagination.Page, error) {
+	defer observe(ctx, "word", "ListWords")()
+
+	var total int
+	if err := queryRowStatement(ctx, r.db, "words.count", "SELECT COUNT(*) FROM words").Scan(&total); err != nil {
+		return nil, pagination.Page{}, fmt.Errorf("error counting words: %w", err)
+	}
+
+	// Fetch one extra row to find out whether another page follows
+	rows, err := queryStatement(ctx, r.db, "words.list",
+		"SELECT id, german, english, parts FROM words WHERE id > ? ORDER BY id LIMIT ?",
+		params.AfterID, params.Limit+1)
 	if err != nil {
-		return nil, err
+		return nil, pagination.Page{}, err
 	}
 	defer rows.Close()
 
-	var words []models.Word
+	words := []models.Word{}
 	for rows.Next() {
 		var word models.Word
 		if err := rows.Scan(&word.ID, &word.German, &word.English, &word.Parts); err != nil {
-			return nil, err
+			return nil, pagination.Page{}, err
 		}
 		words = append(words, word)
 	}
-	return words, nil
+	if err := rows.Err(); err != nil {
+		return nil, pagination.Page{}, err
+	}
+
+	words, page := pagination.Trim(words, params, total, func(word models.Word) int { return word.ID })
+	return words, page, nil
 }
 
 func (r *WordRepository) CreateWord(ctx context.Context, word *models.Word) error {
-	result, err := r.db.ExecContext(ctx,
+	defer observe(ctx, "word", "CreateWord")()
+
+	result, err := execStatement(ctx, r.db, "words.insert",
 		"INSERT INTO words (german, english, parts) VALUES (?, ?, ?)",
 		word.German, word.English, word.Parts)
 	if err != nil {
@@ -67,6 +85,8 @@ func (r *WordRepository) CreateWord(ctx context.Context, word *models.Word) erro
 }
 
 func (r *WordRepository) UpdateWord(ctx context.Context, word *models.Word) error {
+	defer observe(ctx, "word", "UpdateWord")()
+
 	query := `
 		UPDATE words
 		SET german = ?, english = ?, parts = ?
@@ -74,7 +94,7 @@ func (r *WordRepository) UpdateWord(ctx context.Context, word *models.Word) erro
 		RETURNING id, german, english, parts
 	`
 
-	err := r.db.QueryRowContext(ctx,
+	err := queryRowStatement(ctx, r.db, "words.update",
 		query,
 		word.German,
 		word.English,
@@ -95,14 +115,16 @@ func (r *WordRepository) UpdateWord(ctx context.Context, word *models.Word) erro
 }
 
 func (r *WordRepository) DeleteWord(ctx context.Context, id int) error {
+	defer observe(ctx, "word", "DeleteWord")()
+
 	// First delete any associations in words_groups
-	_, err := r.db.ExecContext(ctx, "DELETE FROM words_groups WHERE word_id = ?", id)
+	_, err := execStatement(ctx, r.db, "words_groups.delete_by_word", "DELETE FROM words_groups WHERE word_id = ?", id)
 	if err != nil {
 		return fmt.Errorf("error deleting word associations: %w", err)
 	}
 
 	// Then delete the word
-	result, err := r.db.ExecContext(ctx, "DELETE FROM words WHERE id = ?", id)
+	result, err := execStatement(ctx, r.db, "words.delete", "DELETE FROM words WHERE id = ?", id)
 	if err != nil {
 		return fmt.Errorf("error deleting word: %w", err)
 	}
@@ -1,11 +1,13 @@
 package seeder
 
 import (
+	"crypto/sha256"
 	"database/sql"
+	"encoding/hex"
 	"encoding/json"
 	"fmt"
+	"io/fs"
 	"os"
-	"path/filepath"
 )
 
 type WordData struct {
@@ -32,35 +34,90 @@ type GroupsFile struct {
 	Groups []GroupData `json:"groups"`
 }
 
-func LoadSeedData(db *sql.DB, seedDir string) error {
-	// Load and insert words
-	wordsFile := filepath.Join(seedDir, "words.json")
-	words, err := loadWordsFromJSON(wordsFile)
+// LoadSeedData loads the seed files found in seedDir.
+func LoadSeedData(db *sql.DB, seedDir string) (bool, error) {
+	return LoadSeedFS(db, os.DirFS(seedDir))
+}
+
+// LoadSeedFS loads words.json and groups.json from fsys and reports whether
+// it did. Seed data is only loaded into a database that has not been seeded
+// yet; the run is recorded in seed_runs together with a checksum of the
+// files, in the same transaction as the data so a failed run leaves nothing
+// behind.
+func LoadSeedFS(db *sql.DB, fsys fs.FS) (bool, error) {
+	checksum, err := seedChecksum(fsys, "words.json", "groups.json")
+	if err != nil {
+		return false, fmt.Errorf("failed to read seed files: %w", err)
+	}
+
+	words, err := loadWordsFromJSON(fsys, "words.json")
+	if err != nil {
+		return false, fmt.Errorf("failed to load words: %w", err)
+	}
+	groups, err := loadGroupsFromJSON(fsys, "groups.json")
+	if err != nil {
+		return false, fmt.Errorf("failed to load groups: %w", err)
+	}
+
+	tx, err := db.Begin()
 	if err != nil {
-		return fmt.Errorf("failed to load words: %w", err)
+		return false, fmt.Errorf("failed to begin transaction: %w", err)
 	}
+	defer tx.Rollback()
 
-	wordIDs, err := insertWords(db, words)
+	applied, err := Applied(tx)
 	if err != nil {
-		return fmt.Errorf("failed to insert words: %w", err)
+		return false, err
+	}
+	if applied {
+		return false, nil
 	}
 
-	// Load and insert groups
-	groupsFile := filepath.Join(seedDir, "groups.json")
-	groups, err := loadGroupsFromJSON(groupsFile)
+	wordIDs, err := insertWords(tx, words)
 	if err != nil {
-		return fmt.Errorf("failed to load groups: %w", err)
+		return false, fmt.Errorf("failed to insert words: %w", err)
 	}
 
-	if err := insertGroups(db, groups, words, wordIDs); err != nil {
-		return fmt.Errorf("failed to insert groups: %w", err)
+	if err := insertGroups(tx, groups, words, wordIDs); err != nil {
+		return false, fmt.Errorf("failed to insert groups: %w", err)
 	}
 
-	return nil
+	if _, err := tx.Exec("INSERT INTO seed_runs (checksum) VALUES (?)", checksum); err != nil {
+		return false, fmt.Errorf("failed to record seed run: %w", err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return false, fmt.Errorf("failed to commit seed data: %w", err)
+	}
+	return true, nil
+}
+
+// Applied reports whether seed data has been loaded into the database q
+// reads.
+func Applied(q interface {
+	QueryRow(query string, args ...any) *sql.Row
+}) (bool, error) {
+	var applied bool
+	if err := q.QueryRow("SELECT EXISTS(SELECT 1 FROM seed_runs)").Scan(&applied); err != nil {
+		return false, fmt.Errorf("failed to check seed runs: %w", err)
+	}
+	return applied, nil
+}
+
+func seedChecksum(fsys fs.FS, filenames ...string) (string, error) {
+	hash := sha256.New()
+	for _, filename := range filenames {
+		data, err := fs.ReadFile(fsys, filename)
+		if err != nil {
+			return "", err
+		}
+		hash.Write(data)
+	}
+	return hex.EncodeToString(hash.Sum(nil)), nil
 }
 
-func loadWordsFromJSON(filename string) ([]WordData, error) {
-	data, err := os.ReadFile(filename)
+func loadWordsFromJSON(fsys fs.FS, filename string) ([]WordData, error) {
+	data, err := fs.ReadFile(fsys, filename)
 	if err != nil {
 		return nil, err
 	}
@@ -73,8 +130,8 @@ func loadWordsFromJSON(filename string) ([]WordData, error) {
 	return wordsFile.Words, nil
 }
 
-func loadGroupsFromJSON(filename string) ([]GroupData, error) {
-	data, err := os.ReadFile(filename)
+func loadGroupsFromJSON(fsys fs.FS, filename string) ([]GroupData, error) {
+	data, err := fs.ReadFile(fsys, filename)
 	if err != nil {
 		return nil, err
 	}
@@ -87,7 +144,7 @@ func loadGroupsFromJSON(filename string) ([]GroupData, error) {
 	return groupsFile.Groups, nil
 }
 
-func insertWords(db *sql.DB, words []WordData) (map[string]int64, error) {
+func insertWords(tx *sql.Tx, words []WordData) (map[string]int64, error) {
 	wordIDs := make(map[string]int64)
 	
 	for _, word := range words {
@@ -96,7 +153,7 @@ func insertWords(db *sql.DB, words []WordData) (map[string]int64, error) {
 			return nil, err
 		}
 
-		result, err := db.Exec(
+		result, err := tx.Exec(
 			"INSERT INTO words (german, english, parts) VALUES (?, ?, ?)",
 			word.German,
 			word.English,
@@ -117,10 +174,10 @@ func insertWords(db *sql.DB, words []WordData) (map[string]int64, error) {
 	return wordIDs, nil
 }
 
-func insertGroups(db *sql.DB, groups []GroupData, words []WordData, wordIDs map[string]int64) error {
+func insertGroups(tx *sql.Tx, groups []GroupData, words []WordData, wordIDs map[string]int64) error {
 	for _, group := range groups {
 		// Insert group
-		result, err := db.Exec("INSERT INTO groups (name) VALUES (?)", group.Name)
+		result, err := tx.Exec("INSERT INTO groups (name) VALUES (?)", group.Name)
 		if err != nil {
 			return err
 		}
@@ -137,7 +194,7 @@ func insertGroups(db *sql.DB, groups []GroupData, words []WordData, wordIDs map[
 				return fmt.Errorf("word %q not found in words list", wordGerman)
 			}
 
-			_, err = db.Exec(
+			_, err = tx.Exec(
 				"INSERT INTO words_groups (word_id, group_id) VALUES (?, ?)",
 				wordID,
 				groupID,
@@ -0,0 +1,13 @@
+package seeder_test
+
+import (
+	"testing"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+func TestSeeder(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "Seeder Suite")
+}
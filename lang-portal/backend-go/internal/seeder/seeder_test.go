@@ -0,0 +1,93 @@
+package seeder_test
+
+import (
+	"context"
+	"database/sql"
+	"io/fs"
+	"os"
+	"testing/fstest"
+
+	_ "github.com/mattn/go-sqlite3"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/souheilbenslama/free-genai-bootcamp-2025/lang-portal/backend-go/database"
+	"github.com/souheilbenslama/free-genai-bootcamp-2025/lang-portal/backend-go/internal/migrate"
+	"github.com/souheilbenslama/free-genai-bootcamp-2025/lang-portal/backend-go/internal/seeder"
+)
+
+var _ = Describe("LoadSeedFS", func() {
+	var db *sql.DB
+
+	BeforeEach(func() {
+		tmpfile, err := os.CreateTemp("", "seeder-*.db")
+		Expect(err).NotTo(HaveOccurred())
+		DeferCleanup(os.Remove, tmpfile.Name())
+
+		db, err = sql.Open("sqlite3", tmpfile.Name())
+		Expect(err).NotTo(HaveOccurred())
+		DeferCleanup(db.Close)
+	})
+
+	count := func(table string) int {
+		var n int
+		Expect(db.QueryRow("SELECT COUNT(*) FROM " + table).Scan(&n)).To(Succeed())
+		return n
+	}
+
+	It("loads the seed data once", func() {
+		_, err := migrate.Apply(context.Background(), db, database.Migrations())
+		Expect(err).NotTo(HaveOccurred())
+
+		loaded, err := seeder.LoadSeedFS(db, database.Seed())
+		Expect(err).NotTo(HaveOccurred())
+		Expect(loaded).To(BeTrue())
+		words, groups := count("words"), count("groups")
+		Expect(words).To(BeNumerically(">", 0))
+
+		loaded, err = seeder.LoadSeedFS(db, database.Seed())
+		Expect(err).NotTo(HaveOccurred())
+		Expect(loaded).To(BeFalse())
+		Expect(count("words")).To(Equal(words))
+		Expect(count("groups")).To(Equal(groups))
+	})
+
+	It("leaves a database seeded before seed runs were recorded alone", func() {
+		// Older releases created the schema and seeded without seed_runs
+		initial, err := fs.ReadFile(database.Migrations(), "001_initial_schema.sql")
+		Expect(err).NotTo(HaveOccurred())
+		_, err = db.Exec(string(initial))
+		Expect(err).NotTo(HaveOccurred())
+		_, err = db.Exec(`INSERT INTO words (german, english, parts) VALUES ('Haus', 'house', '{}')`)
+		Expect(err).NotTo(HaveOccurred())
+
+		_, err = migrate.Apply(context.Background(), db, database.Migrations())
+		Expect(err).NotTo(HaveOccurred())
+		applied, err := seeder.Applied(db)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(applied).To(BeTrue())
+
+		loaded, err := seeder.LoadSeedFS(db, database.Seed())
+		Expect(err).NotTo(HaveOccurred())
+		Expect(loaded).To(BeFalse())
+		Expect(count("words")).To(Equal(1))
+	})
+
+	It("leaves nothing behind when loading fails", func() {
+		_, err := migrate.Apply(context.Background(), db, database.Migrations())
+		Expect(err).NotTo(HaveOccurred())
+
+		seed := fstest.MapFS{
+			"words.json":  {Data: []byte(`{"words": [{"german": "Haus", "english": "house"}]}`)},
+			"groups.json": {Data: []byte(`{"groups": [{"name": "Home", "words": ["Haus", "Tür"]}]}`)},
+		}
+		_, err = seeder.LoadSeedFS(db, seed)
+		Expect(err).To(MatchError(ContainSubstring("Tür")))
+
+		Expect(count("words")).To(BeZero())
+		Expect(count("groups")).To(BeZero())
+		applied, err := seeder.Applied(db)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(applied).To(BeFalse())
+	})
+})
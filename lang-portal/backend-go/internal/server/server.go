@@ -0,0 +1,126 @@
+package server
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/souheilbenslama/free-genai-bootcamp-2025/lang-portal/backend-go/internal/config"
+)
+
+// Worker is a background task that runs until its context is cancelled.
+type Worker func(ctx context.Context)
+
+// Server owns the HTTP server, the background workers and the database handle,
+// and tears them down in that order when it stops.
+type Server struct {
+	http            *http.Server
+	db              *sql.DB
+	shutdownTimeout time.Duration
+	workers         map[string]Worker
+}
+
+func New(cfg *config.Config, handler http.Handler, db *sql.DB) *Server {
+	return &Server{
+		http: &http.Server{
+			Addr:         cfg.ListenAddr,
+			Handler:      handler,
+			ReadTimeout:  cfg.ReadTimeout,
+			WriteTimeout: cfg.WriteTimeout,
+			IdleTimeout:  cfg.IdleTimeout,
+		},
+		db:              db,
+		shutdownTimeout: cfg.ShutdownTimeout,
+		workers:         map[string]Worker{},
+	}
+}
+
+// AddWorker registers a background task started by Run and stopped on shutdown.
+func (s *Server) AddWorker(name string, worker Worker) {
+	s.workers[name] = worker
+}
+
+// Run listens on the configured address and serves until ctx is cancelled.
+func (s *Server) Run(ctx context.Context) error {
+	ln, err := net.Listen("tcp", s.http.Addr)
+	if err != nil {
+		return fmt.Errorf("error listening on %s: %w", s.http.Addr, err)
+	}
+	return s.Serve(ctx, ln)
+}
+
+// Serve accepts connections on ln until ctx is cancelled, then drains in-flight
+// requests within the shutdown timeout, stops the workers, checkpoints the WAL
+// and closes the database.
+func (s *Server) Serve(ctx context.Context, ln net.Listener) error {
+	workerCtx, stopWorkers := context.WithCancel(context.Background())
+	defer stopWorkers()
+
+	var workers sync.WaitGroup
+	for name, worker := range s.workers {
+		workers.Add(1)
+		go func(name string, worker Worker) {
+			defer workers.Done()
+			slog.Info("worker started", slog.String("worker", name))
+			worker(workerCtx)
+			slog.Info("worker stopped", slog.String("worker", name))
+		}(name, worker)
+	}
+
+	serveErr := make(chan error, 1)
+	go func() {
+		serveErr <- s.http.Serve(ln)
+	}()
+	slog.Info("server listening", slog.String("addr", ln.Addr().String()))
+
+	var runErr error
+	select {
+	case <-ctx.Done():
+		slog.Info("shutdown requested, draining connections")
+	case err := <-serveErr:
+		runErr = fmt.Errorf("error serving HTTP: %w", err)
+	}
+
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), s.shutdownTimeout)
+	defer cancel()
+
+	if err := s.http.Shutdown(shutdownCtx); err != nil {
+		runErr = errors.Join(runErr, fmt.Errorf("error draining connections: %w", err))
+		s.http.Close()
+	}
+
+	stopWorkers()
+	stopped := make(chan struct{})
+	go func() {
+		workers.Wait()
+		close(stopped)
+	}()
+	select {
+	case <-stopped:
+	case <-shutdownCtx.Done():
+		runErr = errors.Join(runErr, errors.New("background workers did not stop before the shutdown deadline"))
+	}
+
+	if err := s.closeDB(); err != nil {
+		runErr = errors.Join(runErr, err)
+	}
+
+	slog.Info("server stopped")
+	return runErr
+}
+
+func (s *Server) closeDB() error {
+	if _, err := s.db.Exec("PRAGMA wal_checkpoint(TRUNCATE)"); err != nil {
+		slog.Warn("WAL checkpoint failed", slog.Any("error", err))
+	}
+	if err := s.db.Close(); err != nil {
+		return fmt.Errorf("error closing database: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,96 @@
+package server_test
+
+import (
+	"context"
+	"database/sql"
+	"net"
+	"net/http"
+	"path/filepath"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"github.com/souheilbenslama/free-genai-bootcamp-2025/lang-portal/backend-go/internal/config"
+	"github.com/souheilbenslama/free-genai-bootcamp-2025/lang-portal/backend-go/internal/repository/sqlite"
+	"github.com/souheilbenslama/free-genai-bootcamp-2025/lang-portal/backend-go/internal/server"
+)
+
+var _ = Describe("Server", func() {
+	var (
+		db  *sql.DB
+		cfg *config.Config
+		ln  net.Listener
+	)
+
+	BeforeEach(func() {
+		var err error
+		db, err = sqlite.Open(filepath.Join(GinkgoT().TempDir(), "server.db"))
+		Expect(err).NotTo(HaveOccurred())
+
+		cfg = config.Default()
+		cfg.ShutdownTimeout = 2 * time.Second
+
+		ln, err = net.Listen("tcp", "127.0.0.1:0")
+		Expect(err).NotTo(HaveOccurred())
+	})
+
+	It("drains in-flight requests, stops workers and closes the database on shutdown", func() {
+		started := make(chan struct{})
+		handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			close(started)
+			time.Sleep(200 * time.Millisecond)
+			w.WriteHeader(http.StatusNoContent)
+		})
+
+		srv := server.New(cfg, handler, db)
+		workerStopped := make(chan struct{})
+		srv.AddWorker("test", func(ctx context.Context) {
+			<-ctx.Done()
+			close(workerStopped)
+		})
+
+		ctx, cancel := context.WithCancel(context.Background())
+		done := make(chan error, 1)
+		go func() { done <- srv.Serve(ctx, ln) }()
+
+		status := make(chan int, 1)
+		go func() {
+			resp, err := http.Get("http://" + ln.Addr().String())
+			if err != nil {
+				status <- 0
+				return
+			}
+			resp.Body.Close()
+			status <- resp.StatusCode
+		}()
+
+		Eventually(started).Should(BeClosed())
+		cancel()
+
+		Eventually(status).Should(Receive(Equal(http.StatusNoContent)))
+		Eventually(done).Should(Receive(BeNil()))
+		Expect(workerStopped).To(BeClosed())
+		Expect(db.Ping()).To(MatchError(ContainSubstring("database is closed")))
+	})
+
+	It("gives up on requests that outlive the shutdown timeout", func() {
+		cfg.ShutdownTimeout = 50 * time.Millisecond
+		started := make(chan struct{})
+		handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			close(started)
+			time.Sleep(time.Second)
+		})
+
+		srv := server.New(cfg, handler, db)
+		ctx, cancel := context.WithCancel(context.Background())
+		done := make(chan error, 1)
+		go func() { done <- srv.Serve(ctx, ln) }()
+
+		go http.Get("http://" + ln.Addr().String())
+		Eventually(started).Should(BeClosed())
+		cancel()
+
+		Eventually(done).Should(Receive(MatchError(ContainSubstring("error draining connections"))))
+		Expect(db.Ping()).To(HaveOccurred())
+	})
+})
@@ -0,0 +1,148 @@
+// Package spa serves a built single page application: static files as they
+// are, and index.html for every other path so the client side router can
+// handle deep links.
+package spa
+
+import (
+	"bytes"
+	"io"
+	"io/fs"
+	"mime"
+	"net/http"
+	"path"
+	"strconv"
+	"strings"
+)
+
+const (
+	// ImmutableCache is sent for files under assets/, which the bundler names
+	// after a hash of their content.
+	ImmutableCache = "public, max-age=31536000, immutable"
+	// RevalidateCache is sent for everything else, index.html in particular,
+	// so a deploy is picked up on the next load.
+	RevalidateCache = "no-cache"
+)
+
+// encodings are the precompressed variants looked for next to each file, in
+// order of preference.
+var encodings = []struct{ coding, ext string }{
+	{"br", ".br"},
+	{"gzip", ".gz"},
+}
+
+type handler struct {
+	files fs.FS
+}
+
+// Handler serves the bundle in files. Missing paths without a file extension
+// get index.html; missing files with one get 404 so a stale asset URL does
+// not load a page in its place. Only GET and HEAD are answered.
+func Handler(files fs.FS) http.Handler {
+	return &handler{files: files}
+}
+
+func (h *handler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet && r.Method != http.MethodHead {
+		w.Header().Set("Allow", "GET, HEAD")
+		http.Error(w, http.StatusText(http.StatusMethodNotAllowed), http.StatusMethodNotAllowed)
+		return
+	}
+
+	name := strings.TrimPrefix(path.Clean("/"+r.URL.Path), "/")
+	if name == "" {
+		name = "index.html"
+	}
+	if h.serveFile(w, r, name) {
+		return
+	}
+	if path.Ext(name) != "" || !h.serveFile(w, r, "index.html") {
+		http.NotFound(w, r)
+	}
+}
+
+// serveFile writes name, or its best precompressed variant the client
+// accepts, and reports whether name exists.
+func (h *handler) serveFile(w http.ResponseWriter, r *http.Request, name string) bool {
+	info, err := fs.Stat(h.files, name)
+	if err != nil || info.IsDir() {
+		return false
+	}
+
+	header := w.Header()
+	if strings.HasPrefix(name, "assets/") {
+		header.Set("Cache-Control", ImmutableCache)
+	} else {
+		header.Set("Cache-Control", RevalidateCache)
+	}
+	// The type follows the original file, not the compressed variant
+	ctype := mime.TypeByExtension(path.Ext(name))
+	if ctype == "" {
+		ctype = "application/octet-stream"
+	}
+	header.Set("Content-Type", ctype)
+
+	served := name
+	varies := false
+	for _, enc := range encodings {
+		if _, err := fs.Stat(h.files, name+enc.ext); err != nil {
+			continue
+		}
+		varies = true
+		if accepts(r.Header.Get("Accept-Encoding"), enc.coding) {
+			header.Set("Content-Encoding", enc.coding)
+			served = name + enc.ext
+			break
+		}
+	}
+	if varies {
+		header.Add("Vary", "Accept-Encoding")
+	}
+
+	content, err := open(h.files, served)
+	if err != nil {
+		header.Del("Content-Encoding")
+		http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+		return true
+	}
+	if closer, ok := content.(io.Closer); ok {
+		defer closer.Close()
+	}
+	http.ServeContent(w, r, name, info.ModTime(), content)
+	return true
+}
+
+// open returns name as a ReadSeeker, which http.ServeContent needs for range
+// requests. Files of embed.FS and os.DirFS are seekable already.
+func open(files fs.FS, name string) (io.ReadSeeker, error) {
+	f, err := files.Open(name)
+	if err != nil {
+		return nil, err
+	}
+	if rs, ok := f.(io.ReadSeeker); ok {
+		return rs, nil
+	}
+	defer f.Close()
+	data, err := io.ReadAll(f)
+	if err != nil {
+		return nil, err
+	}
+	return bytes.NewReader(data), nil
+}
+
+// accepts reports whether an Accept-Encoding header admits coding. A q value
+// of zero refuses it.
+func accepts(header, coding string) bool {
+	for _, part := range strings.Split(header, ",") {
+		name, params, _ := strings.Cut(strings.TrimSpace(part), ";")
+		if !strings.EqualFold(strings.TrimSpace(name), coding) {
+			continue
+		}
+		key, value, ok := strings.Cut(strings.TrimSpace(params), "=")
+		if !ok || strings.TrimSpace(key) != "q" {
+			return true
+		}
+		q, err := strconv.ParseFloat(strings.TrimSpace(value), 64)
+		return err == nil && q > 0
+	}
+	return false
+}
@@ -0,0 +1,130 @@
+package spa_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing/fstest"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/souheilbenslama/free-genai-bootcamp-2025/lang-portal/backend-go/internal/spa"
+)
+
+var _ = Describe("Handler", func() {
+	var handler http.Handler
+
+	BeforeEach(func() {
+		handler = spa.Handler(fstest.MapFS{
+			"index.html":                   {Data: []byte("<html>app</html>")},
+			"favicon.ico":                  {Data: []byte("icon")},
+			"assets/index-4f2a9c1b.js":     {Data: []byte("console.log('app')")},
+			"assets/index-4f2a9c1b.js.br":  {Data: []byte("brotli")},
+			"assets/index-4f2a9c1b.js.gz":  {Data: []byte("gzip")},
+			"assets/style-0c1d2e3f.css":    {Data: []byte("body {}")},
+			"assets/style-0c1d2e3f.css.gz": {Data: []byte("gzip css")},
+		})
+	})
+
+	serve := func(method, target string, headers ...string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(method, target, nil)
+		for i := 0; i+1 < len(headers); i += 2 {
+			req.Header.Set(headers[i], headers[i+1])
+		}
+		w := httptest.NewRecorder()
+		handler.ServeHTTP(w, req)
+		return w
+	}
+
+	It("serves index.html at the root without long caching", func() {
+		w := serve(http.MethodGet, "/")
+
+		Expect(w.Code).To(Equal(http.StatusOK))
+		Expect(w.Body.String()).To(Equal("<html>app</html>"))
+		Expect(w.Header().Get("Content-Type")).To(HavePrefix("text/html"))
+		Expect(w.Header().Get("Cache-Control")).To(Equal(spa.RevalidateCache))
+	})
+
+	It("serves static files as they are", func() {
+		w := serve(http.MethodGet, "/favicon.ico")
+
+		Expect(w.Code).To(Equal(http.StatusOK))
+		Expect(w.Body.String()).To(Equal("icon"))
+		Expect(w.Header().Get("Cache-Control")).To(Equal(spa.RevalidateCache))
+	})
+
+	It("caches hashed assets for good", func() {
+		w := serve(http.MethodGet, "/assets/index-4f2a9c1b.js")
+
+		Expect(w.Code).To(Equal(http.StatusOK))
+		Expect(w.Body.String()).To(Equal("console.log('app')"))
+		Expect(w.Header().Get("Cache-Control")).To(Equal(spa.ImmutableCache))
+		Expect(w.Header().Get("Content-Encoding")).To(BeEmpty())
+		Expect(w.Header().Values("Vary")).To(Equal([]string{"Accept-Encoding"}))
+	})
+
+	It("falls back to index.html for client side routes", func() {
+		for _, target := range []string{"/study", "/groups/3/words", "/assets/"} {
+			w := serve(http.MethodGet, target)
+
+			Expect(w.Code).To(Equal(http.StatusOK), target)
+			Expect(w.Body.String()).To(Equal("<html>app</html>"), target)
+			Expect(w.Header().Get("Cache-Control")).To(Equal(spa.RevalidateCache), target)
+		}
+	})
+
+	It("returns 404 for missing files instead of the page", func() {
+		w := serve(http.MethodGet, "/assets/index-00000000.js")
+
+		Expect(w.Code).To(Equal(http.StatusNotFound))
+	})
+
+	It("does not escape the bundle", func() {
+		w := serve(http.MethodGet, "/../../favicon.ico")
+
+		Expect(w.Code).To(Equal(http.StatusOK))
+		Expect(w.Body.String()).To(Equal("icon"))
+	})
+
+	It("answers HEAD and refuses other methods", func() {
+		Expect(serve(http.MethodHead, "/study").Code).To(Equal(http.StatusOK))
+
+		w := serve(http.MethodPost, "/study")
+		Expect(w.Code).To(Equal(http.StatusMethodNotAllowed))
+		Expect(w.Header().Get("Allow")).To(Equal("GET, HEAD"))
+	})
+
+	Context("with precompressed variants", func() {
+		It("prefers brotli", func() {
+			w := serve(http.MethodGet, "/assets/index-4f2a9c1b.js", "Accept-Encoding", "gzip, deflate, br")
+
+			Expect(w.Code).To(Equal(http.StatusOK))
+			Expect(w.Body.String()).To(Equal("brotli"))
+			Expect(w.Header().Get("Content-Encoding")).To(Equal("br"))
+			Expect(w.Header().Get("Content-Type")).To(HavePrefix("text/javascript"))
+			Expect(w.Header().Get("Cache-Control")).To(Equal(spa.ImmutableCache))
+		})
+
+		It("falls back to gzip", func() {
+			w := serve(http.MethodGet, "/assets/index-4f2a9c1b.js", "Accept-Encoding", "gzip")
+
+			Expect(w.Body.String()).To(Equal("gzip"))
+			Expect(w.Header().Get("Content-Encoding")).To(Equal("gzip"))
+		})
+
+		It("respects codings refused with q=0", func() {
+			w := serve(http.MethodGet, "/assets/index-4f2a9c1b.js", "Accept-Encoding", "br;q=0, gzip;q=0.5")
+
+			Expect(w.Body.String()).To(Equal("gzip"))
+			Expect(w.Header().Get("Content-Encoding")).To(Equal("gzip"))
+		})
+
+		It("serves the variant with the type of the original", func() {
+			w := serve(http.MethodGet, "/assets/style-0c1d2e3f.css", "Accept-Encoding", "gzip")
+
+			Expect(w.Code).To(Equal(http.StatusOK))
+			Expect(w.Body.String()).To(Equal("gzip css"))
+			Expect(w.Header().Get("Content-Type")).To(HavePrefix("text/css"))
+		})
+	})
+})
@@ -0,0 +1,13 @@
+package stats
+
+// MaxLevel is the level of the busiest days of a calendar.
+const MaxLevel = 4
+
+// Level grades count on a scale from 0 for nothing to MaxLevel in equal
+// steps up to busiest, the count of the busiest day.
+func Level(count, busiest int) int {
+	if count <= 0 || busiest <= 0 {
+		return 0
+	}
+	return min((count*MaxLevel+busiest-1)/busiest, MaxLevel)
+}
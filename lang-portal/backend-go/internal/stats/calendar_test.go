@@ -0,0 +1,23 @@
+package stats_test
+
+import (
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/souheilbenslama/free-genai-bootcamp-2025/lang-portal/backend-go/internal/stats"
+)
+
+var _ = DescribeTable("Level",
+	func(count, busiest, level int) {
+		Expect(stats.Level(count, busiest)).To(Equal(level))
+	},
+	Entry("nothing", 0, 10, 0),
+	Entry("nothing all year", 0, 0, 0),
+	Entry("a little", 1, 10, 1),
+	Entry("a quarter", 3, 12, 1),
+	Entry("just over a quarter", 4, 12, 2),
+	Entry("half", 6, 12, 2),
+	Entry("three quarters", 9, 12, 3),
+	Entry("the busiest day", 12, 12, 4),
+	Entry("the only day", 1, 1, 4),
+)
@@ -0,0 +1,109 @@
+// Package stats lays study activity out over time for charts.
+package stats
+
+import (
+	"errors"
+	"fmt"
+	"time"
+)
+
+// Metrics of a time series.
+const (
+	// MetricReviews counts the reviews of every drill.
+	MetricReviews = "reviews"
+	// MetricAccuracy is the percentage of correct reviews.
+	MetricAccuracy = "accuracy"
+	// MetricNewWords counts the words reviewed for the first time.
+	MetricNewWords = "new_words"
+	// MetricMinutes sums up the time spent in study sessions, counted on the
+	// day they started.
+	MetricMinutes = "minutes"
+)
+
+// Buckets of a time series. Weeks start on Monday.
+const (
+	BucketDay  = "day"
+	BucketWeek = "week"
+)
+
+// DateLayout is the layout of the dates of a time series.
+const DateLayout = "2006-01-02"
+
+// MaxBuckets caps the length of a time series.
+const MaxBuckets = 366
+
+var (
+	ErrInvalidRange = errors.New("from must not be after to")
+	ErrRangeTooLong = fmt.Errorf("a time series has at most %d buckets", MaxBuckets)
+)
+
+// Range is a series of buckets from the one holding From to the one holding
+// To. Both are days at midnight UTC.
+type Range struct {
+	Bucket string
+	From   time.Time
+	To     time.Time
+}
+
+// NewRange covers the days from through to in buckets, starting the first
+// bucket at its start.
+func NewRange(bucket string, from, to time.Time) (Range, error) {
+	r := Range{Bucket: bucket, From: BucketStart(bucket, Day(from)), To: Day(to)}
+	if r.To.Before(r.From) {
+		return Range{}, ErrInvalidRange
+	}
+	if r.Len() > MaxBuckets {
+		return Range{}, ErrRangeTooLong
+	}
+	return r, nil
+}
+
+// Day is the day of t at midnight UTC.
+func Day(t time.Time) time.Time {
+	y, m, d := t.Date()
+	return time.Date(y, m, d, 0, 0, 0, 0, time.UTC)
+}
+
+// BucketStart is the first day of the bucket holding day.
+func BucketStart(bucket string, day time.Time) time.Time {
+	if bucket != BucketWeek {
+		return day
+	}
+	// Weekday counts from Sunday, weeks start on Monday
+	return day.AddDate(0, 0, -(int(day.Weekday())+6)%7)
+}
+
+// Starts lists the first day of every bucket of r.
+func (r Range) Starts() []time.Time {
+	var starts []time.Time
+	for day := r.From; !day.After(r.To); day = r.next(day) {
+		starts = append(starts, day)
+	}
+	return starts
+}
+
+// Len is the number of buckets of r.
+func (r Range) Len() int {
+	days := int(r.To.Sub(r.From).Hours()/24) + 1
+	if r.Bucket == BucketWeek {
+		return (days + 6) / 7
+	}
+	return days
+}
+
+// Previous is the range of as many buckets right before r, to compare r
+// with.
+func (r Range) Previous() Range {
+	days := r.Len()
+	if r.Bucket == BucketWeek {
+		days *= 7
+	}
+	return Range{Bucket: r.Bucket, From: r.From.AddDate(0, 0, -days), To: r.To.AddDate(0, 0, -days)}
+}
+
+func (r Range) next(day time.Time) time.Time {
+	if r.Bucket == BucketWeek {
+		return day.AddDate(0, 0, 7)
+	}
+	return day.AddDate(0, 0, 1)
+}
@@ -0,0 +1,63 @@
+package stats_test
+
+import (
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/souheilbenslama/free-genai-bootcamp-2025/lang-portal/backend-go/internal/stats"
+)
+
+func day(s string) time.Time {
+	t, err := time.Parse(stats.DateLayout, s)
+	Expect(err).NotTo(HaveOccurred())
+	return t
+}
+
+var _ = Describe("Range", func() {
+	It("covers every day of a daily range", func() {
+		r, err := stats.NewRange(stats.BucketDay, day("2025-02-27"), day("2025-03-02"))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(r.Len()).To(Equal(4))
+		Expect(r.Starts()).To(Equal([]time.Time{day("2025-02-27"), day("2025-02-28"), day("2025-03-01"), day("2025-03-02")}))
+	})
+
+	It("starts weekly buckets on Monday", func() {
+		// 2025-01-01 is a Wednesday
+		r, err := stats.NewRange(stats.BucketWeek, day("2025-01-01"), day("2025-01-13"))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(r.From).To(Equal(day("2024-12-30")))
+		Expect(r.Starts()).To(Equal([]time.Time{day("2024-12-30"), day("2025-01-06"), day("2025-01-13")}))
+		Expect(r.Len()).To(Equal(3))
+	})
+
+	It("keeps a Sunday in the week before", func() {
+		Expect(stats.BucketStart(stats.BucketWeek, day("2025-01-05"))).To(Equal(day("2024-12-30")))
+		Expect(stats.BucketStart(stats.BucketWeek, day("2025-01-06"))).To(Equal(day("2025-01-06")))
+	})
+
+	It("compares with as many buckets right before", func() {
+		r, err := stats.NewRange(stats.BucketDay, day("2025-03-01"), day("2025-03-07"))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(r.Previous()).To(Equal(stats.Range{Bucket: stats.BucketDay, From: day("2025-02-22"), To: day("2025-02-28")}))
+
+		r, err = stats.NewRange(stats.BucketWeek, day("2025-01-06"), day("2025-01-15"))
+		Expect(err).NotTo(HaveOccurred())
+		previous := r.Previous()
+		Expect(previous.Starts()).To(Equal([]time.Time{day("2024-12-23"), day("2024-12-30")}))
+	})
+
+	It("rejects ranges that end before they start", func() {
+		_, err := stats.NewRange(stats.BucketDay, day("2025-03-02"), day("2025-03-01"))
+		Expect(err).To(MatchError(stats.ErrInvalidRange))
+	})
+
+	It("rejects ranges with too many buckets", func() {
+		_, err := stats.NewRange(stats.BucketDay, day("2024-01-01"), day("2025-01-01"))
+		Expect(err).To(MatchError(stats.ErrRangeTooLong))
+
+		_, err = stats.NewRange(stats.BucketWeek, day("2024-01-01"), day("2025-01-01"))
+		Expect(err).NotTo(HaveOccurred())
+	})
+})
@@ -0,0 +1,198 @@
+// Package streak counts the days in a row learners study, in their own time
+// zone, and lets them spend freeze tokens on the days they miss.
+package streak
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/souheilbenslama/free-genai-bootcamp-2025/lang-portal/backend-go/internal/models"
+)
+
+const (
+	// DaysPerFreeze is the number of study days in a row that earn a freeze.
+	DaysPerFreeze = 7
+	// MaxFreezes is the number of freezes a learner can hold.
+	MaxFreezes = 2
+)
+
+const dateLayout = "2006-01-02"
+
+var (
+	ErrNoFreezes = errors.New("no streak freezes left")
+	// ErrNotFreezable is returned for days other than today and yesterday,
+	// for days already studied or frozen, and for days that would not keep
+	// a streak alive.
+	ErrNotFreezable = errors.New("day cannot be frozen")
+)
+
+// Store is where the service finds the study of learners.
+type Store interface {
+	// Timezone returns the IANA time zone of the user.
+	Timezone(ctx context.Context, userID int) (string, error)
+	// StudyDays returns the days in loc, as dates at midnight UTC, on which
+	// the user started study sessions or reviewed words.
+	StudyDays(ctx context.Context, userID int, loc *time.Location) ([]time.Time, error)
+	// StreakFreezes returns the days the user froze, in the layout
+	// 2006-01-02.
+	StreakFreezes(ctx context.Context, userID int) ([]string, error)
+	AddStreakFreeze(ctx context.Context, userID int, day string) error
+}
+
+// Service keeps the streaks of learners.
+type Service struct {
+	store Store
+	now   func() time.Time
+}
+
+// NewService reads study from store. now tells the time; nil means
+// time.Now.
+func NewService(store Store, now func() time.Time) *Service {
+	if now == nil {
+		now = time.Now
+	}
+	return &Service{store: store, now: now}
+}
+
+// Get reports the streak of the user.
+func (s *Service) Get(ctx context.Context, userID int) (*models.Streak, error) {
+	h, err := s.history(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	streak := h.replay()
+	return &streak, nil
+}
+
+// Freeze spends a freeze on day, which must be today or yesterday in the
+// time zone of the user, so a day without study keeps the streak alive. It
+// returns the streak with the day frozen.
+func (s *Service) Freeze(ctx context.Context, userID int, day string) (*models.Streak, error) {
+	h, err := s.history(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	date, err := time.Parse(dateLayout, day)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrNotFreezable, err)
+	}
+	before := date.AddDate(0, 0, -1)
+	switch {
+	case !date.Equal(h.today) && !date.Equal(h.today.AddDate(0, 0, -1)):
+		return nil, ErrNotFreezable
+	case h.studied[date] || h.frozen[date]:
+		return nil, ErrNotFreezable
+	// Freezing only bridges a gap in a streak
+	case !h.studied[before] && !h.frozen[before]:
+		return nil, ErrNotFreezable
+	}
+	if h.replay().Freezes == 0 {
+		return nil, ErrNoFreezes
+	}
+
+	if err := s.store.AddStreakFreeze(ctx, userID, day); err != nil {
+		return nil, err
+	}
+	h.frozen[date] = true
+	streak := h.replay()
+	return &streak, nil
+}
+
+// history is the study of a learner by day of their time zone. Days are
+// dates at midnight UTC.
+type history struct {
+	timezone string
+	today    time.Time
+	studied  map[time.Time]bool
+	frozen   map[time.Time]bool
+}
+
+func (s *Service) history(ctx context.Context, userID int) (*history, error) {
+	timezone, err := s.store.Timezone(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	loc, err := time.LoadLocation(timezone)
+	if err != nil {
+		return nil, fmt.Errorf("error loading time zone: %w", err)
+	}
+
+	days, err := s.store.StudyDays(ctx, userID, loc)
+	if err != nil {
+		return nil, err
+	}
+	freezes, err := s.store.StreakFreezes(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	h := &history{
+		timezone: timezone,
+		today:    localDay(s.now(), loc),
+		studied:  map[time.Time]bool{},
+		frozen:   map[time.Time]bool{},
+	}
+	for _, day := range days {
+		h.studied[day] = true
+	}
+	for _, day := range freezes {
+		date, err := time.Parse(dateLayout, day)
+		if err != nil {
+			return nil, fmt.Errorf("error parsing frozen day: %w", err)
+		}
+		h.frozen[date] = true
+	}
+	return h, nil
+}
+
+// localDay is the date of t in loc.
+func localDay(t time.Time, loc *time.Location) time.Time {
+	y, m, d := t.In(loc).Date()
+	return time.Date(y, m, d, 0, 0, 0, 0, time.UTC)
+}
+
+// replay walks the days from the first one studied or frozen through today.
+// Studied days lengthen the streak and every DaysPerFreeze of them in a row
+// earn a freeze; frozen days spend one and keep the streak as it is. Any
+// other day but today breaks it, so a streak ends once both today and
+// yesterday go without study.
+func (h *history) replay() models.Streak {
+	streak := models.Streak{Timezone: h.timezone, Today: h.today.Format(dateLayout), FrozenDays: []string{}}
+
+	var first time.Time
+	for _, days := range []map[time.Time]bool{h.studied, h.frozen} {
+		for day := range days {
+			if first.IsZero() || day.Before(first) {
+				first = day
+			}
+		}
+	}
+	if first.IsZero() {
+		return streak
+	}
+
+	for day := first; !day.After(h.today); day = day.AddDate(0, 0, 1) {
+		switch {
+		case h.studied[day]:
+			streak.Current++
+			streak.Longest = max(streak.Longest, streak.Current)
+			if streak.Current%DaysPerFreeze == 0 {
+				streak.Freezes = min(streak.Freezes+1, MaxFreezes)
+			}
+			last := day.Format(dateLayout)
+			streak.LastStudyDay = &last
+		case h.frozen[day]:
+			streak.Freezes = max(streak.Freezes-1, 0)
+			streak.FrozenDays = append(streak.FrozenDays, day.Format(dateLayout))
+		case day.Equal(h.today):
+			// Today is not over yet
+		default:
+			streak.Current = 0
+		}
+	}
+	streak.StudiedToday = h.studied[h.today]
+	return streak
+}
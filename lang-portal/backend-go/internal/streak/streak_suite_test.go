@@ -0,0 +1,13 @@
+package streak_test
+
+import (
+	"testing"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+func TestStreak(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "Streak Suite")
+}
@@ -0,0 +1,253 @@
+package streak_test
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/souheilbenslama/free-genai-bootcamp-2025/lang-portal/backend-go/internal/streak"
+)
+
+// fakeStore keeps the study of a single learner.
+type fakeStore struct {
+	timezone string
+	times    []time.Time
+	freezes  []string
+	err      error
+}
+
+func (f *fakeStore) Timezone(ctx context.Context, userID int) (string, error) {
+	return f.timezone, f.err
+}
+
+func (f *fakeStore) StudyDays(ctx context.Context, userID int, loc *time.Location) ([]time.Time, error) {
+	var days []time.Time
+	for _, t := range f.times {
+		y, m, d := t.In(loc).Date()
+		days = append(days, time.Date(y, m, d, 0, 0, 0, 0, time.UTC))
+	}
+	return days, f.err
+}
+
+func (f *fakeStore) StreakFreezes(ctx context.Context, userID int) ([]string, error) {
+	return f.freezes, f.err
+}
+
+func (f *fakeStore) AddStreakFreeze(ctx context.Context, userID int, day string) error {
+	f.freezes = append(f.freezes, day)
+	return f.err
+}
+
+var _ = Describe("Service", func() {
+	var (
+		store   *fakeStore
+		now     time.Time
+		service *streak.Service
+	)
+
+	// studied records study at noon UTC of each day, counted back from the
+	// day of now
+	studied := func(daysAgo ...int) {
+		for _, n := range daysAgo {
+			y, m, d := now.AddDate(0, 0, -n).Date()
+			store.times = append(store.times, time.Date(y, m, d, 12, 0, 0, 0, time.UTC))
+		}
+	}
+
+	BeforeEach(func() {
+		store = &fakeStore{timezone: "UTC"}
+		now = time.Date(2025, time.March, 10, 18, 0, 0, 0, time.UTC)
+		service = streak.NewService(store, func() time.Time { return now })
+	})
+
+	get := func() int {
+		s, err := service.Get(context.Background(), 1)
+		Expect(err).NotTo(HaveOccurred())
+		return s.Current
+	}
+
+	It("reports no streak without study", func() {
+		s, err := service.Get(context.Background(), 1)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(s.Current).To(BeZero())
+		Expect(s.Longest).To(BeZero())
+		Expect(s.LastStudyDay).To(BeNil())
+		Expect(s.Today).To(Equal("2025-03-10"))
+		Expect(s.FrozenDays).To(BeEmpty())
+	})
+
+	It("counts the days in a row up to today", func() {
+		studied(0, 1, 2)
+		s, err := service.Get(context.Background(), 1)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(s.Current).To(Equal(3))
+		Expect(s.StudiedToday).To(BeTrue())
+		Expect(*s.LastStudyDay).To(Equal("2025-03-10"))
+	})
+
+	It("keeps the streak while today is not studied yet", func() {
+		studied(1, 2)
+		s, err := service.Get(context.Background(), 1)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(s.Current).To(Equal(2))
+		Expect(s.StudiedToday).To(BeFalse())
+	})
+
+	It("breaks the streak once today and yesterday go without study", func() {
+		studied(2, 3, 4)
+		s, err := service.Get(context.Background(), 1)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(s.Current).To(BeZero())
+		Expect(s.Longest).To(Equal(3))
+	})
+
+	It("tracks the longest streak apart from the current one", func() {
+		studied(0, 1, 5, 6, 7, 8, 20)
+		s, err := service.Get(context.Background(), 1)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(s.Current).To(Equal(2))
+		Expect(s.Longest).To(Equal(4))
+	})
+
+	It("counts several study on a day once", func() {
+		studied(0, 0, 1, 1)
+		Expect(get()).To(Equal(2))
+	})
+
+	It("counts days in the time zone of the learner", func() {
+		store.timezone = "America/New_York"
+		// 02:00 UTC on the 10th is still the evening of the 9th in New York,
+		// and 18:00 UTC on the 10th the afternoon of the 10th
+		store.times = []time.Time{
+			time.Date(2025, time.March, 9, 2, 0, 0, 0, time.UTC),
+			time.Date(2025, time.March, 10, 2, 0, 0, 0, time.UTC),
+		}
+		s, err := service.Get(context.Background(), 1)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(s.Today).To(Equal("2025-03-10"))
+		Expect(s.Timezone).To(Equal("America/New_York"))
+		Expect(*s.LastStudyDay).To(Equal("2025-03-09"))
+		Expect(s.Current).To(Equal(2))
+		Expect(s.StudiedToday).To(BeFalse())
+
+		// In UTC the same study falls on the 9th and the 10th
+		store.timezone = "UTC"
+		s, err = service.Get(context.Background(), 1)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(s.StudiedToday).To(BeTrue())
+	})
+
+	It("moves to the next day at midnight of the learner", func() {
+		store.timezone = "Asia/Tokyo"
+		studied(1, 2)
+		// 18:00 UTC is 03:00 of the 11th in Tokyo, so the 9th was two days ago
+		s, err := service.Get(context.Background(), 1)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(s.Today).To(Equal("2025-03-11"))
+		Expect(s.Current).To(BeZero())
+	})
+
+	It("fails on time zones it does not know", func() {
+		store.timezone = "Mars/Olympus_Mons"
+		_, err := service.Get(context.Background(), 1)
+		Expect(err).To(HaveOccurred())
+	})
+
+	It("reports the errors of the store", func() {
+		store.err = errors.New("database is locked")
+		_, err := service.Get(context.Background(), 1)
+		Expect(err).To(MatchError("database is locked"))
+	})
+
+	Describe("freezes", func() {
+		week := func(from int) {
+			for n := from; n < from+streak.DaysPerFreeze; n++ {
+				studied(n)
+			}
+		}
+
+		It("earns a freeze for every week in a row, up to the limit", func() {
+			week(1)
+			s, err := service.Get(context.Background(), 1)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(s.Freezes).To(Equal(1))
+
+			week(1 + streak.DaysPerFreeze)
+			week(1 + 2*streak.DaysPerFreeze)
+			s, err = service.Get(context.Background(), 1)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(s.Current).To(Equal(3 * streak.DaysPerFreeze))
+			Expect(s.Freezes).To(Equal(streak.MaxFreezes))
+		})
+
+		It("keeps the streak over a frozen day without lengthening it", func() {
+			week(2)
+			s, err := service.Freeze(context.Background(), 1, "2025-03-09")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(s.Current).To(Equal(streak.DaysPerFreeze))
+			Expect(s.Freezes).To(BeZero())
+			Expect(s.FrozenDays).To(Equal([]string{"2025-03-09"}))
+			Expect(store.freezes).To(Equal([]string{"2025-03-09"}))
+
+			// Studying today goes on with the streak
+			studied(0)
+			Expect(get()).To(Equal(streak.DaysPerFreeze + 1))
+		})
+
+		It("breaks the streak over a missed day that was not frozen", func() {
+			week(2)
+			studied(0)
+			Expect(get()).To(Equal(1))
+		})
+
+		It("fails without freezes left", func() {
+			studied(2, 3)
+			_, err := service.Freeze(context.Background(), 1, "2025-03-09")
+			Expect(err).To(MatchError(streak.ErrNoFreezes))
+			Expect(store.freezes).To(BeEmpty())
+		})
+
+		DescribeTable("refuses days that cannot be frozen",
+			func(day string) {
+				week(2)
+				studied(0)
+				_, err := service.Freeze(context.Background(), 1, day)
+				Expect(err).To(MatchError(streak.ErrNotFreezable))
+				Expect(store.freezes).To(BeEmpty())
+			},
+			Entry("today, once studied", "2025-03-10"),
+			Entry("before yesterday", "2025-03-07"),
+			Entry("tomorrow", "2025-03-11"),
+			Entry("a malformed day", "10.03.2025"),
+		)
+
+		It("refuses days that would not keep a streak alive", func() {
+			week(3)
+			_, err := service.Freeze(context.Background(), 1, "2025-03-10")
+			Expect(err).To(MatchError(streak.ErrNotFreezable))
+		})
+
+		It("refuses days already frozen", func() {
+			week(2)
+			week(2 + streak.DaysPerFreeze)
+			_, err := service.Freeze(context.Background(), 1, "2025-03-09")
+			Expect(err).NotTo(HaveOccurred())
+			_, err = service.Freeze(context.Background(), 1, "2025-03-09")
+			Expect(err).To(MatchError(streak.ErrNotFreezable))
+		})
+
+		It("freezes today and yesterday in a row", func() {
+			week(2)
+			week(2 + streak.DaysPerFreeze)
+			_, err := service.Freeze(context.Background(), 1, "2025-03-09")
+			Expect(err).NotTo(HaveOccurred())
+			s, err := service.Freeze(context.Background(), 1, "2025-03-10")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(s.Current).To(Equal(2 * streak.DaysPerFreeze))
+			Expect(s.Freezes).To(BeZero())
+		})
+	})
+})
@@ -0,0 +1,53 @@
+// Package study keeps study sessions in order in the background.
+package study
+
+import (
+	"context"
+	"log/slog"
+	"time"
+
+	"github.com/souheilbenslama/free-genai-bootcamp-2025/lang-portal/backend-go/internal/metrics"
+	"github.com/souheilbenslama/free-genai-bootcamp-2025/lang-portal/backend-go/internal/models"
+	"github.com/souheilbenslama/free-genai-bootcamp-2025/lang-portal/backend-go/internal/server"
+)
+
+// IdleSessions ends the active sessions of every user that had no activity
+// since cutoff and reports how many it ended.
+type IdleSessions interface {
+	AbandonIdleSessions(ctx context.Context, cutoff time.Time) (int, error)
+}
+
+// AbandonIdle returns a worker that abandons sessions idle for longer than
+// idle. It checks right away and then every interval, so a session is
+// abandoned at most interval after it went stale.
+func AbandonIdle(sessions IdleSessions, idle, interval time.Duration) server.Worker {
+	return func(ctx context.Context) {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		for {
+			abandon(ctx, sessions, time.Now().Add(-idle))
+
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+			}
+		}
+	}
+}
+
+func abandon(ctx context.Context, sessions IdleSessions, cutoff time.Time) {
+	n, err := sessions.AbandonIdleSessions(ctx, cutoff)
+	if err != nil {
+		// A cancelled context only means the server is shutting down
+		if ctx.Err() == nil {
+			slog.Error("abandoning idle study sessions failed", slog.Any("error", err))
+		}
+		return
+	}
+	if n > 0 {
+		slog.Info("abandoned idle study sessions", slog.Int("count", n))
+		metrics.SessionsEnded.WithLabelValues(models.SessionAbandoned).Add(float64(n))
+	}
+}
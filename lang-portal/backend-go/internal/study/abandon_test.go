@@ -0,0 +1,80 @@
+package study_test
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/souheilbenslama/free-genai-bootcamp-2025/lang-portal/backend-go/internal/study"
+)
+
+// fakeSessions records the cutoffs it is asked to abandon sessions before.
+type fakeSessions struct {
+	mu      sync.Mutex
+	cutoffs []time.Time
+	err     error
+}
+
+func (f *fakeSessions) AbandonIdleSessions(ctx context.Context, cutoff time.Time) (int, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.cutoffs = append(f.cutoffs, cutoff)
+	return 1, f.err
+}
+
+func (f *fakeSessions) calls() []time.Time {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return append([]time.Time(nil), f.cutoffs...)
+}
+
+func (f *fakeSessions) count() int {
+	return len(f.calls())
+}
+
+var _ = Describe("AbandonIdle", func() {
+	run := func(sessions *fakeSessions, idle, interval time.Duration) (stop func()) {
+		ctx, cancel := context.WithCancel(context.Background())
+		done := make(chan struct{})
+		go func() {
+			defer close(done)
+			study.AbandonIdle(sessions, idle, interval)(ctx)
+		}()
+		return func() {
+			cancel()
+			Eventually(done).Should(BeClosed())
+		}
+	}
+
+	It("abandons sessions idle for longer than the timeout, right away and then periodically", func() {
+		sessions := &fakeSessions{}
+		start := time.Now()
+		stop := run(sessions, 30*time.Minute, 10*time.Millisecond)
+		defer stop()
+
+		Eventually(sessions.count).Should(BeNumerically(">=", 3))
+		first := sessions.calls()[0]
+		Expect(first).To(BeTemporally("~", start.Add(-30*time.Minute), time.Second))
+	})
+
+	It("keeps going after a failed sweep", func() {
+		sessions := &fakeSessions{err: errors.New("database is locked")}
+		stop := run(sessions, time.Minute, 10*time.Millisecond)
+		defer stop()
+
+		Eventually(sessions.count).Should(BeNumerically(">=", 2))
+	})
+
+	It("stops when its context is cancelled", func() {
+		sessions := &fakeSessions{}
+		stop := run(sessions, time.Minute, time.Hour)
+
+		Eventually(sessions.count).Should(Equal(1))
+		stop()
+		Expect(sessions.calls()).To(HaveLen(1))
+	})
+})
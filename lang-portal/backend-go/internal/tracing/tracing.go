@@ -0,0 +1,112 @@
+package tracing
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strings"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracehttp"
+	"go.opentelemetry.io/otel/exporters/stdout/stdouttrace"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	semconv "go.opentelemetry.io/otel/semconv/v1.26.0"
+
+	"github.com/souheilbenslama/free-genai-bootcamp-2025/lang-portal/backend-go/internal/config"
+	"github.com/souheilbenslama/free-genai-bootcamp-2025/lang-portal/backend-go/internal/version"
+)
+
+// ServiceName identifies this service in exported traces.
+const ServiceName = "lang-portal-api"
+
+// Shutdown flushes buffered spans and releases the exporter.
+type Shutdown func(ctx context.Context) error
+
+// Setup installs the global tracer provider and W3C trace context propagator
+// described by cfg. With the none exporter no spans are recorded, but incoming
+// trace context is still propagated.
+func Setup(ctx context.Context, cfg *config.Config) (Shutdown, error) {
+	otel.SetTextMapPropagator(propagation.NewCompositeTextMapPropagator(
+		propagation.TraceContext{},
+		propagation.Baggage{},
+	))
+
+	if cfg.TracingExporter == "none" {
+		return func(context.Context) error { return nil }, nil
+	}
+
+	exporter, closer, err := newExporter(ctx, cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	info := version.Get()
+	res, err := resource.Merge(resource.Default(), resource.NewWithAttributes(
+		semconv.SchemaURL,
+		semconv.ServiceName(ServiceName),
+		semconv.ServiceVersion(info.Commit),
+	))
+	if err != nil {
+		return nil, fmt.Errorf("error building trace resource: %w", err)
+	}
+
+	provider := sdktrace.NewTracerProvider(
+		sdktrace.WithBatcher(exporter),
+		sdktrace.WithResource(res),
+		sdktrace.WithSampler(sdktrace.ParentBased(sdktrace.TraceIDRatioBased(cfg.TracingSampleRatio))),
+	)
+	otel.SetTracerProvider(provider)
+
+	return func(ctx context.Context) error {
+		err := provider.Shutdown(ctx)
+		if closer != nil {
+			err = errors.Join(err, closer.Close())
+		}
+		return err
+	}, nil
+}
+
+// Traced reports whether a request should start a trace. Probes and metric
+// scrapes are skipped so they do not drown out API traffic.
+func Traced(r *http.Request) bool {
+	switch r.URL.Path {
+	case "/healthz", "/readyz", "/metrics":
+		return false
+	}
+	return true
+}
+
+func newExporter(ctx context.Context, cfg *config.Config) (sdktrace.SpanExporter, io.Closer, error) {
+	switch cfg.TracingExporter {
+	case "stdout":
+		exporter, err := stdouttrace.New(stdouttrace.WithWriter(os.Stdout))
+		if err != nil {
+			return nil, nil, fmt.Errorf("error creating stdout trace exporter: %w", err)
+		}
+		return exporter, nil, nil
+	case "file":
+		f, err := os.OpenFile(cfg.TracingFile, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0o644)
+		if err != nil {
+			return nil, nil, fmt.Errorf("error opening trace file: %w", err)
+		}
+		exporter, err := stdouttrace.New(stdouttrace.WithWriter(f))
+		if err != nil {
+			f.Close()
+			return nil, nil, fmt.Errorf("error creating file trace exporter: %w", err)
+		}
+		return exporter, f, nil
+	case "otlp":
+		exporter, err := otlptracehttp.New(ctx, otlptracehttp.WithEndpointURL(strings.TrimSuffix(cfg.OTLPEndpoint, "/")+"/v1/traces"))
+		if err != nil {
+			return nil, nil, fmt.Errorf("error creating OTLP trace exporter: %w", err)
+		}
+		return exporter, nil, nil
+	default:
+		return nil, nil, fmt.Errorf("unknown tracing exporter %q", cfg.TracingExporter)
+	}
+}
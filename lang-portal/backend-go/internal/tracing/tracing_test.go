@@ -0,0 +1,122 @@
+package tracing_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"go.opentelemetry.io/contrib/instrumentation/github.com/gin-gonic/gin/otelgin"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+
+	"github.com/souheilbenslama/free-genai-bootcamp-2025/lang-portal/backend-go/database"
+	"github.com/souheilbenslama/free-genai-bootcamp-2025/lang-portal/backend-go/internal/api/handlers"
+	"github.com/souheilbenslama/free-genai-bootcamp-2025/lang-portal/backend-go/internal/api/handlers/test"
+	"github.com/souheilbenslama/free-genai-bootcamp-2025/lang-portal/backend-go/internal/api/middleware"
+	"github.com/souheilbenslama/free-genai-bootcamp-2025/lang-portal/backend-go/internal/api/routes"
+	"github.com/souheilbenslama/free-genai-bootcamp-2025/lang-portal/backend-go/internal/auth"
+	"github.com/souheilbenslama/free-genai-bootcamp-2025/lang-portal/backend-go/internal/repository/sqlite"
+	"github.com/souheilbenslama/free-genai-bootcamp-2025/lang-portal/backend-go/internal/streak"
+	"github.com/souheilbenslama/free-genai-bootcamp-2025/lang-portal/backend-go/internal/tracing"
+)
+
+var _ = Describe("Tracing", Ordered, func() {
+	var (
+		exporter *tracetest.InMemoryExporter
+		router   *gin.Engine
+	)
+
+	// The global tracer provider can only be delegated once, so every spec
+	// shares it and resets the exporter instead.
+	BeforeAll(func() {
+		exporter = tracetest.NewInMemoryExporter()
+		otel.SetTracerProvider(sdktrace.NewTracerProvider(sdktrace.WithSyncer(exporter)))
+	})
+
+	BeforeEach(func() {
+		exporter.Reset()
+
+		gin.SetMode(gin.TestMode)
+		router = gin.New()
+		router.Use(otelgin.Middleware(tracing.ServiceName, otelgin.WithFilter(tracing.Traced)))
+
+		db := test.SetupTestDB()
+		routes.SetupRoutes(router, routes.Handlers{
+			Word:        handlers.NewWordHandler(sqlite.NewWordRepository(db)),
+			Group:       handlers.NewGroupHandler(sqlite.NewGroupRepository(db)),
+			Study:       handlers.NewStudyHandler(sqlite.NewStudyRepository(db), streak.NewService(sqlite.NewStudyRepository(db), nil)),
+			Health:      handlers.NewHealthHandler(db, database.Migrations(), true),
+			Auth:        handlers.NewAuthHandler(sqlite.NewUserRepository(db), test.Tokens),
+			RequireUser: middleware.Authenticate(test.Tokens, sqlite.NewAPIKeyRepository(db)),
+		})
+	})
+
+	serve := func(method, path, body string) {
+		w := httptest.NewRecorder()
+		req := httptest.NewRequest(method, path, strings.NewReader(body))
+		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set("Authorization", test.Bearer(1, auth.RoleAdmin))
+		router.ServeHTTP(w, req)
+	}
+
+	spanNamed := func(spans tracetest.SpanStubs, name string) tracetest.SpanStub {
+		for _, span := range spans {
+			if span.Name == name {
+				return span
+			}
+		}
+		Fail("no span named " + name)
+		return tracetest.SpanStub{}
+	}
+
+	attr := func(span tracetest.SpanStub, key attribute.Key) string {
+		for _, kv := range span.Attributes {
+			if kv.Key == key {
+				return kv.Value.Emit()
+			}
+		}
+		return ""
+	}
+
+	It("nests a span per SQL statement under the route span", func() {
+		serve(http.MethodGet, "/api/groups", "")
+
+		spans := exporter.GetSpans()
+		root := spanNamed(spans, "/api/groups")
+		for _, name := range []string{"groups.count", "groups.list"} {
+			span := spanNamed(spans, name)
+			Expect(span.Parent.SpanID()).To(Equal(root.SpanContext.SpanID()))
+			Expect(span.SpanContext.TraceID()).To(Equal(root.SpanContext.TraceID()))
+			Expect(attr(span, "db.system")).To(Equal("sqlite"))
+		}
+	})
+
+	It("records the SQL template but not the bound values", func() {
+		serve(http.MethodPost, "/api/words", `{"german":"Geheimnis","english":"secret","parts":"das"}`)
+
+		span := spanNamed(exporter.GetSpans(), "words.insert")
+		Expect(attr(span, "db.statement")).To(Equal("INSERT INTO words (german, english, parts) VALUES (?, ?, ?)"))
+		for _, kv := range span.Attributes {
+			Expect(kv.Value.Emit()).NotTo(ContainSubstring("Geheimnis"))
+		}
+	})
+
+	It("marks failed statements as errors", func() {
+		serve(http.MethodGet, "/api/dashboard/quick_stats", "")
+
+		span := spanNamed(exporter.GetSpans(), "dashboard.totals")
+		Expect(span.Status.Code.String()).To(Equal("Error"))
+	})
+
+	It("skips probes and metric scrapes", func() {
+		serve(http.MethodGet, "/healthz", "")
+		serve(http.MethodGet, "/metrics", "")
+
+		Expect(exporter.GetSpans()).To(BeEmpty())
+	})
+})
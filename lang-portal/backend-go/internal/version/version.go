@@ -0,0 +1,51 @@
+package version
+
+import (
+	"runtime"
+	"runtime/debug"
+)
+
+// Commit and BuildTime are stamped at build time, e.g.
+//
+//	go build -ldflags "-X .../internal/version.Commit=$(git rev-parse HEAD) -X .../internal/version.BuildTime=$(date -u +%FT%TZ)"
+//
+// When they are empty the VCS information recorded by the Go toolchain is used.
+var (
+	Commit    = ""
+	BuildTime = ""
+)
+
+// Info describes the running binary.
+type Info struct {
+	Commit    string `json:"commit"`
+	BuildTime string `json:"build_time"`
+	GoVersion string `json:"go_version"`
+}
+
+// Get returns the build information of the running binary.
+func Get() Info {
+	info := Info{
+		Commit:    Commit,
+		BuildTime: BuildTime,
+		GoVersion: runtime.Version(),
+	}
+
+	if build, ok := debug.ReadBuildInfo(); ok {
+		for _, setting := range build.Settings {
+			switch {
+			case setting.Key == "vcs.revision" && info.Commit == "":
+				info.Commit = setting.Value
+			case setting.Key == "vcs.time" && info.BuildTime == "":
+				info.BuildTime = setting.Value
+			}
+		}
+	}
+
+	if info.Commit == "" {
+		info.Commit = "unknown"
+	}
+	if info.BuildTime == "" {
+		info.BuildTime = "unknown"
+	}
+	return info
+}
@@ -2,14 +2,17 @@
 package main
 
 import (
+	"context"
 	"database/sql"
 	"fmt"
 	"os"
 	"path/filepath"
+	"time"
 
 	"github.com/magefile/mage/mg"
 	"github.com/magefile/mage/sh"
 	_ "github.com/mattn/go-sqlite3"
+	"github.com/souheilbenslama/free-genai-bootcamp-2025/lang-portal/backend-go/internal/migrate"
 	"github.com/souheilbenslama/free-genai-bootcamp-2025/lang-portal/backend-go/internal/seeder"
 )
 
@@ -31,17 +34,13 @@ func (DB) Migrate() error {
 	}
 	defer db.Close()
 
-	migrationPath := filepath.Join("database", "migrations", "001_initial_schema.sql")
-	migrationSQL, err := os.ReadFile(migrationPath)
+	migrationDir := filepath.Join("database", "migrations")
+	applied, err := migrate.Apply(context.Background(), db, os.DirFS(migrationDir))
 	if err != nil {
-		return fmt.Errorf("failed to read migration file: %w", err)
-	}
-
-	if _, err := db.Exec(string(migrationSQL)); err != nil {
 		return fmt.Errorf("failed to apply migrations: %w", err)
 	}
 
-	fmt.Println("Migrations applied successfully")
+	fmt.Printf("Migrations applied successfully: %v\n", applied)
 	return nil
 }
 
@@ -56,11 +55,16 @@ func (DB) Seed() error {
 	defer db.Close()
 
 	seedDir := filepath.Join("database", "seed")
-	if err := seeder.LoadSeedData(db, seedDir); err != nil {
+	loaded, err := seeder.LoadSeedData(db, seedDir)
+	if err != nil {
 		return fmt.Errorf("failed to load seed data: %w", err)
 	}
 
-	fmt.Println("Seed data loaded successfully")
+	if loaded {
+		fmt.Println("Seed data loaded successfully")
+	} else {
+		fmt.Println("Seed data already loaded")
+	}
 	return nil
 }
 
@@ -82,7 +86,14 @@ func (Dev) Run() error {
 // Build builds the application
 func Build() error {
 	fmt.Println("Building application...")
-	return sh.Run("go", "build", "-o", "lang-portal", "./cmd/api")
+	commit, err := sh.Output("git", "rev-parse", "HEAD")
+	if err != nil {
+		commit = "unknown"
+	}
+	pkg := "github.com/souheilbenslama/free-genai-bootcamp-2025/lang-portal/backend-go/internal/version"
+	ldflags := fmt.Sprintf("-X %s.Commit=%s -X %s.BuildTime=%s",
+		pkg, commit, pkg, time.Now().UTC().Format(time.RFC3339))
+	return sh.Run("go", "build", "-ldflags", ldflags, "-o", "lang-portal", "./cmd/api")
 }
 
 // Test runs the test suite with verbose output
@@ -2,6 +2,7 @@ package e2e
 
 import (
 	"bytes"
+	"context"
 	"database/sql"
 	"encoding/json"
 	"fmt"
@@ -14,10 +15,19 @@ import (
 	_ "github.com/mattn/go-sqlite3"
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/souheilbenslama/free-genai-bootcamp-2025/lang-portal/backend-go/database"
 	"github.com/souheilbenslama/free-genai-bootcamp-2025/lang-portal/backend-go/internal/api/handlers"
+	"github.com/souheilbenslama/free-genai-bootcamp-2025/lang-portal/backend-go/internal/api/middleware"
 	"github.com/souheilbenslama/free-genai-bootcamp-2025/lang-portal/backend-go/internal/api/routes"
+	"github.com/souheilbenslama/free-genai-bootcamp-2025/lang-portal/backend-go/internal/auth"
+	"github.com/souheilbenslama/free-genai-bootcamp-2025/lang-portal/backend-go/internal/grading"
+	"github.com/souheilbenslama/free-genai-bootcamp-2025/lang-portal/backend-go/internal/metrics"
+	"github.com/souheilbenslama/free-genai-bootcamp-2025/lang-portal/backend-go/internal/migrate"
 	"github.com/souheilbenslama/free-genai-bootcamp-2025/lang-portal/backend-go/internal/models"
+	"github.com/souheilbenslama/free-genai-bootcamp-2025/lang-portal/backend-go/internal/quiz"
 	"github.com/souheilbenslama/free-genai-bootcamp-2025/lang-portal/backend-go/internal/repository/sqlite"
+	"github.com/souheilbenslama/free-genai-bootcamp-2025/lang-portal/backend-go/internal/streak"
 )
 
 const testDBPath = "test.db"
@@ -46,9 +56,24 @@ var _ = BeforeSuite(func() {
 
 	wordHandler := handlers.NewWordHandler(wordRepo)
 	groupHandler := handlers.NewGroupHandler(groupRepo)
-	studyHandler := handlers.NewStudyHandler(studyRepo)
-
-	routes.SetupRoutes(router, wordHandler, groupHandler, studyHandler)
+	studyHandler := handlers.NewStudyHandler(studyRepo, streak.NewService(studyRepo, nil))
+	healthHandler := handlers.NewHealthHandler(db, database.Migrations(), false)
+
+	tokens := auth.NewTokens([]byte("e2e-secret-e2e-secret-e2e-secret"), time.Hour, time.Hour)
+	authHandler := handlers.NewAuthHandler(sqlite.NewUserRepository(db), tokens)
+	adminHandler := handlers.NewAdminHandler(sqlite.NewUserRepository(db), sqlite.NewAPIKeyRepository(db))
+
+	routes.SetupRoutes(router, routes.Handlers{
+		Word:        wordHandler,
+		Group:       groupHandler,
+		Study:       studyHandler,
+		Question:    handlers.NewQuestionHandler(studyRepo, groupRepo),
+		Answer:      handlers.NewAnswerHandler(studyRepo, 1),
+		Health:      healthHandler,
+		Auth:        authHandler,
+		Admin:       adminHandler,
+		RequireUser: middleware.Authenticate(tokens, sqlite.NewAPIKeyRepository(db)),
+	})
 
 	server = &http.Server{
 		Addr:    serverAddr,
@@ -80,18 +105,66 @@ func setupTestDB() {
 	db, err = sql.Open("sqlite3", testDBPath)
 	Expect(err).NotTo(HaveOccurred())
 
-	// Read and execute migration SQL
-	migrationSQL, err := os.ReadFile("../../database/migrations/001_initial_schema.sql")
+	_, err = migrate.Apply(context.Background(), db, database.Migrations())
+	Expect(err).NotTo(HaveOccurred())
+}
+
+// login registers an account and returns its Authorization header value.
+func login(email string) string {
+	body := fmt.Sprintf(`{"email":%q,"name":"Learner","password":"correct horse battery"}`, email)
+	resp, err := http.Post(baseURL+"/api/auth/register", "application/json", bytes.NewReader([]byte(body)))
+	Expect(err).NotTo(HaveOccurred())
+	Expect(resp.StatusCode).To(Equal(http.StatusCreated))
+
+	return signIn(email)
+}
+
+// signIn logs in to a registered account and returns its Authorization
+// header value.
+func signIn(email string) string {
+	body := fmt.Sprintf(`{"email":%q,"password":"correct horse battery"}`, email)
+	resp, err := http.Post(baseURL+"/api/auth/login", "application/json", bytes.NewReader([]byte(body)))
+	Expect(err).NotTo(HaveOccurred())
+	Expect(resp.StatusCode).To(Equal(http.StatusOK))
+
+	var tokens struct {
+		AccessToken string `json:"access_token"`
+	}
+	Expect(json.NewDecoder(resp.Body).Decode(&tokens)).To(Succeed())
+	return "Bearer " + tokens.AccessToken
+}
+
+// do sends an authenticated request with an optional JSON body.
+func do(method, url, authorization, body string) *http.Response {
+	req, err := http.NewRequest(method, url, bytes.NewReader([]byte(body)))
 	Expect(err).NotTo(HaveOccurred())
+	if body != "" {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	req.Header.Set("Authorization", authorization)
 
-	_, err = db.Exec(string(migrationSQL))
+	resp, err := http.DefaultClient.Do(req)
 	Expect(err).NotTo(HaveOccurred())
+	return resp
 }
 
 var _ = Describe("API E2E Tests", func() {
 	var createdWordID int
 	var createdGroupID int
 	var studySessionID int
+	var admin, learner, otherLearner string
+
+	BeforeEach(func() {
+		if learner == "" {
+			// Registered accounts are learners until an operator promotes them
+			login("admin@example.com")
+			_, err := sqlite.NewUserRepository(db).PromoteAdmin(context.Background(), "admin@example.com")
+			Expect(err).NotTo(HaveOccurred())
+			admin = signIn("admin@example.com")
+			learner = login("anna@example.com")
+			otherLearner = login("ben@example.com")
+		}
+	})
 
 	Context("Word Management Flow", func() {
 		It("should create a new word", func() {
@@ -103,8 +176,7 @@ var _ = Describe("API E2E Tests", func() {
 			body, err := json.Marshal(word)
 			Expect(err).NotTo(HaveOccurred())
 
-			resp, err := http.Post(baseURL+"/api/words", "application/json", bytes.NewReader(body))
-			Expect(err).NotTo(HaveOccurred())
+			resp := do(http.MethodPost, baseURL+"/api/words", admin, string(body))
 			Expect(resp.StatusCode).To(Equal(http.StatusOK))
 
 			var response map[string]interface{}
@@ -114,6 +186,12 @@ var _ = Describe("API E2E Tests", func() {
 			createdWordID = int(response["id"].(float64))
 		})
 
+		It("should not let a learner create words", func() {
+			body := `{"german":"Birne","english":"pear","parts":"noun"}`
+			resp := do(http.MethodPost, baseURL+"/api/words", learner, body)
+			Expect(resp.StatusCode).To(Equal(http.StatusForbidden))
+		})
+
 		It("should get the created word", func() {
 			resp, err := http.Get(fmt.Sprintf("%s/api/words/%d", baseURL, createdWordID))
 			Expect(err).NotTo(HaveOccurred())
@@ -135,8 +213,7 @@ var _ = Describe("API E2E Tests", func() {
 			body, err := json.Marshal(group)
 			Expect(err).NotTo(HaveOccurred())
 
-			resp, err := http.Post(baseURL+"/api/groups", "application/json", bytes.NewReader(body))
-			Expect(err).NotTo(HaveOccurred())
+			resp := do(http.MethodPost, baseURL+"/api/groups", admin, string(body))
 			Expect(resp.StatusCode).To(Equal(http.StatusCreated))
 
 			var response map[string]interface{}
@@ -153,6 +230,7 @@ var _ = Describe("API E2E Tests", func() {
 			req, err := http.NewRequest(http.MethodPost, url, bytes.NewReader([]byte(body)))
 			Expect(err).NotTo(HaveOccurred())
 			req.Header.Set("Content-Type", "application/json")
+			req.Header.Set("Authorization", admin)
 			
 			resp, err := http.DefaultClient.Do(req)
 			Expect(err).NotTo(HaveOccurred())
@@ -163,63 +241,565 @@ var _ = Describe("API E2E Tests", func() {
 	Context("Study Session Flow", func() {
 		It("should start a study session", func() {
 			body := fmt.Sprintf(`{"group_id": %d}`, createdGroupID)
-			resp, err := http.Post(baseURL+"/api/study-sessions", "application/json", bytes.NewReader([]byte(body)))
-			Expect(err).NotTo(HaveOccurred())
+			resp := do(http.MethodPost, baseURL+"/api/study_sessions", learner, body)
 			Expect(resp.StatusCode).To(Equal(http.StatusCreated))
 
 			var response map[string]interface{}
-			err = json.NewDecoder(resp.Body).Decode(&response)
+			err := json.NewDecoder(resp.Body).Decode(&response)
 			Expect(err).NotTo(HaveOccurred())
 			studySessionID = int(response["id"].(float64))
 		})
 
+		It("should issue the words of the group once", func() {
+			url := fmt.Sprintf("%s/api/study_sessions/%d/next?strategy=weakest", baseURL, studySessionID)
+
+			resp := do(http.MethodGet, url, learner, "")
+			Expect(resp.StatusCode).To(Equal(http.StatusOK))
+			var item models.SessionItem
+			Expect(json.NewDecoder(resp.Body).Decode(&item)).To(Succeed())
+			Expect(item.Word.ID).To(Equal(createdWordID))
+			Expect(item.Remaining).To(BeZero())
+
+			resp = do(http.MethodGet, url, learner, "")
+			Expect(resp.StatusCode).To(Equal(http.StatusNoContent))
+		})
+
 		It("should record word review", func() {
-			url := fmt.Sprintf("%s/api/study-sessions/%d/reviews", baseURL, studySessionID)
+			url := fmt.Sprintf("%s/api/study_sessions/%d/reviews", baseURL, studySessionID)
 			body := fmt.Sprintf(`{"word_id": %d, "correct": true}`, createdWordID)
-			
-			req, err := http.NewRequest(http.MethodPost, url, bytes.NewReader([]byte(body)))
+
+			resp := do(http.MethodPost, url, learner, body)
+			Expect(resp.StatusCode).To(Equal(http.StatusNoContent))
+		})
+
+		It("should not let another learner review in the session", func() {
+			url := fmt.Sprintf("%s/api/study_sessions/%d/reviews", baseURL, studySessionID)
+			body := fmt.Sprintf(`{"word_id": %d, "correct": true}`, createdWordID)
+
+			resp := do(http.MethodPost, url, otherLearner, body)
+			Expect(resp.StatusCode).To(Equal(http.StatusNotFound))
+		})
+
+		It("should finish the session", func() {
+			url := fmt.Sprintf("%s/api/study_sessions/%d/finish", baseURL, studySessionID)
+
+			resp := do(http.MethodPost, url, learner, "")
+			Expect(resp.StatusCode).To(Equal(http.StatusOK))
+
+			var session models.StudySession
+			Expect(json.NewDecoder(resp.Body).Decode(&session)).To(Succeed())
+			Expect(session.State).To(Equal(models.SessionCompleted))
+			Expect(session.EndedAt).NotTo(BeNil())
+			Expect(session.DurationSeconds).To(BeNumerically(">=", 0))
+		})
+
+		It("should reject reviews in a finished session", func() {
+			url := fmt.Sprintf("%s/api/study_sessions/%d/reviews", baseURL, studySessionID)
+			body := fmt.Sprintf(`{"word_id": %d, "correct": false}`, createdWordID)
+
+			resp := do(http.MethodPost, url, learner, body)
+			Expect(resp.StatusCode).To(Equal(http.StatusConflict))
+		})
+
+		It("should abandon idle sessions", func() {
+			idleLearner := login("idle@example.com")
+			body := fmt.Sprintf(`{"group_id": %d}`, createdGroupID)
+			resp := do(http.MethodPost, baseURL+"/api/study_sessions", idleLearner, body)
+			Expect(resp.StatusCode).To(Equal(http.StatusCreated))
+			var started models.StudySession
+			Expect(json.NewDecoder(resp.Body).Decode(&started)).To(Succeed())
+
+			// Sweep as if the session had been idle past the timeout
+			abandoned, err := sqlite.NewStudyRepository(db).AbandonIdleSessions(context.Background(), time.Now().Add(time.Minute))
 			Expect(err).NotTo(HaveOccurred())
-			req.Header.Set("Content-Type", "application/json")
-			
-			resp, err := http.DefaultClient.Do(req)
+			Expect(abandoned).To(Equal(1))
+
+			resp = do(http.MethodGet, baseURL+"/api/dashboard/last_study_session", idleLearner, "")
+			Expect(resp.StatusCode).To(Equal(http.StatusOK))
+			var session models.StudySession
+			Expect(json.NewDecoder(resp.Body).Decode(&session)).To(Succeed())
+			Expect(session.ID).To(Equal(started.ID))
+			Expect(session.State).To(Equal(models.SessionAbandoned))
+			Expect(session.EndedAt).NotTo(BeNil())
+			Expect(*session.EndedAt).To(BeTemporally("~", started.CreatedAt, time.Second))
+		})
+
+		It("should ask multiple choice questions and record the chosen option", func() {
+			quizLearner := login("quiz@example.com")
+			body := fmt.Sprintf(`{"group_id": %d}`, createdGroupID)
+			resp := do(http.MethodPost, baseURL+"/api/study_sessions", quizLearner, body)
+			Expect(resp.StatusCode).To(Equal(http.StatusCreated))
+			var session models.StudySession
+			Expect(json.NewDecoder(resp.Body).Decode(&session)).To(Succeed())
+
+			resp = do(http.MethodGet, fmt.Sprintf("%s/api/study_sessions/%d/questions?type=mc", baseURL, session.ID), quizLearner, "")
+			Expect(resp.StatusCode).To(Equal(http.StatusOK))
+			var question quiz.Question
+			Expect(json.NewDecoder(resp.Body).Decode(&question)).To(Succeed())
+			Expect(question.WordID).To(Equal(createdWordID))
+			Expect(question.Prompt).To(Equal("Apfel"))
+			Expect(question.Options).To(ContainElement(quiz.Option{WordID: createdWordID, Text: "apple"}))
+
+			url := fmt.Sprintf("%s/api/study_sessions/%d/reviews", baseURL, session.ID)
+			resp = do(http.MethodPost, url, quizLearner, fmt.Sprintf(`{"word_id": %d, "chosen_word_id": 9999}`, createdWordID))
+			Expect(resp.StatusCode).To(Equal(http.StatusBadRequest))
+
+			resp = do(http.MethodPost, url, quizLearner, fmt.Sprintf(`{"word_id": %d, "chosen_word_id": %d}`, createdWordID, createdWordID))
+			Expect(resp.StatusCode).To(Equal(http.StatusNoContent))
+		})
+
+		It("should grade typed answers and record the verdict", func() {
+			typingLearner := login("typing@example.com")
+			resp := do(http.MethodPost, baseURL+"/api/study_sessions", typingLearner, fmt.Sprintf(`{"group_id": %d}`, createdGroupID))
+			Expect(resp.StatusCode).To(Equal(http.StatusCreated))
+			var session models.StudySession
+			Expect(json.NewDecoder(resp.Body).Decode(&session)).To(Succeed())
+
+			resp = do(http.MethodGet, fmt.Sprintf("%s/api/study_sessions/%d/next", baseURL, session.ID), typingLearner, "")
+			Expect(resp.StatusCode).To(Equal(http.StatusOK))
+
+			url := fmt.Sprintf("%s/api/study_sessions/%d/answers", baseURL, session.ID)
+			resp = do(http.MethodPost, url, typingLearner, fmt.Sprintf(`{"word_id": %d, "answer": " apfel"}`, createdWordID))
+			Expect(resp.StatusCode).To(Equal(http.StatusCreated))
+			var result grading.Result
+			Expect(json.NewDecoder(resp.Body).Decode(&result)).To(Succeed())
+			Expect(result.Verdict).To(Equal(grading.VerdictAlmost))
+			Expect(result.Expected).To(Equal("Apfel"))
+			Expect(result.Mistakes).To(Equal([]string{grading.MistakeCapitalization}))
+
+			var verdict string
+			var correct bool
+			err := db.QueryRow("SELECT verdict, correct FROM word_review_items WHERE study_session_id = ?", session.ID).Scan(&verdict, &correct)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(verdict).To(Equal(grading.VerdictAlmost))
+			Expect(correct).To(BeFalse())
+		})
+
+		It("should log every attempt at a word", func() {
+			attemptLearner := login("attempts@example.com")
+			resp := do(http.MethodPost, baseURL+"/api/study_sessions", attemptLearner, fmt.Sprintf(`{"group_id": %d}`, createdGroupID))
+			Expect(resp.StatusCode).To(Equal(http.StatusCreated))
+			var session models.StudySession
+			Expect(json.NewDecoder(resp.Body).Decode(&session)).To(Succeed())
+
+			resp = do(http.MethodGet, fmt.Sprintf("%s/api/study_sessions/%d/next", baseURL, session.ID), attemptLearner, "")
+			Expect(resp.StatusCode).To(Equal(http.StatusOK))
+
+			url := fmt.Sprintf("%s/api/study_sessions/%d/reviews", baseURL, session.ID)
+			body := fmt.Sprintf(`{"word_id": %d, "correct": false, "answer": "pear", "response_ms": 2500, "direction": "de_en"}`, createdWordID)
+			resp = do(http.MethodPost, url, attemptLearner, body)
+			Expect(resp.StatusCode).To(Equal(http.StatusNoContent))
+
+			resp = do(http.MethodPost, baseURL+fmt.Sprintf("/api/study_sessions/%d/answers", session.ID), attemptLearner, fmt.Sprintf(`{"word_id": %d, "answer": "Apfel", "response_ms": 1200}`, createdWordID))
+			Expect(resp.StatusCode).To(Equal(http.StatusCreated))
+
+			rows, err := db.Query(`
+				SELECT attempt, correct, answer, expected, response_ms, direction
+				FROM word_review_items WHERE study_session_id = ? ORDER BY attempt
+			`, session.ID)
+			Expect(err).NotTo(HaveOccurred())
+			defer rows.Close()
+
+			type attempt struct {
+				number                      int
+				correct                     bool
+				answer, expected, direction string
+				responseMS                  int
+			}
+			var attempts []attempt
+			for rows.Next() {
+				var a attempt
+				Expect(rows.Scan(&a.number, &a.correct, &a.answer, &a.expected, &a.responseMS, &a.direction)).To(Succeed())
+				attempts = append(attempts, a)
+			}
+			Expect(rows.Err()).NotTo(HaveOccurred())
+			Expect(attempts).To(Equal([]attempt{
+				{number: 1, correct: false, answer: "pear", expected: "apple", direction: models.DirectionGermanToEnglish, responseMS: 2500},
+				{number: 2, correct: true, answer: "Apfel", expected: "Apfel", direction: models.DirectionEnglishToGerman, responseMS: 1200},
+			}))
+		})
+
+		It("should chart a learner's study over time", func() {
+			chartLearner := login("charts@example.com")
+			resp := do(http.MethodPost, baseURL+"/api/study_sessions", chartLearner, fmt.Sprintf(`{"group_id": %d}`, createdGroupID))
+			Expect(resp.StatusCode).To(Equal(http.StatusCreated))
+			var session models.StudySession
+			Expect(json.NewDecoder(resp.Body).Decode(&session)).To(Succeed())
+
+			resp = do(http.MethodGet, fmt.Sprintf("%s/api/study_sessions/%d/next", baseURL, session.ID), chartLearner, "")
+			Expect(resp.StatusCode).To(Equal(http.StatusOK))
+			url := fmt.Sprintf("%s/api/study_sessions/%d/reviews", baseURL, session.ID)
+			for _, correct := range []bool{false, true, true} {
+				resp = do(http.MethodPost, url, chartLearner, fmt.Sprintf(`{"word_id": %d, "correct": %t}`, createdWordID, correct))
+				Expect(resp.StatusCode).To(Equal(http.StatusNoContent))
+			}
+
+			today := time.Now().UTC().Format("2006-01-02")
+			series := func(query string) models.TimeSeries {
+				resp := do(http.MethodGet, baseURL+"/api/stats/timeseries?"+query, chartLearner, "")
+				Expect(resp.StatusCode).To(Equal(http.StatusOK))
+				var series models.TimeSeries
+				Expect(json.NewDecoder(resp.Body).Decode(&series)).To(Succeed())
+				return series
+			}
+
+			reviews := series("metric=reviews&compare=true")
+			Expect(reviews.Points).To(HaveLen(30))
+			last := reviews.Points[len(reviews.Points)-1]
+			Expect(last.Start).To(Equal(today))
+			Expect(*last.Value).To(Equal(3.0))
+			Expect(*reviews.Total).To(Equal(3.0))
+			Expect(reviews.Previous.Points).To(HaveLen(30))
+			Expect(*reviews.Previous.Total).To(BeZero())
+			Expect(reviews.Change).To(BeNil())
+
+			accuracy := series("metric=accuracy&from=" + today + "&to=" + today)
+			Expect(accuracy.Points).To(HaveLen(1))
+			Expect(*accuracy.Points[0].Value).To(BeNumerically("~", 66.67, 0.01))
+
+			newWords := series("metric=new_words&bucket=week")
+			Expect(newWords.Points).To(HaveLen(12))
+			Expect(*newWords.Total).To(Equal(1.0))
+
+			articles := series("metric=accuracy&drill=article")
+			Expect(articles.Total).To(BeNil())
+			Expect(*articles.Drill).To(Equal(models.DrillArticle))
+
+			otherGroup := series(fmt.Sprintf("metric=reviews&group_id=%d", createdGroupID+1000))
+			Expect(*otherGroup.Total).To(BeZero())
+		})
+
+		It("should lay out the study of a year in the learner's time zone", func() {
+			calendarLearner := login("calendar@example.com")
+			resp := do(http.MethodPut, baseURL+"/api/auth/me/timezone", calendarLearner, `{"timezone": "America/New_York"}`)
+			Expect(resp.StatusCode).To(Equal(http.StatusOK))
+			var user models.User
+			Expect(json.NewDecoder(resp.Body).Decode(&user)).To(Succeed())
+			Expect(user.Timezone).To(Equal("America/New_York"))
+
+			// Late on March 9 in New York is already March 10 in UTC
+			started := time.Date(2024, time.March, 10, 3, 30, 0, 0, time.UTC)
+			result, err := db.Exec(`
+				INSERT INTO study_sessions (user_id, group_id, created_at, state, last_activity_at, ended_at)
+				VALUES (?, ?, ?, 'completed', ?, ?)
+			`, user.ID, createdGroupID, started, started.Add(30*time.Minute), started.Add(30*time.Minute))
+			Expect(err).NotTo(HaveOccurred())
+			sessionID, err := result.LastInsertId()
+			Expect(err).NotTo(HaveOccurred())
+			for attempt, at := range []time.Time{started.Add(10 * time.Minute), started.Add(20 * time.Minute), started.Add(12 * time.Hour)} {
+				_, err = db.Exec(`
+					INSERT INTO word_review_items (word_id, study_session_id, attempt, correct, created_at)
+					VALUES (?, ?, ?, 1, ?)
+				`, createdWordID, sessionID, attempt+1, at)
+				Expect(err).NotTo(HaveOccurred())
+			}
+
+			resp = do(http.MethodGet, baseURL+"/api/stats/calendar?year=2024", calendarLearner, "")
+			Expect(resp.StatusCode).To(Equal(http.StatusOK))
+			var calendar models.Calendar
+			Expect(json.NewDecoder(resp.Body).Decode(&calendar)).To(Succeed())
+			Expect(calendar.Timezone).To(Equal("America/New_York"))
+			Expect(calendar.Days).To(HaveLen(366))
+			Expect(calendar.Days[0].Date).To(Equal("2024-01-01"))
+
+			march9, march10 := calendar.Days[31+29+8], calendar.Days[31+29+9]
+			Expect(march9).To(Equal(models.CalendarDay{Date: "2024-03-09", Reviews: 2, Sessions: 1, Minutes: 30, Level: 4}))
+			Expect(march10).To(Equal(models.CalendarDay{Date: "2024-03-10", Reviews: 1, Level: 2}))
+			Expect(calendar.Days[0].Level).To(BeZero())
+
+			resp = do(http.MethodGet, baseURL+"/api/stats/timeseries?metric=reviews&from=2024-03-09&to=2024-03-10", calendarLearner, "")
+			Expect(resp.StatusCode).To(Equal(http.StatusOK))
+			var series models.TimeSeries
+			Expect(json.NewDecoder(resp.Body).Decode(&series)).To(Succeed())
+			Expect(series.Points).To(HaveLen(2))
+			Expect(*series.Points[0].Value).To(Equal(2.0))
+			Expect(*series.Points[1].Value).To(Equal(1.0))
+		})
+
+		It("should keep a streak alive with a freeze", func() {
+			streakLearner := login("streak@example.com")
+			resp := do(http.MethodGet, baseURL+"/api/auth/me", streakLearner, "")
+			Expect(resp.StatusCode).To(Equal(http.StatusOK))
+			var user models.User
+			Expect(json.NewDecoder(resp.Body).Decode(&user)).To(Succeed())
+
+			// Eight days in a row that ended the day before yesterday earn a
+			// freeze, but today and yesterday without study broke the streak
+			y, m, d := time.Now().UTC().Date()
+			today := time.Date(y, m, d, 12, 0, 0, 0, time.UTC)
+			for n := 2; n < 10; n++ {
+				started := today.AddDate(0, 0, -n)
+				_, err := db.Exec(`
+					INSERT INTO study_sessions (user_id, group_id, created_at, state, last_activity_at, ended_at)
+					VALUES (?, ?, ?, 'completed', ?, ?)
+				`, user.ID, createdGroupID, started, started, started)
+				Expect(err).NotTo(HaveOccurred())
+			}
+
+			resp = do(http.MethodGet, baseURL+"/api/dashboard/streak", streakLearner, "")
+			Expect(resp.StatusCode).To(Equal(http.StatusOK))
+			var broken models.Streak
+			Expect(json.NewDecoder(resp.Body).Decode(&broken)).To(Succeed())
+			Expect(broken.Current).To(BeZero())
+			Expect(broken.Longest).To(Equal(8))
+			Expect(broken.Freezes).To(Equal(1))
+			Expect(broken.Today).To(Equal(today.Format("2006-01-02")))
+
+			yesterday := today.AddDate(0, 0, -1).Format("2006-01-02")
+			resp = do(http.MethodPost, baseURL+"/api/dashboard/streak/freeze", streakLearner, fmt.Sprintf(`{"day": %q}`, yesterday))
+			Expect(resp.StatusCode).To(Equal(http.StatusOK))
+			var frozen models.Streak
+			Expect(json.NewDecoder(resp.Body).Decode(&frozen)).To(Succeed())
+			Expect(frozen.Current).To(Equal(8))
+			Expect(frozen.Freezes).To(BeZero())
+			Expect(frozen.FrozenDays).To(Equal([]string{yesterday}))
+
+			resp = do(http.MethodPost, baseURL+"/api/dashboard/streak/freeze", streakLearner, fmt.Sprintf(`{"day": %q}`, today.Format("2006-01-02")))
+			Expect(resp.StatusCode).To(Equal(http.StatusConflict))
+
+			resp = do(http.MethodGet, baseURL+"/api/dashboard/quick_stats", streakLearner, "")
+			Expect(resp.StatusCode).To(Equal(http.StatusOK))
+			var stats models.DashboardStats
+			Expect(json.NewDecoder(resp.Body).Decode(&stats)).To(Succeed())
+			Expect(stats.StudyStreakDays).To(Equal(8))
+		})
+
+		It("should report the progress with a group", func() {
+			progressLearner := login("progress@example.com")
+			progressURL := fmt.Sprintf("%s/api/groups/%d/progress", baseURL, createdGroupID)
+			progress := func() models.GroupProgress {
+				resp := do(http.MethodGet, progressURL, progressLearner, "")
+				Expect(resp.StatusCode).To(Equal(http.StatusOK))
+				var progress models.GroupProgress
+				Expect(json.NewDecoder(resp.Body).Decode(&progress)).To(Succeed())
+				return progress
+			}
+
+			fresh := progress()
+			Expect(fresh.TotalWords).To(Equal(1))
+			Expect(fresh.NeverSeen).To(Equal(1))
+			Expect(fresh.Completed).To(BeFalse())
+			Expect(fresh.Accuracy).To(BeNil())
+			Expect(fresh.LastStudiedAt).To(BeNil())
+			Expect(fresh.EstimatedMinutes).To(BeNumerically(">", 0))
+
+			resp := do(http.MethodPost, baseURL+"/api/study_sessions", progressLearner, fmt.Sprintf(`{"group_id": %d}`, createdGroupID))
+			Expect(resp.StatusCode).To(Equal(http.StatusCreated))
+			var session models.StudySession
+			Expect(json.NewDecoder(resp.Body).Decode(&session)).To(Succeed())
+			resp = do(http.MethodGet, fmt.Sprintf("%s/api/study_sessions/%d/next", baseURL, session.ID), progressLearner, "")
+			Expect(resp.StatusCode).To(Equal(http.StatusOK))
+
+			url := fmt.Sprintf("%s/api/study_sessions/%d/reviews", baseURL, session.ID)
+			resp = do(http.MethodPost, url, progressLearner, fmt.Sprintf(`{"word_id": %d, "correct": false}`, createdWordID))
+			Expect(resp.StatusCode).To(Equal(http.StatusNoContent))
+
+			learning := progress()
+			Expect(learning.NeverSeen).To(BeZero())
+			Expect(learning.Learning).To(Equal(1))
+			Expect(*learning.Accuracy).To(BeZero())
+			Expect(learning.LastStudiedAt).NotTo(BeNil())
+
+			// Four correct answers lift the word to 80%
+			for range 4 {
+				resp = do(http.MethodPost, url, progressLearner, fmt.Sprintf(`{"word_id": %d, "correct": true}`, createdWordID))
+				Expect(resp.StatusCode).To(Equal(http.StatusNoContent))
+			}
+
+			mastered := progress()
+			Expect(mastered.Mastered).To(Equal(1))
+			Expect(mastered.Completed).To(BeTrue())
+			Expect(*mastered.Accuracy).To(Equal(80.0))
+			Expect(mastered.EstimatedMinutes).To(BeZero())
+
+			var page struct {
+				Items []models.Group `json:"items"`
+			}
+			resp = do(http.MethodGet, baseURL+"/api/groups", progressLearner, "")
+			Expect(resp.StatusCode).To(Equal(http.StatusOK))
+			Expect(json.NewDecoder(resp.Body).Decode(&page)).To(Succeed())
+			var listed *models.Group
+			for i := range page.Items {
+				if page.Items[i].ID == createdGroupID {
+					listed = &page.Items[i]
+				}
+			}
+			Expect(listed).NotTo(BeNil())
+			Expect(listed.Progress).To(Equal(&mastered))
+
+			resp, err := http.Get(baseURL + "/api/groups")
 			Expect(err).NotTo(HaveOccurred())
+			Expect(resp.StatusCode).To(Equal(http.StatusOK))
+			var anonymous struct {
+				Items []models.Group `json:"items"`
+			}
+			Expect(json.NewDecoder(resp.Body).Decode(&anonymous)).To(Succeed())
+			Expect(anonymous.Items).NotTo(BeEmpty())
+			for _, group := range anonymous.Items {
+				Expect(group.Progress).To(BeNil())
+			}
+		})
+
+		It("should drill articles and report the most confused gender", func() {
+			resp := do(http.MethodPost, baseURL+"/api/groups", admin, `{"name":"Furniture","description":"Nouns for the article drill"}`)
+			Expect(resp.StatusCode).To(Equal(http.StatusCreated))
+			var group models.Group
+			Expect(json.NewDecoder(resp.Body).Decode(&group)).To(Succeed())
+
+			nouns := map[string]int{}
+			for german, article := range map[string]string{"Tisch": "der", "Lampe": "die"} {
+				body := fmt.Sprintf(`{"german":%q,"english":"furniture","parts":"{\"article\":\"%s\"}"}`, german, article)
+				resp = do(http.MethodPost, baseURL+"/api/words", admin, body)
+				Expect(resp.StatusCode).To(Equal(http.StatusOK))
+				var word models.Word
+				Expect(json.NewDecoder(resp.Body).Decode(&word)).To(Succeed())
+				nouns[german] = word.ID
+
+				resp = do(http.MethodPost, fmt.Sprintf("%s/api/groups/%d/words", baseURL, group.ID), admin, fmt.Sprintf(`{"word_id": %d}`, word.ID))
+				Expect(resp.StatusCode).To(Equal(http.StatusOK))
+			}
+
+			drillLearner := login("articles@example.com")
+			resp = do(http.MethodPost, baseURL+"/api/study_sessions", drillLearner, fmt.Sprintf(`{"group_id": %d}`, group.ID))
+			Expect(resp.StatusCode).To(Equal(http.StatusCreated))
+			var session models.StudySession
+			Expect(json.NewDecoder(resp.Body).Decode(&session)).To(Succeed())
+
+			// Answer der for every noun, which is wrong for Lampe only
+			counted := testutil.ToFloat64(metrics.ReviewsRecorded.WithLabelValues(models.DrillArticle, "incorrect"))
+			questionURL := fmt.Sprintf("%s/api/study_sessions/%d/questions?type=article", baseURL, session.ID)
+			for range nouns {
+				resp = do(http.MethodGet, questionURL, drillLearner, "")
+				Expect(resp.StatusCode).To(Equal(http.StatusOK))
+				var question quiz.Question
+				Expect(json.NewDecoder(resp.Body).Decode(&question)).To(Succeed())
+				Expect(question.Articles).To(Equal([]string{"der", "die", "das"}))
+
+				url := fmt.Sprintf("%s/api/study_sessions/%d/article_reviews", baseURL, session.ID)
+				resp = do(http.MethodPost, url, drillLearner, fmt.Sprintf(`{"word_id": %d, "article": "der"}`, question.WordID))
+				Expect(resp.StatusCode).To(Equal(http.StatusCreated))
+				var review models.ArticleReview
+				Expect(json.NewDecoder(resp.Body).Decode(&review)).To(Succeed())
+				Expect(review.Correct).To(Equal(question.WordID == nouns["Tisch"]))
+			}
+			resp = do(http.MethodGet, questionURL, drillLearner, "")
+			Expect(resp.StatusCode).To(Equal(http.StatusNoContent))
+			Expect(testutil.ToFloat64(metrics.ReviewsRecorded.WithLabelValues(models.DrillArticle, "incorrect"))).To(Equal(counted + 1))
+
+			resp = do(http.MethodGet, baseURL+"/api/dashboard/articles", drillLearner, "")
+			Expect(resp.StatusCode).To(Equal(http.StatusOK))
+			var breakdown models.ArticleBreakdown
+			Expect(json.NewDecoder(resp.Body).Decode(&breakdown)).To(Succeed())
+			Expect(breakdown.MostConfused).To(HaveValue(Equal("die")))
+			Expect(breakdown.Articles).To(ContainElement(models.ArticleStats{
+				Article: "die", Total: 1, Correct: 0, Accuracy: 0, ChosenInstead: map[string]int{"der": 1},
+			}))
+			Expect(breakdown.Nouns).To(HaveLen(2))
+			Expect(breakdown.Nouns[0].German).To(Equal("Lampe"))
+
+			// Words without an article are never drilled
+			resp = do(http.MethodPost, baseURL+"/api/study_sessions", drillLearner, fmt.Sprintf(`{"group_id": %d}`, createdGroupID))
+			Expect(resp.StatusCode).To(Equal(http.StatusCreated))
+			Expect(json.NewDecoder(resp.Body).Decode(&session)).To(Succeed())
+			resp = do(http.MethodGet, fmt.Sprintf("%s/api/study_sessions/%d/questions?type=article", baseURL, session.ID), drillLearner, "")
 			Expect(resp.StatusCode).To(Equal(http.StatusNoContent))
 		})
+
+		It("should drill plurals and report the weakest pattern", func() {
+			resp := do(http.MethodPost, baseURL+"/api/groups", admin, `{"name":"Bedroom","description":"Nouns for the plural drill"}`)
+			Expect(resp.StatusCode).To(Equal(http.StatusCreated))
+			var group models.Group
+			Expect(json.NewDecoder(resp.Body).Decode(&group)).To(Succeed())
+
+			answers := map[int]string{}
+			for _, noun := range []struct{ german, parts, answer string }{
+				{"Stuhl", `{\"article\":\"der\",\"plural\":\"Stühle\"}`, "Stühle"},
+				{"Bett", `{\"article\":\"das\",\"plural\":\"Betten\"}`, "Bette"},
+			} {
+				resp = do(http.MethodPost, baseURL+"/api/words", admin, fmt.Sprintf(`{"german":%q,"english":"furniture","parts":"%s"}`, noun.german, noun.parts))
+				Expect(resp.StatusCode).To(Equal(http.StatusOK))
+				var word models.Word
+				Expect(json.NewDecoder(resp.Body).Decode(&word)).To(Succeed())
+				answers[word.ID] = noun.answer
+
+				resp = do(http.MethodPost, fmt.Sprintf("%s/api/groups/%d/words", baseURL, group.ID), admin, fmt.Sprintf(`{"word_id": %d}`, word.ID))
+				Expect(resp.StatusCode).To(Equal(http.StatusOK))
+			}
+
+			drillLearner := login("plurals@example.com")
+			resp = do(http.MethodPost, baseURL+"/api/study_sessions", drillLearner, fmt.Sprintf(`{"group_id": %d}`, group.ID))
+			Expect(resp.StatusCode).To(Equal(http.StatusCreated))
+			var session models.StudySession
+			Expect(json.NewDecoder(resp.Body).Decode(&session)).To(Succeed())
+
+			for range answers {
+				resp = do(http.MethodGet, fmt.Sprintf("%s/api/study_sessions/%d/questions?type=plural", baseURL, session.ID), drillLearner, "")
+				Expect(resp.StatusCode).To(Equal(http.StatusOK))
+				var question quiz.Question
+				Expect(json.NewDecoder(resp.Body).Decode(&question)).To(Succeed())
+				Expect(question.Prompt).To(BeElementOf("der Stuhl", "das Bett"))
+
+				url := fmt.Sprintf("%s/api/study_sessions/%d/plural_reviews", baseURL, session.ID)
+				resp = do(http.MethodPost, url, drillLearner, fmt.Sprintf(`{"word_id": %d, "answer": %q}`, question.WordID, answers[question.WordID]))
+				Expect(resp.StatusCode).To(Equal(http.StatusCreated))
+			}
+
+			resp = do(http.MethodGet, baseURL+"/api/dashboard/plurals", drillLearner, "")
+			Expect(resp.StatusCode).To(Equal(http.StatusOK))
+			var breakdown models.PluralBreakdown
+			Expect(json.NewDecoder(resp.Body).Decode(&breakdown)).To(Succeed())
+			Expect(breakdown.WeakestPattern).To(HaveValue(Equal(quiz.PluralEn)))
+			Expect(breakdown.Patterns).To(ContainElements(
+				models.PatternStats{Pattern: quiz.PluralE, Total: 1, Correct: 1, Accuracy: 100, AppliedInstead: map[string]int{}},
+				models.PatternStats{Pattern: quiz.PluralEn, Total: 1, Correct: 0, Accuracy: 0, AppliedInstead: map[string]int{quiz.PluralE: 1}},
+			))
+		})
 	})
 
 	Context("Dashboard Flow", func() {
 		It("should get study progress", func() {
-			resp, err := http.Get(baseURL + "/api/dashboard/study_progress")
-			Expect(err).NotTo(HaveOccurred())
+			resp := do(http.MethodGet, baseURL+"/api/dashboard/study_progress", learner, "")
 			Expect(resp.StatusCode).To(Equal(http.StatusOK))
 
 			var progress models.StudyProgress
-			err = json.NewDecoder(resp.Body).Decode(&progress)
+			err := json.NewDecoder(resp.Body).Decode(&progress)
 			Expect(err).NotTo(HaveOccurred())
 			Expect(progress.TotalWordsStudied).To(BeNumerically(">", 0))
 		})
 
 		It("should get quick stats", func() {
-			resp, err := http.Get(baseURL + "/api/dashboard/quick_stats")
-			Expect(err).NotTo(HaveOccurred())
+			resp := do(http.MethodGet, baseURL+"/api/dashboard/quick_stats", learner, "")
 			Expect(resp.StatusCode).To(Equal(http.StatusOK))
 
 			var stats models.DashboardStats
-			err = json.NewDecoder(resp.Body).Decode(&stats)
+			err := json.NewDecoder(resp.Body).Decode(&stats)
 			Expect(err).NotTo(HaveOccurred())
 			Expect(stats.TotalStudySessions).To(BeNumerically(">", 0))
 		})
 
 		It("should get last study session", func() {
-			resp, err := http.Get(baseURL + "/api/dashboard/last_study_session")
-			Expect(err).NotTo(HaveOccurred())
+			resp := do(http.MethodGet, baseURL+"/api/dashboard/last_study_session", learner, "")
 			Expect(resp.StatusCode).To(Equal(http.StatusOK))
 
 			var session models.StudySession
-			err = json.NewDecoder(resp.Body).Decode(&session)
+			err := json.NewDecoder(resp.Body).Decode(&session)
 			Expect(err).NotTo(HaveOccurred())
 			Expect(session.ID).To(Equal(studySessionID))
 		})
+
+		It("should keep another learner's dashboard empty", func() {
+			resp := do(http.MethodGet, baseURL+"/api/dashboard/last_study_session", otherLearner, "")
+			Expect(resp.StatusCode).To(Equal(http.StatusNotFound))
+
+			resp = do(http.MethodGet, baseURL+"/api/dashboard/quick_stats", otherLearner, "")
+			Expect(resp.StatusCode).To(Equal(http.StatusOK))
+
+			var stats models.DashboardStats
+			Expect(json.NewDecoder(resp.Body).Decode(&stats)).To(Succeed())
+			Expect(stats.TotalStudySessions).To(BeZero())
+			Expect(stats.StudyStreakDays).To(BeZero())
+			Expect(stats.TotalWords).To(BeNumerically(">", 0))
+		})
 	})
 
 	Context("Cleanup Flow", func() {
@@ -227,6 +807,7 @@ var _ = Describe("API E2E Tests", func() {
 			url := fmt.Sprintf("%s/api/groups/%d/words/%d", baseURL, createdGroupID, createdWordID)
 			req, err := http.NewRequest(http.MethodDelete, url, nil)
 			Expect(err).NotTo(HaveOccurred())
+			req.Header.Set("Authorization", admin)
 			
 			resp, err := http.DefaultClient.Do(req)
 			Expect(err).NotTo(HaveOccurred())
@@ -237,6 +818,7 @@ var _ = Describe("API E2E Tests", func() {
 			url := fmt.Sprintf("%s/api/groups/%d", baseURL, createdGroupID)
 			req, err := http.NewRequest(http.MethodDelete, url, nil)
 			Expect(err).NotTo(HaveOccurred())
+			req.Header.Set("Authorization", admin)
 			
 			resp, err := http.DefaultClient.Do(req)
 			Expect(err).NotTo(HaveOccurred())
@@ -247,6 +829,7 @@ var _ = Describe("API E2E Tests", func() {
 			url := fmt.Sprintf("%s/api/words/%d", baseURL, createdWordID)
 			req, err := http.NewRequest(http.MethodDelete, url, nil)
 			Expect(err).NotTo(HaveOccurred())
+			req.Header.Set("Authorization", admin)
 			
 			resp, err := http.DefaultClient.Do(req)
 			Expect(err).NotTo(HaveOccurred())
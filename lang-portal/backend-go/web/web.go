@@ -0,0 +1,20 @@
+// Package web embeds the built frontend so the API binary can serve it
+// without any files next to it.
+//
+// Build the frontend into web/dist before compiling the server; the
+// placeholder page committed there keeps the package building without one.
+package web
+
+import (
+	"embed"
+	"io/fs"
+)
+
+//go:embed all:dist
+var dist embed.FS
+
+// Dist returns the embedded frontend bundle.
+func Dist() fs.FS {
+	sub, _ := fs.Sub(dist, "dist")
+	return sub
+}